@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/similarity"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin guards maintenance endpoints with the ADMIN_TOKEN bearer
+// token. When ADMIN_TOKEN is unset the endpoints are disabled entirely.
+func RequireAdmin() gin.HandlerFunc {
+	token := os.Getenv("ADMIN_TOKEN")
+
+	return func(c *gin.Context) {
+		if token == "" {
+			problem(c, http.StatusForbidden, CodeAdminDisabled, "Admin endpoints are disabled")
+			return
+		}
+
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			problem(c, http.StatusUnauthorized, CodeUnauthorized, "Invalid admin token")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AdminHandler serves the maintenance endpoints behind RequireAdmin.
+type AdminHandler struct {
+	leaderboard database.LeaderboardRepository
+	scanner     *similarity.Scanner
+}
+
+func NewAdminHandler(leaderboard database.LeaderboardRepository, scanner *similarity.Scanner) *AdminHandler {
+	return &AdminHandler{leaderboard: leaderboard, scanner: scanner}
+}
+
+func (h *AdminHandler) RebuildLeaderboard(c *gin.Context) {
+	replayed, err := h.leaderboard.RebuildLeaderboard(c.Request.Context())
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to rebuild leaderboard")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Leaderboard rebuilt from reputation events",
+		"events_replayed": replayed,
+	})
+}
+
+func (h *AdminHandler) CheckLeaderboardConsistency(c *gin.Context) {
+	report, err := h.leaderboard.CheckLeaderboardConsistency(c.Request.Context())
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to check leaderboard consistency")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// RescanSimilarity fingerprints a challenge's submissions again, for
+// submissions stored before similarity detection or after a threshold
+// change.
+func (h *AdminHandler) RescanSimilarity(c *gin.Context) {
+	address, ok := addressParam(c, "address")
+	if !ok {
+		return
+	}
+
+	scanned, err := h.scanner.Rescan(c.Request.Context(), address)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to rescan submissions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":             "Similarity flags recomputed",
+		"submissions_scanned": scanned,
+	})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cyrup/backend/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+const walletContextKey = "wallet"
+
+// WalletAuth identifies the caller from the X-Wallet-Address,
+// X-Wallet-Signature and X-Wallet-Timestamp headers, where the signature is
+// a personal_sign over auth.Message(address, timestamp). Requests without
+// the headers continue anonymously; requests with a bad signature are
+// rejected rather than silently downgraded.
+func WalletAuth(verifier *auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address := c.GetHeader("X-Wallet-Address")
+		signature := c.GetHeader("X-Wallet-Signature")
+		if address == "" && signature == "" {
+			c.Next()
+			return
+		}
+
+		wallet, err := verifier.Verify(address, signature, c.GetHeader("X-Wallet-Timestamp"))
+		if err != nil {
+			problem(c, http.StatusUnauthorized, CodeSignatureInvalid, err.Error())
+			return
+		}
+
+		c.Set(walletContextKey, wallet)
+		c.Next()
+	}
+}
+
+// authenticatedWallet returns the caller's lowercase wallet address, or ""
+// for anonymous requests.
+func authenticatedWallet(c *gin.Context) string {
+	return c.GetString(walletContextKey)
+}
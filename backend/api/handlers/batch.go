@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cyrup/backend/api/models"
+	"github.com/cyrup/backend/api/services"
+	"github.com/cyrup/backend/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// batch is a group of proofs verified together. Each item's result is kept
+// in the handler's results under its own ID, so /api/status/:id and
+// /api/result/:id work for batch items too.
+type batch struct {
+	id        string
+	createdAt time.Time
+	labels    []string
+	itemIDs   []string
+}
+
+// timeoutSeconds converts a request's timeout in milliseconds to the lean
+// runner's seconds, falling back to 30 for missing or out-of-range values.
+func timeoutSeconds(milliseconds int) int {
+	if milliseconds > 0 && milliseconds <= 60000 {
+		return milliseconds / 1000
+	}
+	return 30
+}
+
+// VerifyBatch queues many proofs at once. Items go to the queue's batch
+// lane, which takes turns with single verifications, so a large batch
+// slows down other work only by its fair share of the workers.
+func (h *LeanHandler) VerifyBatch(c *gin.Context) {
+	var req models.BatchVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingProblem(c, err)
+		return
+	}
+	if len(req.Items) > h.maxBatchItems {
+		problemWith(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, fmt.Sprintf("Batch has %d items; the limit is %d", len(req.Items), h.maxBatchItems), gin.H{
+			"limit": h.maxBatchItems,
+		})
+		return
+	}
+	jobs := make([]services.LeanVerifyRequest, len(req.Items))
+	for i, item := range req.Items {
+		job, invalid := h.buildJob(item.VerifyRequest)
+		if invalid != nil {
+			if invalid.extra == nil {
+				invalid.extra = gin.H{}
+			}
+			if fields, ok := invalid.extra["errors"].([]FieldError); ok {
+				for j := range fields {
+					fields[j].Field = fmt.Sprintf("items[%d].%s", i, fields[j].Field)
+				}
+			}
+			invalid.detail = fmt.Sprintf("Item %d: %s", i, invalid.detail)
+			invalid.extra["index"] = i
+			invalid.write(c)
+			return
+		}
+		jobs[i] = job
+	}
+
+	// Every item runs on the lean runner, so each one is charged.
+	if !chargeRateLimit(c, h.limiter, ratelimit.BudgetBatch, len(req.Items)) {
+		return
+	}
+
+	now := time.Now()
+	b := &batch{
+		id:        uuid.NewString(),
+		createdAt: now,
+		labels:    make([]string, len(req.Items)),
+		itemIDs:   make([]string, len(req.Items)),
+	}
+	items := make([]models.BatchItem, len(req.Items))
+	runs := make([]func(), len(req.Items))
+
+	owner := authenticatedWallet(c)
+	h.mu.Lock()
+	for i, item := range req.Items {
+		id := uuid.NewString()
+		result := &models.ProofResult{ID: id, Status: models.StatusQueued, CreatedAt: now}
+		h.results[id] = result
+
+		b.labels[i] = item.Label
+		b.itemIDs[i] = id
+		items[i] = models.BatchItem{Index: i, Label: item.Label, ProofResult: *result}
+
+		job := jobs[i]
+		runs[i] = func() { h.processProof(id, owner, job) }
+	}
+	h.batches[b.id] = b
+	h.mu.Unlock()
+
+	h.queue.SubmitBatch(b.id, runs...)
+
+	c.JSON(http.StatusAccepted, models.BatchResponse{
+		ID:        b.id,
+		Status:    models.BatchQueued,
+		Progress:  models.BatchProgress{Total: len(items), Queued: len(items)},
+		CreatedAt: now,
+		Items:     items,
+	})
+}
+
+// GetBatch reports a batch's progress.
+func (h *LeanHandler) GetBatch(c *gin.Context) {
+	b, items, ok := h.batchItems(c)
+	if !ok {
+		return
+	}
+
+	response := models.BatchResponse{
+		ID:        b.id,
+		Status:    models.BatchCompleted,
+		Progress:  models.BatchProgress{Total: len(items)},
+		CreatedAt: b.createdAt,
+	}
+	var completedAt time.Time
+	for _, item := range items {
+		switch item.Status {
+		case models.StatusQueued:
+			response.Progress.Queued++
+		case models.StatusProcessing:
+			response.Progress.Processing++
+		case models.StatusSuccess:
+			response.Progress.Succeeded++
+		case models.StatusError:
+			response.Progress.Failed++
+		case models.StatusTimeout:
+			response.Progress.TimedOut++
+		}
+		if item.CompletedAt != nil && item.CompletedAt.After(completedAt) {
+			completedAt = *item.CompletedAt
+		}
+	}
+
+	switch {
+	case response.Progress.Queued == len(items):
+		response.Status = models.BatchQueued
+	case response.Progress.Queued > 0 || response.Progress.Processing > 0:
+		response.Status = models.BatchRunning
+	default:
+		response.CompletedAt = &completedAt
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListBatchItems lists a batch's items with their results in submission
+// order, paged by limit and offset and optionally filtered by ?status=.
+func (h *LeanHandler) ListBatchItems(c *gin.Context) {
+	status := models.ProofStatus(c.Query("status"))
+	switch status {
+	case "", models.StatusQueued, models.StatusProcessing, models.StatusSuccess, models.StatusError, models.StatusTimeout:
+	default:
+		problem(c, http.StatusBadRequest, CodeInvalidQuery, "status must be queued, processing, success, error or timeout")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	b, items, ok := h.batchItems(c)
+	if !ok {
+		return
+	}
+
+	matching := []models.BatchItem{}
+	for _, item := range items {
+		if status == "" || item.Status == status {
+			matching = append(matching, item)
+		}
+	}
+	if offset > len(matching) {
+		offset = len(matching)
+	}
+	page := matching[offset:]
+	if len(page) > limit {
+		page = page[:limit]
+	}
+
+	list(c, page, offsetPage(limit, offset), gin.H{"batch_id": b.id})
+}
+
+// batchItems looks up the batch named by :id and snapshots its items,
+// writing a 404 if there is no such batch.
+func (h *LeanHandler) batchItems(c *gin.Context) (*batch, []models.BatchItem, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	b, exists := h.batches[c.Param("id")]
+	if !exists {
+		problem(c, http.StatusNotFound, CodeNotFound, "Batch not found")
+		return nil, nil, false
+	}
+
+	items := make([]models.BatchItem, len(b.itemIDs))
+	for i, id := range b.itemIDs {
+		items[i] = models.BatchItem{Index: i, Label: b.labels[i], ProofResult: *h.results[id]}
+	}
+	return b, items, true
+}
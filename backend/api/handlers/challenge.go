@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/money"
+	"github.com/cyrup/backend/internal/scheduler"
+	"github.com/cyrup/backend/internal/validate"
+	"github.com/cyrup/backend/internal/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+// ChallengeHandler indexes ChallengeEscrow events and serves the resulting
+// challenge state, deadlines and lifecycle notifications.
+type ChallengeHandler struct {
+	challenges database.ChallengeRepository
+	jobs       database.JobRepository
+	scheduler  *scheduler.Scheduler
+	publisher  *webhook.Publisher
+}
+
+func NewChallengeHandler(challenges database.ChallengeRepository, jobs database.JobRepository, scheduler *scheduler.Scheduler, publisher *webhook.Publisher) *ChallengeHandler {
+	return &ChallengeHandler{challenges: challenges, jobs: jobs, scheduler: scheduler, publisher: publisher}
+}
+
+type ChallengeEventRequest struct {
+	ChallengeAddress string `json:"challenge_address" binding:"required,address"`
+	Event            string `json:"event" binding:"required,oneof=ChallengeCreated VerifierSelected RewardsDistributed ChallengeCancelled"`
+	ChallengeID      *int64 `json:"challenge_id,omitempty"`
+	Creator          string `json:"creator,omitempty" binding:"omitempty,address"`
+	Verifier         string `json:"verifier,omitempty" binding:"omitempty,address"`
+	TokenAddress     string `json:"token_address,omitempty" binding:"omitempty,address"`
+	Reward           string `json:"reward,omitempty"`
+	Deadline         int64  `json:"deadline,omitempty"`
+}
+
+// RecordChallengeEvent ingests a ChallengeCreated, VerifierSelected,
+// RewardsDistributed or ChallengeCancelled event. Status only moves
+// forward, so events may arrive in any order and repeats are no-ops.
+func (h *ChallengeHandler) RecordChallengeEvent(c *gin.Context) {
+	var req ChallengeEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingProblem(c, err)
+		return
+	}
+
+	event := database.ChallengeEvent{
+		ChallengeAddress: strings.ToLower(req.ChallengeAddress),
+		Event:            req.Event,
+		ChallengeID:      req.ChallengeID,
+		Creator:          strings.ToLower(req.Creator),
+		Verifier:         strings.ToLower(req.Verifier),
+		TokenAddress:     strings.ToLower(req.TokenAddress),
+	}
+
+	switch req.Event {
+	case database.EventChallengeCreated:
+		if req.Creator == "" {
+			problem(c, http.StatusBadRequest, CodeInvalidRequest, "creator is required for ChallengeCreated")
+			return
+		}
+		if req.Reward != "" {
+			reward, ok := new(big.Int).SetString(req.Reward, 10)
+			if !ok || reward.Sign() < 0 {
+				problem(c, http.StatusBadRequest, CodeInvalidRequest, "reward must be a non-negative integer in token base units")
+				return
+			}
+			amount := money.FromUnits(reward, 0)
+			event.RewardRaw = &amount
+		}
+		if req.Deadline > 0 {
+			deadline := time.Unix(req.Deadline, 0).UTC()
+			event.Deadline = &deadline
+		}
+	case database.EventVerifierSelected:
+		if req.Verifier == "" {
+			problem(c, http.StatusBadRequest, CodeInvalidRequest, "verifier is required for VerifierSelected")
+			return
+		}
+	}
+
+	challenge, changed, err := h.challenges.RecordChallengeEvent(c.Request.Context(), event)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to record challenge event")
+		return
+	}
+
+	if changed {
+		if err := h.scheduler.ScheduleChallenge(c.Request.Context(), challenge); err != nil {
+			log.Printf("Warning: failed to schedule deadline jobs for %s: %v", challenge.Address, err)
+		}
+		publish(c.Request.Context(), h.publisher, webhook.EventChallengeStateChanged, gin.H{
+			"event":     req.Event,
+			"challenge": challenge,
+		})
+	}
+
+	status := http.StatusOK
+	if changed {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"challenge": challenge,
+		"changed":   changed,
+	})
+}
+
+// ListChallenges pages through indexed challenges, newest first, optionally
+// filtered by ?status=.
+func (h *ChallengeHandler) ListChallenges(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", database.ChallengeOpen, database.ChallengeActive, database.ChallengeCompleted, database.ChallengeCancelled:
+	default:
+		problem(c, http.StatusBadRequest, CodeInvalidQuery, "status must be open, active, completed or cancelled")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	challenges, err := h.challenges.ListChallenges(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenges")
+		return
+	}
+
+	list(c, challenges, offsetPage(limit, offset), nil)
+}
+
+func (h *ChallengeHandler) GetChallenge(c *gin.Context) {
+	address, ok := addressParam(c, "address")
+	if !ok {
+		return
+	}
+
+	challenge, err := h.challenges.GetChallenge(c.Request.Context(), address)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenge")
+		return
+	}
+
+	if challenge == nil {
+		problem(c, http.StatusNotFound, CodeNotFound, "Challenge not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
+}
+
+// GetSchedule lists the deadline jobs of a challenge and their state.
+func (h *ChallengeHandler) GetSchedule(c *gin.Context) {
+	address, ok := addressParam(c, "address")
+	if !ok {
+		return
+	}
+
+	jobs, err := h.jobs.ListJobs(c.Request.Context(), address)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch schedule")
+		return
+	}
+
+	list(c, jobs, Page{}, nil)
+}
+
+// ListUpcomingDeadlines returns open and active challenges whose deadline
+// falls within ?within= (a Go duration, default 168h), soonest first.
+func (h *ChallengeHandler) ListUpcomingDeadlines(c *gin.Context) {
+	within, err := time.ParseDuration(c.DefaultQuery("within", "168h"))
+	if err != nil || within <= 0 {
+		problem(c, http.StatusBadRequest, CodeInvalidQuery, "within must be a positive duration such as 24h")
+		return
+	}
+
+	now := time.Now()
+	challenges, err := h.challenges.ListChallengesWithDeadlines(c.Request.Context(), now)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch deadlines")
+		return
+	}
+
+	upcoming := []database.Challenge{}
+	for _, challenge := range challenges {
+		if challenge.Deadline.After(now.Add(within)) {
+			break
+		}
+		upcoming = append(upcoming, challenge)
+	}
+
+	list(c, upcoming, Page{}, gin.H{"within": within.String()})
+}
+
+// ListNotifications returns lifecycle notifications, newest first,
+// optionally narrowed with ?challenge=.
+func (h *ChallengeHandler) ListNotifications(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	challenge := c.Query("challenge")
+	if challenge != "" {
+		if challenge, err = validate.Address(challenge); err != nil {
+			problem(c, http.StatusBadRequest, CodeInvalidQuery, "challenge "+err.Error())
+			return
+		}
+	}
+
+	notifications, err := h.jobs.ListNotifications(c.Request.Context(), challenge, limit)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch notifications")
+		return
+	}
+
+	list(c, notifications, Page{Limit: limit}, nil)
+}
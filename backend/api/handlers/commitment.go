@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cyrup/backend/api/services"
+	"github.com/cyrup/backend/internal/commitment"
+	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/similarity"
+	"github.com/gin-gonic/gin"
+)
+
+// CommitmentHandler runs the commit-reveal submission flow. Solvers commit
+// to a salted hash while the challenge accepts submissions and reveal the
+// code once its deadline has passed; among valid reveals, the earliest
+// commitment has priority.
+type CommitmentHandler struct {
+	commitments database.CommitmentRepository
+	challenges  database.ChallengeRepository
+	scanner     *similarity.Scanner
+	checker     *services.SubmissionChecker
+	maxCodeSize int
+}
+
+func NewCommitmentHandler(commitments database.CommitmentRepository, challenges database.ChallengeRepository, scanner *similarity.Scanner, checker *services.SubmissionChecker, maxCodeSize int) *CommitmentHandler {
+	return &CommitmentHandler{commitments: commitments, challenges: challenges, scanner: scanner, checker: checker, maxCodeSize: maxCodeSize}
+}
+
+type CommitRequest struct {
+	ChallengeAddress string `json:"challenge_address" binding:"required,address"`
+	Commitment       string `json:"commitment" binding:"required"`
+}
+
+// Commit registers the authenticated wallet's commitment. It is refused
+// once the challenge's deadline has passed or the challenge has closed.
+func (h *CommitmentHandler) Commit(c *gin.Context) {
+	wallet := authenticatedWallet(c)
+	if wallet == "" {
+		problem(c, http.StatusUnauthorized, CodeUnauthorized, "A signed wallet is required to commit")
+		return
+	}
+
+	var req CommitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingProblem(c, err)
+		return
+	}
+	if !commitment.Valid(req.Commitment) {
+		problem(c, http.StatusBadRequest, CodeInvalidRequest, "commitment must be a 0x-prefixed 32-byte hash")
+		return
+	}
+
+	challengeAddress := strings.ToLower(req.ChallengeAddress)
+	challenge, err := h.challenges.GetChallenge(c.Request.Context(), challengeAddress)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenge")
+		return
+	}
+	if challenge != nil && (challenge.Status == database.ChallengeCompleted || challenge.Status == database.ChallengeCancelled) {
+		problem(c, http.StatusConflict, CodeChallengeClosed, "Challenge is closed")
+		return
+	}
+	if challenge != nil && challenge.Deadline != nil && !time.Now().Before(*challenge.Deadline) {
+		problem(c, http.StatusConflict, CodeWindowClosed, "Submission window has closed")
+		return
+	}
+
+	record := &database.Commitment{
+		ChallengeAddress: challengeAddress,
+		WalletAddress:    wallet,
+		Commitment:       strings.ToLower(req.Commitment),
+	}
+
+	inserted, err := h.commitments.CreateCommitment(c.Request.Context(), record)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to record commitment")
+		return
+	}
+	if !inserted {
+		problem(c, http.StatusConflict, CodeAlreadyExists, "Commitment already registered")
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+type RevealRequest struct {
+	Commitment   string `json:"commitment" binding:"required"`
+	UID          string `json:"uid" binding:"required,uid"`
+	SolutionCode string `json:"solution_code" binding:"required"`
+	Salt         string `json:"salt" binding:"required"`
+}
+
+// Reveal checks the code and salt against the caller's commitment and, if
+// they match, records the submission. Reveals open at the challenge
+// deadline, which must already be indexed.
+func (h *CommitmentHandler) Reveal(c *gin.Context) {
+	wallet := authenticatedWallet(c)
+	if wallet == "" {
+		problem(c, http.StatusUnauthorized, CodeUnauthorized, "A signed wallet is required to reveal")
+		return
+	}
+
+	var req RevealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingProblem(c, err)
+		return
+	}
+	if !checkCodeSize(c, req.SolutionCode, h.maxCodeSize) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	hash := strings.ToLower(req.Commitment)
+	record, err := h.commitments.GetCommitment(ctx, hash)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch commitment")
+		return
+	}
+	if record == nil {
+		problem(c, http.StatusNotFound, CodeNotFound, "Commitment not found")
+		return
+	}
+	if record.WalletAddress != wallet {
+		problem(c, http.StatusForbidden, CodeForbidden, "Commitment belongs to another wallet")
+		return
+	}
+	if record.RevealedAt != nil {
+		problem(c, http.StatusConflict, CodeAlreadyRevealed, "Commitment already revealed")
+		return
+	}
+
+	challenge, err := h.challenges.GetChallenge(ctx, record.ChallengeAddress)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenge")
+		return
+	}
+	if challenge == nil || challenge.Deadline == nil {
+		problem(c, http.StatusConflict, CodeRevealNotOpen, "Challenge deadline is not indexed yet")
+		return
+	}
+	if time.Now().Before(*challenge.Deadline) {
+		problemWith(c, http.StatusConflict, CodeRevealNotOpen, "Reveals open when the submission window closes", gin.H{
+			"opens_at": challenge.Deadline,
+		})
+		return
+	}
+
+	expected, err := commitment.Hash(req.SolutionCode, req.Salt, wallet)
+	if err != nil {
+		problem(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if expected != hash {
+		problem(c, http.StatusUnprocessableEntity, CodeMismatch, "Code and salt do not match the commitment")
+		return
+	}
+
+	submission := &database.Submission{
+		UID:              req.UID,
+		ChallengeAddress: record.ChallengeAddress,
+		WalletAddress:    wallet,
+		SolutionCode:     req.SolutionCode,
+		Status:           database.SubmissionPending,
+	}
+
+	revealed, err := h.commitments.RevealCommitment(ctx, hash, submission)
+	if errors.Is(err, database.ErrSubmissionExists) {
+		problem(c, http.StatusConflict, CodeAlreadyExists, "A submission with this uid already exists")
+		return
+	}
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to record submission")
+		return
+	}
+	if !revealed {
+		problem(c, http.StatusConflict, CodeAlreadyRevealed, "Commitment already revealed")
+		return
+	}
+
+	processSubmission(c, h.scanner, h.checker, submission)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"submission":   submission,
+		"committed_at": record.CommittedAt,
+	})
+}
+
+// CommitmentView is a commitment with its priority among revealed
+// commitments for the same challenge. Priority is nil until revealed.
+type CommitmentView struct {
+	database.Commitment
+	Priority *int `json:"priority"`
+}
+
+// ListCommitments returns a challenge's commitments, earliest first.
+func (h *CommitmentHandler) ListCommitments(c *gin.Context) {
+	address, ok := addressParam(c, "address")
+	if !ok {
+		return
+	}
+
+	commitments, err := h.commitments.ListCommitments(c.Request.Context(), address)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch commitments")
+		return
+	}
+
+	views := make([]CommitmentView, 0, len(commitments))
+	revealed := 0
+	for _, record := range commitments {
+		view := CommitmentView{Commitment: record}
+		if record.RevealedAt != nil {
+			revealed++
+			priority := revealed
+			view.Priority = &priority
+		}
+		views = append(views, view)
+	}
+
+	list(c, views, Page{}, nil)
+}
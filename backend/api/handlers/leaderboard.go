@@ -1,14 +1,45 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
+	"math/big"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/money"
+	"github.com/cyrup/backend/internal/pricing"
+	"github.com/cyrup/backend/internal/reputation"
+	"github.com/cyrup/backend/internal/webhook"
 	"github.com/gin-gonic/gin"
 )
 
-func GetLeaderboard(c *gin.Context) {
+// LeaderboardHandler serves leaderboard rankings, either all-time or for a
+// rolling or seasonal time window.
+type LeaderboardHandler struct {
+	leaderboard database.LeaderboardRepository
+	reputation  database.ReputationRepository
+	seasons     []reputation.Season
+	tokens      *money.TokenRegistry
+	prices      pricing.PriceSource
+}
+
+// NewLeaderboardHandler wires the leaderboard endpoints. prices may be nil,
+// in which case user stats carry no USD totals.
+func NewLeaderboardHandler(repos *database.Repositories, seasons []reputation.Season, tokens *money.TokenRegistry, prices pricing.PriceSource) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboard: repos.Leaderboard,
+		reputation:  repos.Reputation,
+		seasons:     seasons,
+		tokens:      tokens,
+		prices:      prices,
+	}
+}
+
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "50")
 	offsetStr := c.DefaultQuery("offset", "0")
 	
@@ -25,45 +56,154 @@ func GetLeaderboard(c *gin.Context) {
 		offset = 0
 	}
 
-	entries, err := database.GetLeaderboard(limit, offset)
+	role := c.Query("role")
+	if role != "" && role != database.RoleSolver && role != database.RoleVerifier {
+		problem(c, http.StatusBadRequest, CodeInvalidQuery, "role must be solver or verifier")
+		return
+	}
+
+	if windowParam := c.Query("window"); windowParam != "" && windowParam != "all" {
+		window, err := reputation.ParseWindow(windowParam, h.seasons, time.Now())
+		if err != nil {
+			problem(c, http.StatusBadRequest, CodeInvalidQuery, err.Error())
+			return
+		}
+
+		entries, err := h.leaderboard.GetWindowedLeaderboard(c.Request.Context(), window.Start, window.End, role, limit, offset)
+		if err != nil {
+			problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch leaderboard")
+			return
+		}
+
+		list(c, entries, offsetPage(limit, offset), gin.H{
+			"window": window,
+			"role":   role,
+		})
+		return
+	}
+
+	var entries interface{}
+	switch role {
+	case database.RoleSolver:
+		entries, err = h.leaderboard.GetSolverLeaderboard(c.Request.Context(), limit, offset)
+	case database.RoleVerifier:
+		entries, err = h.leaderboard.GetVerifierLeaderboard(c.Request.Context(), limit, offset)
+	default:
+		entries, err = h.leaderboard.GetLeaderboard(c.Request.Context(), limit, offset)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leaderboard"})
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch leaderboard")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"leaderboard": entries,
-		"limit":       limit,
-		"offset":      offset,
-	})
+	list(c, entries, offsetPage(limit, offset), gin.H{"role": role})
+}
+
+func (h *LeaderboardHandler) ListSeasons(c *gin.Context) {
+	seasons := h.seasons
+	if seasons == nil {
+		seasons = []reputation.Season{}
+	}
+
+	list(c, seasons, Page{}, nil)
 }
 
-func GetUserStats(c *gin.Context) {
-	walletAddress := c.Param("wallet")
+func (h *LeaderboardHandler) GetUserStats(c *gin.Context) {
+	walletAddress, ok := addressParam(c, "wallet")
+	if !ok {
+		return
+	}
 	
-	stats, err := database.GetUserStats(walletAddress)
+	stats, err := h.leaderboard.GetUserStats(c.Request.Context(), walletAddress)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user stats"})
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch user stats")
 		return
 	}
 	
 	if stats == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		problem(c, http.StatusNotFound, CodeNotFound, "User not found")
 		return
 	}
 
-	position, err := database.GetLeaderboardPosition(walletAddress)
+	position, err := h.leaderboard.GetLeaderboardPosition(c.Request.Context(), walletAddress)
 	if err != nil {
 		position = 0
 	}
 
+	winnings, err := h.leaderboard.GetTokenWinnings(c.Request.Context(), walletAddress)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch token winnings")
+		return
+	}
+
+	breakdown, totalUSD, unpriced, err := h.priceWinnings(c.Request.Context(), winnings)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to price token winnings")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"stats":    stats,
-		"position": position,
+		"stats":           stats,
+		"position":        position,
+		"winnings":        breakdown,
+		"total_usd":       totalUSD,
+		"unpriced_tokens": unpriced,
 	})
 }
 
-func GetTopPerformers(c *gin.Context) {
+// TokenWinningsView is a wallet's winnings in one token with the token's
+// registry details. USDValue is nil when the token has no price.
+type TokenWinningsView struct {
+	database.TokenWinnings
+	Symbol   string        `json:"symbol"`
+	Decimals int           `json:"decimals"`
+	USDValue *money.Amount `json:"usd_value"`
+}
+
+// priceWinnings attaches token details and USD values to a wallet's
+// winnings. The total covers priced tokens only; it is nil without a price
+// source, and tokens lacking a price are listed in unpriced.
+func (h *LeaderboardHandler) priceWinnings(ctx context.Context, winnings []database.TokenWinnings) ([]TokenWinningsView, *money.Amount, []string, error) {
+	views := make([]TokenWinningsView, 0, len(winnings))
+	unpriced := []string{}
+	var total *money.Amount
+	if h.prices != nil {
+		total = &money.Amount{}
+	}
+
+	for _, row := range winnings {
+		view := TokenWinningsView{TokenWinnings: row}
+		if token, known := h.tokens.Lookup(row.TokenAddress); known {
+			view.Symbol = token.Symbol
+			view.Decimals = token.Decimals
+		}
+
+		if h.prices != nil {
+			price, ok, err := h.prices.USDPrice(ctx, row.TokenAddress)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if ok {
+				value := row.AmountWon.Add(row.FeesEarned).Mul(price)
+				view.USDValue = &value
+				*total = total.Add(value)
+			} else {
+				unpriced = append(unpriced, row.TokenAddress)
+			}
+		}
+
+		views = append(views, view)
+	}
+
+	return views, total, unpriced, nil
+}
+
+// ListTokens returns the reward token registry.
+func (h *LeaderboardHandler) ListTokens(c *gin.Context) {
+	list(c, h.tokens.Tokens(), Page{}, gin.H{"usdc": h.tokens.USDC().Address})
+}
+
+func (h *LeaderboardHandler) GetTopPerformers(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "10")
 	
 	limit, err := strconv.Atoi(limitStr)
@@ -71,66 +211,242 @@ func GetTopPerformers(c *gin.Context) {
 		limit = 10
 	}
 
-	performers, err := database.GetTopPerformers(limit)
+	performers, err := h.leaderboard.GetLeaderboard(c.Request.Context(), limit, 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch top performers"})
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch top performers")
 		return
 	}
 
-	c.JSON(http.StatusOK, performers)
+	list(c, performers, Page{Limit: limit}, nil)
 }
 
+// ReputationEventRequest carries the amount either as AmountRaw, the
+// uint256 from the ReputationUpdated event in base units of TokenAddress,
+// or as USDCAmount, a decimal string in whole USDC. When both are sent they
+// must agree.
 type ReputationEventRequest struct {
-	WalletAddress   string  `json:"wallet_address" binding:"required"`
-	PointsAdded     int     `json:"points_added" binding:"required"`
-	TotalPoints     int     `json:"total_points" binding:"required"`
-	IsVerifier      bool    `json:"is_verifier"`
-	USDCAmount      float64 `json:"usdc_amount,omitempty"`
-	TransactionHash string  `json:"transaction_hash,omitempty"`
-	BlockNumber     int64   `json:"block_number,omitempty"`
+	WalletAddress   string        `json:"wallet_address" binding:"required,address"`
+	PointsAdded     *int          `json:"points_added,omitempty"`
+	TotalPoints     *int          `json:"total_points,omitempty"`
+	IsVerifier      bool          `json:"is_verifier"`
+	USDCAmount      *money.Amount `json:"usdc_amount,omitempty"`
+	AmountRaw       string        `json:"amount_raw,omitempty"`
+	TokenAddress    string        `json:"token_address,omitempty" binding:"omitempty,address"`
+	TransactionHash string        `json:"transaction_hash" binding:"required"`
+	LogIndex        *int          `json:"log_index" binding:"required"`
+	BlockNumber     int64         `json:"block_number,omitempty"`
+	BlockTimestamp  int64         `json:"block_timestamp,omitempty"`
+}
+
+// ReputationHandler records reputation events, deriving points from the
+// USDC amount with the same tiers as ReputationSystem.calculatePoints
+// instead of trusting client-supplied values.
+type ReputationHandler struct {
+	reputation database.ReputationRepository
+	tiers      reputation.TierTable
+	tokens     *money.TokenRegistry
+	publisher  *webhook.Publisher
+}
+
+func NewReputationHandler(repo database.ReputationRepository, tiers reputation.TierTable, tokens *money.TokenRegistry, publisher *webhook.Publisher) *ReputationHandler {
+	return &ReputationHandler{reputation: repo, tiers: tiers, tokens: tokens, publisher: publisher}
+}
+
+// resolveAmount returns the event's token, its raw on-chain amount and the
+// same amount as a decimal in token units. Events without a token address
+// were paid in the configured USDC. A non-nil error is reported with the
+// status and error code that go with it.
+func (h *ReputationHandler) resolveAmount(req *ReputationEventRequest) (money.Token, *big.Int, money.Amount, int, string, error) {
+	token := h.tokens.USDC()
+	if req.AmountRaw == "" {
+		if req.USDCAmount == nil || req.USDCAmount.Sign() <= 0 {
+			return token, nil, money.Amount{}, http.StatusBadRequest, CodeInvalidRequest, fmt.Errorf("usdc_amount or amount_raw must be a positive amount")
+		}
+		if req.TokenAddress != "" {
+			return token, nil, money.Amount{}, http.StatusBadRequest, CodeInvalidRequest, fmt.Errorf("token_address requires amount_raw")
+		}
+		raw, err := req.USDCAmount.Units(token.Decimals)
+		if err != nil {
+			return token, nil, money.Amount{}, http.StatusBadRequest, CodeInvalidRequest, err
+		}
+		return token, raw, *req.USDCAmount, 0, "", nil
+	}
+
+	raw, ok := new(big.Int).SetString(req.AmountRaw, 10)
+	if !ok || raw.Sign() <= 0 {
+		return token, nil, money.Amount{}, http.StatusBadRequest, CodeInvalidRequest, fmt.Errorf("amount_raw must be a positive integer in token base units")
+	}
+
+	if req.TokenAddress != "" {
+		var known bool
+		if token, known = h.tokens.Lookup(req.TokenAddress); !known {
+			return token, nil, money.Amount{}, http.StatusUnprocessableEntity, CodeUnknownToken, fmt.Errorf("token %s is not registered", req.TokenAddress)
+		}
+	}
+
+	amount := money.FromUnits(raw, token.Decimals)
+	if req.USDCAmount != nil && req.USDCAmount.Cmp(amount) != 0 {
+		return token, nil, money.Amount{}, http.StatusUnprocessableEntity, CodeMismatch, fmt.Errorf("usdc_amount %s does not match amount_raw %s", req.USDCAmount, amount)
+	}
+
+	return token, raw, amount, 0, "", nil
 }
 
-func RecordReputationEvent(c *gin.Context) {
+func (h *ReputationHandler) RecordReputationEvent(c *gin.Context) {
 	var req ReputationEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindingProblem(c, err)
+		return
+	}
+	req.WalletAddress = strings.ToLower(req.WalletAddress)
+
+	token, rawAmount, amount, status, code, err := h.resolveAmount(&req)
+	if err != nil {
+		problem(c, status, code, err.Error())
+		return
+	}
+
+	pointsAdded := h.tiers.CalculatePoints(reputation.TierAmount(rawAmount), req.IsVerifier)
+	if req.PointsAdded != nil && *req.PointsAdded != pointsAdded {
+		problemWith(c, http.StatusUnprocessableEntity, CodeMismatch, "points_added does not match the points for this USDC amount", gin.H{
+			"expected": pointsAdded,
+			"received": *req.PointsAdded,
+		})
+		return
+	}
+
+	previousPoints, err := h.reputation.GetPointsBefore(c.Request.Context(), req.WalletAddress, req.BlockNumber, *req.LogIndex)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to compute total points")
+		return
+	}
+
+	totalPoints := previousPoints + pointsAdded
+	if req.TotalPoints != nil && *req.TotalPoints != totalPoints {
+		problemWith(c, http.StatusUnprocessableEntity, CodeMismatch, "total_points does not match the recorded reputation history", gin.H{
+			"expected": totalPoints,
+			"received": *req.TotalPoints,
+		})
 		return
 	}
 
 	event := &database.ReputationEvent{
 		WalletAddress:   req.WalletAddress,
 		EventType:       "reputation_update",
-		PointsAdded:     req.PointsAdded,
-		TotalPoints:     req.TotalPoints,
+		PointsAdded:     pointsAdded,
+		TotalPoints:     totalPoints,
 		IsVerifier:      req.IsVerifier,
+		USDCAmount:      amount,
+		AmountRaw:       money.FromUnits(rawAmount, 0),
+		TokenAddress:    token.Address,
 		TransactionHash: req.TransactionHash,
+		LogIndex:        req.LogIndex,
 		BlockNumber:     req.BlockNumber,
 	}
+	if req.BlockTimestamp > 0 {
+		blockTime := time.Unix(req.BlockTimestamp, 0).UTC()
+		event.BlockTimestamp = &blockTime
+	}
 
-	if err := database.CreateReputationEvent(event); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record reputation event"})
+	inserted, err := h.reputation.RecordReputationEvent(c.Request.Context(), event)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to record reputation event")
 		return
 	}
 
-	isWinner := !req.IsVerifier
-	if err := database.UpdateLeaderboardFromEvent(
-		req.WalletAddress,
-		req.PointsAdded,
-		req.TotalPoints,
-		isWinner,
-		req.USDCAmount,
-	); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update leaderboard"})
+	if !inserted {
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Reputation event already recorded",
+			"duplicate": true,
+		})
 		return
 	}
 
+	publish(c.Request.Context(), h.publisher, webhook.EventReputationUpdated, event)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Reputation event recorded successfully",
 		"event":   event,
 	})
 }
 
-func GetRecentEvents(c *gin.Context) {
+// CalculatePoints previews the points an award would earn. amount is in
+// USDC base units (6 decimals), matching the on-chain argument.
+func (h *ReputationHandler) CalculatePoints(c *gin.Context) {
+	amount, err := strconv.ParseUint(c.Query("amount"), 10, 64)
+	if err != nil || amount == 0 {
+		problem(c, http.StatusBadRequest, CodeInvalidRequest, "amount must be a positive integer in USDC base units")
+		return
+	}
+
+	isVerifier := c.DefaultQuery("is_verifier", "false") == "true"
+
+	c.JSON(http.StatusOK, gin.H{
+		"amount":      amount,
+		"is_verifier": isVerifier,
+		"points":      h.tiers.CalculatePoints(amount, isVerifier),
+	})
+}
+
+type SolutionApprovalRequest struct {
+	ChallengeAddress string `json:"challenge_address" binding:"required,address"`
+	SubmissionID     int64  `json:"submission_id" binding:"required"`
+	SubmissionUID    string `json:"submission_uid,omitempty" binding:"omitempty,uid"`
+	Approver         string `json:"approver" binding:"required,address"`
+	IsVerifier       bool   `json:"is_verifier"`
+	TransactionHash  string `json:"transaction_hash" binding:"required"`
+	LogIndex         *int   `json:"log_index" binding:"required"`
+	BlockNumber      int64  `json:"block_number,omitempty"`
+	BlockTimestamp   int64  `json:"block_timestamp,omitempty"`
+}
+
+// RecordSolutionApproval ingests a SolutionApproved event. Verifier
+// approvals feed the approval latency shown on the verifier leaderboard.
+func (h *ReputationHandler) RecordSolutionApproval(c *gin.Context) {
+	var req SolutionApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingProblem(c, err)
+		return
+	}
+
+	approval := &database.SolutionApproval{
+		ChallengeAddress: strings.ToLower(req.ChallengeAddress),
+		SubmissionID:     req.SubmissionID,
+		Approver:         strings.ToLower(req.Approver),
+		IsVerifier:       req.IsVerifier,
+		TransactionHash:  req.TransactionHash,
+		LogIndex:         *req.LogIndex,
+		BlockNumber:      req.BlockNumber,
+	}
+	if req.SubmissionUID != "" {
+		approval.SubmissionUID = &req.SubmissionUID
+	}
+	if req.BlockTimestamp > 0 {
+		blockTime := time.Unix(req.BlockTimestamp, 0).UTC()
+		approval.BlockTimestamp = &blockTime
+	}
+
+	inserted, err := h.reputation.CreateSolutionApproval(c.Request.Context(), approval)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to record solution approval")
+		return
+	}
+
+	if !inserted {
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Solution approval already recorded",
+			"duplicate": true,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Solution approval recorded successfully",
+		"approval": approval,
+	})
+}
+
+func (h *ReputationHandler) GetRecentEvents(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "20")
 	
 	limit, err := strconv.Atoi(limitStr)
@@ -141,11 +457,11 @@ func GetRecentEvents(c *gin.Context) {
 		limit = 100
 	}
 
-	events, err := database.GetRecentReputationEvents(limit)
+	events, err := h.reputation.GetRecentReputationEvents(c.Request.Context(), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recent events"})
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch recent events")
 		return
 	}
 
-	c.JSON(http.StatusOK, events)
+	list(c, events, Page{Limit: limit}, nil)
 }
\ No newline at end of file
@@ -1,42 +1,60 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/cyrup/backend/api/models"
 	"github.com/cyrup/backend/api/services"
+	"github.com/cyrup/backend/internal/project"
+	"github.com/cyrup/backend/internal/queue"
+	"github.com/cyrup/backend/internal/ratelimit"
+	"github.com/cyrup/backend/internal/webhook"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type LeanHandler struct {
-	leanService *services.LeanHTTPService
-	results     map[string]*models.ProofResult
-	mu          sync.RWMutex
+	leanService   *services.LeanHTTPService
+	publisher     *webhook.Publisher
+	queue         *queue.Queue
+	limiter       *ratelimit.Limiter
+	maxCodeSize   int
+	maxBatchItems int
+	projectLimits project.Limits
+	results       map[string]*models.ProofResult
+	batches       map[string]*batch
+	mu            sync.RWMutex
 }
 
-func NewLeanHandler(leanService *services.LeanHTTPService) *LeanHandler {
+func NewLeanHandler(leanService *services.LeanHTTPService, publisher *webhook.Publisher, jobs *queue.Queue, limiter *ratelimit.Limiter, maxCodeSize, maxBatchItems int, projectLimits project.Limits) *LeanHandler {
 	return &LeanHandler{
-		leanService: leanService,
-		results:     make(map[string]*models.ProofResult),
+		leanService:   leanService,
+		publisher:     publisher,
+		queue:         jobs,
+		limiter:       limiter,
+		maxCodeSize:   maxCodeSize,
+		maxBatchItems: maxBatchItems,
+		projectLimits: projectLimits,
+		results:       make(map[string]*models.ProofResult),
+		batches:       make(map[string]*batch),
 	}
 }
 
 func (h *LeanHandler) VerifyProof(c *gin.Context) {
 	var req models.VerifyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindingProblem(c, err)
+		return
+	}
+	job, ok := h.verifyJob(c, req)
+	if !ok {
 		return
 	}
 
 	id := uuid.New().String()
-	
-	timeout := 30
-	if req.Timeout > 0 && req.Timeout <= 60000 {
-		timeout = req.Timeout / 1000
-	}
 
 	result := &models.ProofResult{
 		ID:        id,
@@ -48,7 +66,8 @@ func (h *LeanHandler) VerifyProof(c *gin.Context) {
 	h.results[id] = result
 	h.mu.Unlock()
 
-	go h.processProof(id, req.Code, timeout)
+	owner := authenticatedWallet(c)
+	h.queue.Submit(func() { h.processProof(id, owner, job) })
 
 	c.JSON(http.StatusAccepted, models.VerifyResponse{
 		ID:     id,
@@ -56,7 +75,10 @@ func (h *LeanHandler) VerifyProof(c *gin.Context) {
 	})
 }
 
-func (h *LeanHandler) processProof(id, code string, timeout int) {
+// processProof runs a queued job. When the job was requested by a signed
+// wallet, its verification.finished event goes to that wallet's webhooks;
+// anonymous jobs publish none.
+func (h *LeanHandler) processProof(id, owner string, job services.LeanVerifyRequest) {
 	h.mu.Lock()
 	if result, exists := h.results[id]; exists {
 		result.Status = models.StatusProcessing
@@ -65,30 +87,45 @@ func (h *LeanHandler) processProof(id, code string, timeout int) {
 
 	startTime := time.Now()
 	
-	output, err := h.leanService.RunLeanProof(code, timeout)
+	response, err := h.leanService.Run(job)
 	
 	executionTime := time.Since(startTime)
 	completedAt := time.Now()
 
+	var finished *models.ProofResult
 	h.mu.Lock()
 	if result, exists := h.results[id]; exists {
 		result.ExecutionTime = executionTime
 		result.CompletedAt = &completedAt
 		
-		if err != nil {
-			if err.Error() == "timeout" {
-				result.Status = models.StatusTimeout
-				result.Error = "Proof verification timed out"
-			} else {
-				result.Status = models.StatusError
-				result.Error = err.Error()
-			}
-		} else {
+		switch {
+		case err != nil:
+			result.Status = models.StatusError
+			result.Error = err.Error()
+		case response.Status == "success":
 			result.Status = models.StatusSuccess
-			result.Output = output
+			result.Output = response.Output
+		case response.Status == "timeout":
+			result.Status = models.StatusTimeout
+			result.Error = "Proof verification timed out"
+		default:
+			result.Status = models.StatusError
+			result.Error = response.Error
 		}
+		if response != nil {
+			result.Files = response.Files
+		}
+		snapshot := *result
+		finished = &snapshot
 	}
 	h.mu.Unlock()
+
+	if finished != nil {
+		// The output is left out; subscribers fetch it from /api/result/:id.
+		finished.Output = ""
+		finished.Files = nil
+		publishTo(context.Background(), h.publisher, owner, webhook.EventVerificationFinished, finished)
+	}
 }
 
 func (h *LeanHandler) GetStatus(c *gin.Context) {
@@ -99,7 +136,7 @@ func (h *LeanHandler) GetStatus(c *gin.Context) {
 	h.mu.RUnlock()
 	
 	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Proof not found"})
+		problem(c, http.StatusNotFound, CodeNotFound, "Proof not found")
 		return
 	}
 	
@@ -117,7 +154,7 @@ func (h *LeanHandler) GetResult(c *gin.Context) {
 	h.mu.RUnlock()
 	
 	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Proof not found"})
+		problem(c, http.StatusNotFound, CodeNotFound, "Proof not found")
 		return
 	}
 	
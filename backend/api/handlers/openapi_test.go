@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestOpenAPI compares the spec with the registered routes and with the
+// Go types the handlers bind and serialize.
+func TestOpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	Routes{}.Register(r)
+
+	if err := CheckOpenAPI(r.Routes()); err != nil {
+		t.Fatal(err)
+	}
+}
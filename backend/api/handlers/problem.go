@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+	"strings"
+
+	"github.com/cyrup/backend/internal/validate"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// Error codes carried in the code member of every problem response. They
+// are part of the API contract: clients branch on them, so a code keeps its
+// meaning once published and new conditions get new codes.
+const (
+	CodeInvalidRequest   = "invalid_request"
+	CodeValidationFailed = "validation_failed"
+	CodeInvalidAddress   = "invalid_address"
+	CodeInvalidUID       = "invalid_uid"
+	CodeInvalidQuery     = "invalid_query"
+	CodeInvalidProject   = "invalid_project"
+	CodeUnauthorized     = "unauthorized"
+	CodeSignatureInvalid = "signature_invalid"
+	CodeForbidden        = "forbidden"
+	CodeAdminDisabled    = "admin_disabled"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeAlreadyExists    = "already_exists"
+	CodeChallengeClosed  = "challenge_closed"
+	CodeWindowClosed     = "submission_window_closed"
+	CodeRevealNotOpen    = "reveal_not_open"
+	CodeAlreadyRevealed  = "already_revealed"
+	CodeMismatch         = "mismatch"
+	CodeUnknownToken     = "unknown_token"
+	CodePayloadTooLarge  = "payload_too_large"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal_error"
+)
+
+// problemType prefixes the code to form the type URI of a problem.
+const problemType = "urn:cyrup:error:"
+
+// requestIDHeader carries the request ID in both directions.
+const requestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key holding the request ID.
+const requestIDKey = "request_id"
+
+// FieldError describes one invalid member of a request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// problem aborts the request with a problem+json response.
+func problem(c *gin.Context, status int, code, detail string) {
+	problemWith(c, status, code, detail, nil)
+}
+
+// problemWith is problem with extension members. The body carries the
+// RFC 7807 members plus code, which repeats the last segment of type so
+// clients need not parse the URI, and request_id, which matches the
+// X-Request-ID header for correlating with server logs.
+func problemWith(c *gin.Context, status int, code, detail string, extensions gin.H) {
+	body := gin.H{}
+	for key, value := range extensions {
+		body[key] = value
+	}
+	body["type"] = problemType + code
+	body["title"] = http.StatusText(status)
+	body["status"] = status
+	if detail != "" {
+		body["detail"] = detail
+	}
+	body["instance"] = c.Request.URL.Path
+	body["code"] = code
+	if id := c.GetString(requestIDKey); id != "" {
+		body["request_id"] = id
+	}
+
+	c.Abort()
+	c.Render(status, problemJSON{body})
+}
+
+// problemJSON renders like gin's JSON but with the problem media type.
+type problemJSON struct {
+	body gin.H
+}
+
+func (r problemJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return json.NewEncoder(w).Encode(r.body)
+}
+
+func (r problemJSON) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/problem+json")
+}
+
+// bindingProblem reports a failed ShouldBindJSON. Validation failures list
+// each offending field by its JSON name rather than echoing the binder's
+// message, which names Go struct fields.
+func bindingProblem(c *gin.Context, err error) {
+	var invalid validator.ValidationErrors
+	var syntax *json.SyntaxError
+	var mistyped *json.UnmarshalTypeError
+	var tooLarge *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &tooLarge):
+		bodyTooLarge(c, tooLarge.Limit)
+	case errors.As(err, &invalid):
+		fields := make([]FieldError, 0, len(invalid))
+		for _, fe := range invalid {
+			fields = append(fields, FieldError{Field: fieldName(fe), Message: fieldMessage(fe)})
+		}
+		problemWith(c, http.StatusBadRequest, CodeValidationFailed, "The request body has invalid fields", gin.H{"errors": fields})
+	case errors.As(err, &mistyped):
+		problemWith(c, http.StatusBadRequest, CodeValidationFailed, "The request body has invalid fields", gin.H{
+			"errors": []FieldError{{Field: mistyped.Field, Message: "must be a " + jsonType(mistyped.Type)}},
+		})
+	case errors.As(err, &syntax), errors.Is(err, io.ErrUnexpectedEOF):
+		problem(c, http.StatusBadRequest, CodeInvalidRequest, "The request body is not valid JSON")
+	case errors.Is(err, io.EOF):
+		problem(c, http.StatusBadRequest, CodeInvalidRequest, "The request body is empty")
+	default:
+		problem(c, http.StatusBadRequest, CodeInvalidRequest, "The request body could not be read")
+	}
+}
+
+// fieldName is the dotted JSON path of a failed field, without the name of
+// the request struct itself.
+func fieldName(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "address":
+		if value, ok := fe.Value().(string); ok {
+			if _, err := validate.Address(value); err != nil {
+				return err.Error()
+			}
+		}
+		return validate.ErrAddress.Error()
+	case "uid":
+		return validate.ErrUID.Error()
+	case "solution_hash":
+		return validate.ErrSolutionHash.Error()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min":
+		if fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map || fe.Kind() == reflect.String {
+			return "must have a length of at least " + fe.Param()
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map || fe.Kind() == reflect.String {
+			return "must have a length of at most " + fe.Param()
+		}
+		return "must be at most " + fe.Param()
+	case "gt", "gte", "lt", "lte":
+		return fmt.Sprintf("must be %s %s", comparisons[fe.Tag()], fe.Param())
+	case "url":
+		return "must be a URL"
+	}
+	return "failed the " + fe.Tag() + " check"
+}
+
+var comparisons = map[string]string{
+	"gt":  "greater than",
+	"gte": "at least",
+	"lt":  "less than",
+	"lte": "at most",
+}
+
+// jsonType names a Go type the way a client would think of it in JSON.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		// encoding/json takes []byte as base64.
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return "base64 string"
+		}
+		return "array"
+	case reflect.Ptr:
+		return jsonType(t.Elem())
+	}
+	return "object"
+}
+
+// RequestID tags each request with an ID, taken from X-Request-ID when the
+// client sent a well-formed one and generated otherwise, and echoes it in
+// the response header.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if validate.UID(id) != nil {
+			id = uuid.NewString()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+// Recover turns a panicking handler into a 500 problem response, logging
+// the panic and stack under the request ID. It must run after RequestID.
+func Recover() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				log.Printf("Panic serving %s %s (request %s): %v\n%s", c.Request.Method, c.Request.URL.Path, c.GetString(requestIDKey), err, debug.Stack())
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+				problem(c, http.StatusInternalServerError, CodeInternal, "The server hit an unexpected error")
+			}
+		}()
+		c.Next()
+	}
+}
+
+// NoRoute answers requests for unknown paths.
+func NoRoute(c *gin.Context) {
+	problem(c, http.StatusNotFound, CodeNotFound, "No endpoint at "+c.Request.URL.Path)
+}
+
+// NoMethod answers requests using a method the path does not support.
+func NoMethod(c *gin.Context) {
+	problem(c, http.StatusMethodNotAllowed, CodeMethodNotAllowed, c.Request.Method+" is not supported on "+c.Request.URL.Path)
+}
+
+// Page describes which part of a list a response holds. Count is the
+// number of items in data; the other members are present only for
+// endpoints paged that way.
+type Page struct {
+	Count      int     `json:"count"`
+	Limit      int     `json:"limit,omitempty"`
+	Offset     *int    `json:"offset,omitempty"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// offsetPage is the Page of a limit/offset list.
+func offsetPage(limit, offset int) Page {
+	return Page{Limit: limit, Offset: &offset}
+}
+
+// list writes a 200 {data, page} envelope. Extra members describe the list
+// as a whole, such as the filters that produced it.
+func list(c *gin.Context, data interface{}, page Page, extra gin.H) {
+	value := reflect.ValueOf(data)
+	if value.Kind() == reflect.Slice && value.IsNil() {
+		data = []struct{}{}
+	} else if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		page.Count = value.Len()
+	}
+
+	body := gin.H{}
+	for key, value := range extra {
+		body[key] = value
+	}
+	body["data"] = data
+	body["page"] = page
+	c.JSON(http.StatusOK, body)
+}
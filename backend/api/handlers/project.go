@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cyrup/backend/api/models"
+	"github.com/cyrup/backend/api/services"
+	"github.com/cyrup/backend/internal/project"
+	"github.com/gin-gonic/gin"
+)
+
+// requestProblem is a problem found in a request before it is written, so
+// that the caller can add context such as a batch item's index.
+type requestProblem struct {
+	status int
+	code   string
+	detail string
+	extra  gin.H
+}
+
+func (p *requestProblem) write(c *gin.Context) {
+	problemWith(c, p.status, p.code, p.detail, p.extra)
+}
+
+// verifyJob turns a verify request into a lean runner job like buildJob,
+// writing the problem and returning false when it cannot.
+func (h *LeanHandler) verifyJob(c *gin.Context, req models.VerifyRequest) (services.LeanVerifyRequest, bool) {
+	job, invalid := h.buildJob(req)
+	if invalid != nil {
+		invalid.write(c)
+		return job, false
+	}
+	return job, true
+}
+
+// buildJob turns a verify request into a lean runner job: a single file
+// from code, or a multi-file project from files or an archive. It returns a
+// problem when the request has none of these, more than one, or oversized
+// code or an invalid or oversized project.
+func (h *LeanHandler) buildJob(req models.VerifyRequest) (services.LeanVerifyRequest, *requestProblem) {
+	job := services.LeanVerifyRequest{Timeout: timeoutSeconds(req.Timeout)}
+
+	sources := 0
+	for _, set := range []bool{req.Code != "", len(req.Files) > 0, len(req.Archive) > 0} {
+		if set {
+			sources++
+		}
+	}
+	switch {
+	case sources == 0:
+		return job, &requestProblem{http.StatusBadRequest, CodeValidationFailed, "The request body has invalid fields", gin.H{
+			"errors": []FieldError{{Field: "code", Message: "is required"}},
+		}}
+	case sources > 1:
+		return job, &requestProblem{status: http.StatusBadRequest, code: CodeInvalidRequest, detail: "Send one of code, files or archive"}
+	case req.Code != "":
+		if invalid := codeSizeProblem(req.Code, h.maxCodeSize); invalid != nil {
+			return job, invalid
+		}
+		job.Code = req.Code
+		return job, nil
+	}
+
+	var p *project.Project
+	var err error
+	if len(req.Files) > 0 {
+		p, err = project.New(req.Files, req.Entry, h.projectLimits)
+	} else {
+		p, err = project.FromArchive(req.Archive, req.Entry, h.projectLimits)
+	}
+	var limit *project.LimitError
+	switch {
+	case errors.As(err, &limit):
+		return job, &requestProblem{http.StatusRequestEntityTooLarge, CodePayloadTooLarge, limit.Detail, gin.H{
+			"limit": limit.Limit,
+		}}
+	case err != nil:
+		return job, &requestProblem{status: http.StatusBadRequest, code: CodeInvalidProject, detail: fmt.Sprintf("The project is invalid: %s", err)}
+	}
+
+	job.Files, job.Entry = p.Files, p.Entry
+	return job, nil
+}
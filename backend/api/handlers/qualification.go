@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/reputation"
+	"github.com/gin-gonic/gin"
+)
+
+// GetVerifierQualification reports whether a wallet passes the top 10%
+// check that gates ChallengeEscrow.proposeAsVerifier. The threshold is the
+// last one reported by a ThresholdUpdated event when it agrees with the
+// value recomputed from the stored points; otherwise the recomputed value is
+// used, so a bad event cannot lower the bar.
+func (h *LeaderboardHandler) GetVerifierQualification(c *gin.Context) {
+	ctx := c.Request.Context()
+	walletAddress, ok := addressParam(c, "wallet")
+	if !ok {
+		return
+	}
+
+	stats, err := h.leaderboard.GetUserStats(ctx, walletAddress)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch user stats")
+		return
+	}
+
+	points := 0
+	if stats != nil {
+		points = stats.ReputationScore
+	}
+
+	topPoints, err := h.leaderboard.GetTopPoints(ctx, reputation.MaxLeaderboardSize)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch top performers")
+		return
+	}
+
+	totalUsers, usersBelow, err := h.leaderboard.CountUsers(ctx, points)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to count users")
+		return
+	}
+
+	latest, err := h.reputation.GetThresholdHistory(ctx, 1)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch threshold")
+		return
+	}
+
+	// The contract's stored threshold is the one its last ThresholdUpdated
+	// event reported, and it is kept when there is nothing to recompute.
+	previous := 0
+	if len(latest) > 0 {
+		previous = latest[0].NewThreshold
+	}
+	computed := reputation.Top10Threshold(topPoints, totalUsers, previous)
+	threshold, source := computed, "computed"
+	if len(latest) > 0 && latest[0].NewThreshold == computed {
+		source = "event"
+	}
+
+	qualified, gap, percentile := reputation.Qualify(points, threshold, usersBelow, totalUsers)
+
+	c.JSON(http.StatusOK, reputation.Qualification{
+		WalletAddress:     walletAddress,
+		Points:            points,
+		Threshold:         threshold,
+		ThresholdSource:   source,
+		ComputedThreshold: computed,
+		Gap:               gap,
+		Qualified:         qualified,
+		Percentile:        percentile,
+		TotalUsers:        totalUsers,
+	})
+}
+
+type ThresholdUpdateRequest struct {
+	OldThreshold    int    `json:"old_threshold"`
+	NewThreshold    int    `json:"new_threshold"`
+	TotalUsers      int    `json:"total_users" binding:"required"`
+	TransactionHash string `json:"transaction_hash" binding:"required"`
+	LogIndex        *int   `json:"log_index" binding:"required"`
+	BlockNumber     int64  `json:"block_number" binding:"required"`
+	BlockTimestamp  int64  `json:"block_timestamp,omitempty"`
+}
+
+// RecordThresholdUpdate ingests a ThresholdUpdated event. It is behind
+// RequireAdmin.
+func (h *ReputationHandler) RecordThresholdUpdate(c *gin.Context) {
+	var req ThresholdUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingProblem(c, err)
+		return
+	}
+
+	update := &database.ThresholdUpdate{
+		OldThreshold:    req.OldThreshold,
+		NewThreshold:    req.NewThreshold,
+		TotalUsers:      req.TotalUsers,
+		TransactionHash: req.TransactionHash,
+		LogIndex:        *req.LogIndex,
+		BlockNumber:     req.BlockNumber,
+	}
+	if req.BlockTimestamp > 0 {
+		blockTime := time.Unix(req.BlockTimestamp, 0).UTC()
+		update.BlockTimestamp = &blockTime
+	}
+
+	inserted, err := h.reputation.CreateThresholdUpdate(c.Request.Context(), update)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to record threshold update")
+		return
+	}
+
+	if !inserted {
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Threshold update already recorded",
+			"duplicate": true,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Threshold update recorded successfully",
+		"update":  update,
+	})
+}
+
+func (h *ReputationHandler) GetThresholdHistory(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "50")
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	updates, err := h.reputation.GetThresholdHistory(c.Request.Context(), limit)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch threshold history")
+		return
+	}
+
+	list(c, updates, Page{Limit: limit}, nil)
+}
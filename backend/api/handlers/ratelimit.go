@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cyrup/backend/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit charges each request against a budget, once for the client IP
+// and once more for the signed wallet if there is one, and rejects it with
+// 429 when either bucket is empty. It must run after WalletAuth. If the
+// limiter's store fails the request is let through.
+func RateLimit(limiter *ratelimit.Limiter, budget string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if chargeRateLimit(c, limiter, budget, 1) {
+			c.Next()
+		}
+	}
+}
+
+// chargeRateLimit takes cost tokens from the budget like RateLimit and
+// reports whether the request may go on. When it may not, the problem has
+// been written: 429, or 413 if cost is more than the bucket ever holds.
+func chargeRateLimit(c *gin.Context, limiter *ratelimit.Limiter, budget string, cost int) bool {
+	keys := []string{"ip:" + c.ClientIP()}
+	if wallet := authenticatedWallet(c); wallet != "" {
+		keys = append(keys, "wallet:"+wallet)
+	}
+
+	decision, limited, err := limiter.Allow(c.Request.Context(), budget, cost, keys...)
+	if err != nil {
+		log.Printf("Warning: rate limiter unavailable: %v", err)
+		return true
+	}
+	if !limited {
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(seconds(decision.Reset)))
+
+	if !decision.Allowed && cost > decision.Limit {
+		problemWith(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, fmt.Sprintf("Request costs %d %s tokens; the limit is %d", cost, budget, decision.Limit), gin.H{
+			"limit":  decision.Limit,
+			"budget": budget,
+		})
+		return false
+	}
+	if !decision.Allowed {
+		retryAfter := seconds(decision.RetryAfter)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		problemWith(c, http.StatusTooManyRequests, CodeRateLimited, "Rate limit exceeded", gin.H{
+			"budget":      budget,
+			"retry_after": retryAfter,
+		})
+		return false
+	}
+	return true
+}
+
+// RateLimitReads applies the read budget to GET requests only, so a group
+// can be limited as a whole.
+func RateLimitReads(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	limit := RateLimit(limiter, ratelimit.BudgetRead)
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		limit(c)
+	}
+}
+
+// seconds rounds up to whole seconds for the rate limit headers.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// checkCodeSize rejects code over maxBytes with 413 and reports whether the
+// code may be processed.
+func checkCodeSize(c *gin.Context, code string, maxBytes int) bool {
+	if invalid := codeSizeProblem(code, maxBytes); invalid != nil {
+		invalid.write(c)
+		return false
+	}
+	return true
+}
+
+func codeSizeProblem(code string, maxBytes int) *requestProblem {
+	if len(code) > maxBytes {
+		return &requestProblem{http.StatusRequestEntityTooLarge, CodePayloadTooLarge, fmt.Sprintf("Code is %d bytes; the limit is %d", len(code), maxBytes), gin.H{
+			"limit": maxBytes,
+		}}
+	}
+	return nil
+}
+
+// bodyOverhead is the room a request body gets for everything but code.
+const bodyOverhead = 64 << 10
+
+// MaxBodyBytes is the body limit for requests carrying up to payload bytes
+// of code. JSON escaping can double the code's size.
+func MaxBodyBytes(payload int) int64 {
+	return 2*int64(payload) + bodyOverhead
+}
+
+// rawBodyKey holds the request body as it was before any limit.
+const rawBodyKey = "raw_body"
+
+// LimitBody rejects request bodies over maxBytes with 413 before binding
+// reads them in full. Applied again on a route, the route's limit replaces
+// the group's.
+func LimitBody(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			bodyTooLarge(c, maxBytes)
+			return
+		}
+
+		body, ok := c.Get(rawBodyKey)
+		if !ok {
+			body = c.Request.Body
+			c.Set(rawBodyKey, body)
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, body.(io.ReadCloser), maxBytes)
+		c.Next()
+	}
+}
+
+func bodyTooLarge(c *gin.Context, maxBytes int64) {
+	problemWith(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, fmt.Sprintf("The request body is larger than %d bytes", maxBytes), gin.H{
+		"limit": maxBytes,
+	})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"github.com/cyrup/backend/internal/auth"
+	"github.com/cyrup/backend/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// Routes holds what the API's routes are served by. The route table lives
+// here rather than in main so that tests can register it and compare it
+// with the spec; nil handlers are fine for that, since nothing is served.
+type Routes struct {
+	Lean        *LeanHandler
+	Submissions *SubmissionHandler
+	Challenges  *ChallengeHandler
+	Commitments *CommitmentHandler
+	Similarity  *SimilarityHandler
+	Verifier    *VerifierHandler
+	Webhooks    *WebhookHandler
+	Leaderboard *LeaderboardHandler
+	Reputation  *ReputationHandler
+	Admin       *AdminHandler
+
+	Wallets *auth.Verifier
+	Limiter *ratelimit.Limiter
+	// MaxBodyBytes limits request bodies; MaxBatchBodyBytes replaces it for
+	// batch verification.
+	MaxBodyBytes      int64
+	MaxBatchBodyBytes int64
+}
+
+// Register adds the routes to r.
+func (rt Routes) Register(r *gin.Engine) {
+	verifyLimit := RateLimit(rt.Limiter, ratelimit.BudgetVerify)
+
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "healthy"})
+	})
+
+	api := r.Group("/api", LimitBody(rt.MaxBodyBytes), WalletAuth(rt.Wallets), RateLimitReads(rt.Limiter))
+	{
+		api.GET("/openapi.json", OpenAPI)
+
+		// LEAN verification endpoints
+		api.POST("/verify", verifyLimit, rt.Lean.VerifyProof)
+		api.POST("/verify/batch", LimitBody(rt.MaxBatchBodyBytes), rt.Lean.VerifyBatch)
+		api.GET("/verify/batch/:id", rt.Lean.GetBatch)
+		api.GET("/verify/batch/:id/items", rt.Lean.ListBatchItems)
+		api.GET("/status/:id", rt.Lean.GetStatus)
+		api.GET("/result/:id", rt.Lean.GetResult)
+
+		// Submission endpoints
+		api.POST("/submissions", verifyLimit, rt.Submissions.CreateSubmission)
+		api.GET("/submissions/:uid", rt.Submissions.GetSubmission)
+		api.PUT("/submissions/:uid/status", RequireAdmin(), rt.Submissions.UpdateSubmissionStatus)
+		api.GET("/submissions/wallet/:wallet", rt.Submissions.GetUserSubmissions)
+		api.GET("/submissions/challenge/:address", rt.Submissions.GetChallengeSubmissions)
+
+		// Challenge endpoints
+		api.GET("/challenges", rt.Challenges.ListChallenges)
+		api.POST("/challenges/events", RequireAdmin(), rt.Challenges.RecordChallengeEvent)
+		api.GET("/challenges/:address", rt.Challenges.GetChallenge)
+		api.GET("/challenges/:address/commitments", rt.Commitments.ListCommitments)
+		api.GET("/challenges/:address/similarity", rt.Similarity.GetChallengeSimilarity)
+		api.GET("/challenges/:address/schedule", rt.Challenges.GetSchedule)
+		api.GET("/deadlines", rt.Challenges.ListUpcomingDeadlines)
+		api.GET("/notifications", rt.Challenges.ListNotifications)
+
+		// Verifier workbench endpoints
+		api.GET("/verifier/:wallet/queue", rt.Verifier.GetQueue)
+		api.POST("/verifier/submissions/:uid/review", rt.Verifier.RecordReview)
+		api.POST("/verifier/submissions/:uid/recheck", verifyLimit, rt.Verifier.Recheck)
+
+		// Commit-reveal endpoints
+		api.POST("/commitments", rt.Commitments.Commit)
+		api.POST("/commitments/reveal", verifyLimit, rt.Commitments.Reveal)
+
+		// Webhook endpoints
+		api.POST("/webhooks", rt.Webhooks.CreateWebhook)
+		api.GET("/webhooks", rt.Webhooks.ListWebhooks)
+		api.DELETE("/webhooks/:id", rt.Webhooks.DeleteWebhook)
+		api.GET("/webhooks/:id/deliveries", rt.Webhooks.ListDeliveries)
+		api.POST("/webhooks/:id/deliveries/:delivery/redeliver", rt.Webhooks.Redeliver)
+
+		// Leaderboard endpoints
+		api.GET("/leaderboard", rt.Leaderboard.GetLeaderboard)
+		api.GET("/leaderboard/seasons", rt.Leaderboard.ListSeasons)
+		api.GET("/tokens", rt.Leaderboard.ListTokens)
+		api.GET("/leaderboard/top", rt.Leaderboard.GetTopPerformers)
+		api.GET("/leaderboard/user/:wallet", rt.Leaderboard.GetUserStats)
+		api.GET("/leaderboard/user/:wallet/qualification", rt.Leaderboard.GetVerifierQualification)
+		api.GET("/leaderboard/thresholds", rt.Reputation.GetThresholdHistory)
+		api.POST("/leaderboard/thresholds", RequireAdmin(), rt.Reputation.RecordThresholdUpdate)
+		api.GET("/leaderboard/points", rt.Reputation.CalculatePoints)
+		api.POST("/leaderboard/events", RequireAdmin(), rt.Reputation.RecordReputationEvent)
+		api.GET("/leaderboard/events/recent", rt.Reputation.GetRecentEvents)
+		api.POST("/leaderboard/approvals", RequireAdmin(), rt.Reputation.RecordSolutionApproval)
+	}
+
+	admin := r.Group("/api/admin", RequireAdmin())
+	{
+		admin.POST("/leaderboard/rebuild", rt.Admin.RebuildLeaderboard)
+		admin.GET("/leaderboard/consistency", rt.Admin.CheckLeaderboardConsistency)
+		admin.POST("/similarity/:address/rescan", rt.Admin.RescanSimilarity)
+	}
+}
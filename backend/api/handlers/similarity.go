@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/similarity"
+	"github.com/gin-gonic/gin"
+)
+
+// SimilarityHandler serves the near-duplicate report for a challenge.
+type SimilarityHandler struct {
+	similarity database.SimilarityRepository
+	challenges database.ChallengeRepository
+	scanner    *similarity.Scanner
+}
+
+func NewSimilarityHandler(repo database.SimilarityRepository, challenges database.ChallengeRepository, scanner *similarity.Scanner) *SimilarityHandler {
+	return &SimilarityHandler{similarity: repo, challenges: challenges, scanner: scanner}
+}
+
+// GetChallengeSimilarity lists the submissions flagged as resembling an
+// earlier submission from another wallet. The report is shown to whoever
+// may read every submission's code: the selected verifier and the creator
+// while the challenge runs, anyone once it has closed.
+func (h *SimilarityHandler) GetChallengeSimilarity(c *gin.Context) {
+	address, ok := addressParam(c, "address")
+	if !ok {
+		return
+	}
+
+	challenge, err := h.challenges.GetChallenge(c.Request.Context(), address)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenge")
+		return
+	}
+	if !challenge.CodeVisibleTo(authenticatedWallet(c), "") {
+		problem(c, http.StatusForbidden, CodeForbidden, "Only the challenge's verifier and creator can view the similarity report")
+		return
+	}
+
+	flags, err := h.similarity.ListSimilarityFlags(c.Request.Context(), address)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch similarity flags")
+		return
+	}
+
+	list(c, flags, Page{}, gin.H{
+		"challenge_address": address,
+		"threshold":         h.scanner.Threshold(),
+	})
+}
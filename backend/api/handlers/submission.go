@@ -1,113 +1,367 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"fmt"
+	"log"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cyrup/backend/api/services"
 	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/similarity"
+	"github.com/cyrup/backend/internal/validate"
+	"github.com/cyrup/backend/internal/webhook"
 	"github.com/gin-gonic/gin"
 )
 
 type SubmissionRequest struct {
-	UID              string `json:"uid" binding:"required"`
-	ChallengeAddress string `json:"challenge_address" binding:"required"`
-	WalletAddress    string `json:"wallet_address" binding:"required"`
+	UID              string `json:"uid" binding:"required,uid"`
+	ChallengeAddress string `json:"challenge_address" binding:"required,address"`
+	WalletAddress    string `json:"wallet_address" binding:"required,address"`
 	SolutionCode     string `json:"solution_code" binding:"required"`
-	SolutionHash     string `json:"solution_hash,omitempty"`
+	SolutionHash     string `json:"solution_hash,omitempty" binding:"omitempty,solution_hash"`
 }
 
-func CreateSubmission(c *gin.Context) {
+// SubmissionHandler stores and serves solution submissions. Solution code
+// is withheld according to Challenge.CodeVisibleTo. New submissions are
+// checked for near-duplicates and queued for an automatic Lean check.
+type SubmissionHandler struct {
+	submissions database.SubmissionRepository
+	challenges  database.ChallengeRepository
+	scanner     *similarity.Scanner
+	checker     *services.SubmissionChecker
+	publisher   *webhook.Publisher
+	maxCodeSize int
+}
+
+func NewSubmissionHandler(submissions database.SubmissionRepository, challenges database.ChallengeRepository, scanner *similarity.Scanner, checker *services.SubmissionChecker, publisher *webhook.Publisher, maxCodeSize int) *SubmissionHandler {
+	return &SubmissionHandler{submissions: submissions, challenges: challenges, scanner: scanner, checker: checker, publisher: publisher, maxCodeSize: maxCodeSize}
+}
+
+// SubmissionView is a submission as returned to a particular caller.
+// CodeHidden is set when the caller may not read the solution code yet.
+type SubmissionView struct {
+	database.Submission
+	CodeHidden bool `json:"code_hidden,omitempty"`
+}
+
+// codeVisibility answers CodeVisibleTo for the caller, loading each
+// challenge at most once per request.
+type codeVisibility struct {
+	ctx        context.Context
+	challenges database.ChallengeRepository
+	viewer     string
+	loaded     map[string]*database.Challenge
+}
+
+func (h *SubmissionHandler) codeVisibility(c *gin.Context) *codeVisibility {
+	return &codeVisibility{
+		ctx:        c.Request.Context(),
+		challenges: h.challenges,
+		viewer:     authenticatedWallet(c),
+		loaded:     make(map[string]*database.Challenge),
+	}
+}
+
+func (v *codeVisibility) visible(submission *database.Submission) (bool, error) {
+	address := strings.ToLower(submission.ChallengeAddress)
+	challenge, loaded := v.loaded[address]
+	if !loaded {
+		var err error
+		if challenge, err = v.challenges.GetChallenge(v.ctx, address); err != nil {
+			return false, err
+		}
+		v.loaded[address] = challenge
+	}
+
+	return challenge.CodeVisibleTo(v.viewer, submission.WalletAddress), nil
+}
+
+func (h *SubmissionHandler) CreateSubmission(c *gin.Context) {
 	var req SubmissionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindingProblem(c, err)
+		return
+	}
+	if !checkCodeSize(c, req.SolutionCode, h.maxCodeSize) {
 		return
 	}
 
 	submission := &database.Submission{
 		UID:              req.UID,
-		ChallengeAddress: req.ChallengeAddress,
-		WalletAddress:    req.WalletAddress,
+		ChallengeAddress: strings.ToLower(req.ChallengeAddress),
+		WalletAddress:    strings.ToLower(req.WalletAddress),
 		SolutionCode:     req.SolutionCode,
-		Status:           "pending",
+		Status:           database.SubmissionPending,
 	}
 
 	if req.SolutionHash != "" {
-		submission.SolutionHash = sql.NullString{String: req.SolutionHash, Valid: true}
+		hash, _ := validate.SolutionHash(req.SolutionHash)
+		submission.SolutionHash = &hash
 	}
 
-	if err := database.CreateSubmission(submission); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create submission"})
+	// Submissions after the indexed deadline are kept but marked late; the
+	// scheduler does the same for any that arrive before the deadline is
+	// indexed.
+	challenge, err := h.challenges.GetChallenge(c.Request.Context(), submission.ChallengeAddress)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenge")
 		return
 	}
+	if challenge != nil && challenge.Deadline != nil && time.Now().After(*challenge.Deadline) {
+		submission.Status = database.SubmissionLate
+	}
+
+	if err := h.submissions.CreateSubmission(c.Request.Context(), submission); err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to create submission")
+		return
+	}
+
+	processSubmission(c, h.scanner, h.checker, submission)
 
 	c.JSON(http.StatusCreated, submission)
 }
 
-func GetSubmission(c *gin.Context) {
-	uid := c.Param("uid")
+func (h *SubmissionHandler) GetSubmission(c *gin.Context) {
+	uid, ok := uidParam(c)
+	if !ok {
+		return
+	}
 	
-	submission, err := database.GetSubmissionByUID(uid)
+	submission, err := h.submissions.GetSubmissionByUID(c.Request.Context(), uid)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submission"})
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch submission")
 		return
 	}
 	
 	if submission == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		problem(c, http.StatusNotFound, CodeNotFound, "Submission not found")
 		return
 	}
 
-	c.JSON(http.StatusOK, submission)
+	visible, err := h.codeVisibility(c).visible(submission)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenge")
+		return
+	}
+
+	view := SubmissionView{Submission: *submission}
+	if !visible {
+		view.SolutionCode = ""
+		view.CodeHidden = true
+	}
+
+	c.JSON(http.StatusOK, view)
 }
 
-func UpdateSubmissionStatus(c *gin.Context) {
-	uid := c.Param("uid")
+func (h *SubmissionHandler) UpdateSubmissionStatus(c *gin.Context) {
+	uid, ok := uidParam(c)
+	if !ok {
+		return
+	}
 	
 	var req struct {
-		Status       string `json:"status" binding:"required"`
-		SolutionHash string `json:"solution_hash,omitempty"`
+		Status       string `json:"status" binding:"required,oneof=pending verified failed approved rejected late"`
+		SolutionHash string `json:"solution_hash,omitempty" binding:"omitempty,solution_hash"`
 	}
 	
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindingProblem(c, err)
 		return
 	}
+	solutionHash, _ := validate.SolutionHash(req.SolutionHash)
 
-	if err := database.UpdateSubmissionStatus(uid, req.Status, req.SolutionHash); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+	if err := h.submissions.UpdateSubmissionStatus(c.Request.Context(), uid, req.Status, solutionHash); err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to update submission")
 		return
 	}
 
+	submission, err := h.submissions.GetSubmissionByUID(c.Request.Context(), uid)
+	if err != nil {
+		log.Printf("Warning: failed to reload submission %s for webhooks: %v", uid, err)
+	} else if submission != nil {
+		publish(c.Request.Context(), h.publisher, webhook.EventSubmissionStatusChanged, webhook.SubmissionStatusChanged(submission))
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Submission updated successfully"})
 }
 
-func GetUserSubmissions(c *gin.Context) {
-	walletAddress := c.Param("wallet")
-	limitStr := c.DefaultQuery("limit", "10")
-	
-	limit, err := strconv.Atoi(limitStr)
+// submissionFields are the fields a listing can return via ?fields=.
+// solution_code is only loaded when it is asked for.
+var submissionFields = []string{"id", "uid", "challenge_address", "wallet_address", "solution_code", "solution_hash", "status", "created_at", "updated_at"}
+
+func submissionField(submission *database.Submission, field string) interface{} {
+	switch field {
+	case "id":
+		return submission.ID
+	case "uid":
+		return submission.UID
+	case "challenge_address":
+		return submission.ChallengeAddress
+	case "wallet_address":
+		return submission.WalletAddress
+	case "solution_code":
+		return submission.SolutionCode
+	case "solution_hash":
+		return submission.SolutionHash
+	case "status":
+		return submission.Status
+	case "created_at":
+		return submission.CreatedAt
+	case "updated_at":
+		return submission.UpdatedAt
+	}
+	return nil
+}
+
+// parseSubmissionQuery reads the paging, filter and field parameters shared
+// by the submission listings.
+func parseSubmissionQuery(c *gin.Context) (database.SubmissionQuery, []string, error) {
+	query := database.SubmissionQuery{Sort: c.DefaultQuery("sort", database.SortNewest)}
+	if query.Sort != database.SortNewest && query.Sort != database.SortOldest {
+		return query, nil, fmt.Errorf("sort must be newest or oldest")
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	if err != nil || limit <= 0 {
-		limit = 10
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	query.Limit = limit
+
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := database.DecodeSubmissionCursor(token)
+		if err != nil {
+			return query, nil, err
+		}
+		if cursor.Sort != query.Sort {
+			return query, nil, fmt.Errorf("cursor was issued for sort=%s", cursor.Sort)
+		}
+		query.After = cursor
+	}
+
+	if status := c.Query("status"); status != "" {
+		query.Statuses = strings.Split(status, ",")
+	}
+
+	for param, target := range map[string]**time.Time{
+		"created_after":  &query.CreatedAfter,
+		"created_before": &query.CreatedBefore,
+	} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return query, nil, fmt.Errorf("%s must be an RFC 3339 timestamp", param)
+			}
+			*target = &parsed
+		}
+	}
+
+	fields := slices.DeleteFunc(slices.Clone(submissionFields), func(field string) bool {
+		return field == "solution_code"
+	})
+	if requested := c.Query("fields"); requested != "" {
+		fields = strings.Split(requested, ",")
+		for _, field := range fields {
+			if !slices.Contains(submissionFields, field) {
+				return query, nil, fmt.Errorf("unknown field %q", field)
+			}
+		}
+	}
+	query.IncludeCode = slices.Contains(fields, "solution_code")
+
+	return query, fields, nil
+}
+
+// listSubmissions serves one page of a listing. It fetches a row past the
+// limit to learn whether a next page exists.
+func (h *SubmissionHandler) listSubmissions(c *gin.Context, query database.SubmissionQuery, fields []string) {
+	limit := query.Limit
+	query.Limit++
+
+	submissions, err := h.submissions.ListSubmissions(c.Request.Context(), query)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch submissions")
+		return
+	}
+
+	var nextCursor *string
+	if len(submissions) > limit {
+		submissions = submissions[:limit]
+		token := database.CursorAfter(submissions[limit-1], query.Sort).Encode()
+		nextCursor = &token
+	}
+
+	visibility := h.codeVisibility(c)
+	rows := make([]gin.H, 0, len(submissions))
+	for i := range submissions {
+		row := gin.H{}
+		for _, field := range fields {
+			row[field] = submissionField(&submissions[i], field)
+		}
+
+		if query.IncludeCode {
+			visible, err := visibility.visible(&submissions[i])
+			if err != nil {
+				problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenge")
+				return
+			}
+			if !visible {
+				delete(row, "solution_code")
+				row["code_hidden"] = true
+			}
+		}
+
+		rows = append(rows, row)
 	}
 
-	submissions, err := database.GetSubmissionsByWallet(walletAddress, limit)
+	list(c, rows, Page{Limit: limit, NextCursor: nextCursor}, nil)
+}
+
+func (h *SubmissionHandler) GetUserSubmissions(c *gin.Context) {
+	query, fields, err := parseSubmissionQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submissions"})
+		problem(c, http.StatusBadRequest, CodeInvalidQuery, err.Error())
 		return
 	}
+	wallet, ok := addressParam(c, "wallet")
+	if !ok {
+		return
+	}
+	query.WalletAddress = wallet
 
-	c.JSON(http.StatusOK, submissions)
+	h.listSubmissions(c, query, fields)
 }
 
-func GetChallengeSubmissions(c *gin.Context) {
-	challengeAddress := c.Param("address")
-	
-	submissions, err := database.GetSubmissionsByChallenge(challengeAddress)
+func (h *SubmissionHandler) GetChallengeSubmissions(c *gin.Context) {
+	query, fields, err := parseSubmissionQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submissions"})
+		problem(c, http.StatusBadRequest, CodeInvalidQuery, err.Error())
 		return
 	}
+	address, ok := addressParam(c, "address")
+	if !ok {
+		return
+	}
+	query.ChallengeAddress = address
 
-	c.JSON(http.StatusOK, submissions)
-}
\ No newline at end of file
+	h.listSubmissions(c, query, fields)
+}
+
+// processSubmission runs the similarity scanner over a newly stored
+// submission and queues its Lean check. The submission stands even if either
+// fails; an admin rescan or a verifier recheck fills the gap.
+func processSubmission(c *gin.Context, scanner *similarity.Scanner, checker *services.SubmissionChecker, submission *database.Submission) {
+	if _, err := scanner.Scan(c.Request.Context(), submission); err != nil {
+		log.Printf("Warning: similarity scan of submission %s failed: %v", submission.UID, err)
+	}
+	if err := checker.Check(c.Request.Context(), submission); err != nil {
+		log.Printf("Warning: failed to queue check of submission %s: %v", submission.UID, err)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/cyrup/backend/internal/validate"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidators adds the address, uid and solution_hash binding tags,
+// backed by package validate, and makes validation errors name fields by
+// their JSON keys. Bound addresses still need lowercasing.
+func RegisterValidators() error {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unexpected validator engine %T", binding.Validator.Engine())
+	}
+
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	checks := map[string]func(string) error{
+		"address": func(s string) error {
+			_, err := validate.Address(s)
+			return err
+		},
+		"uid": validate.UID,
+		"solution_hash": func(s string) error {
+			_, err := validate.SolutionHash(s)
+			return err
+		},
+	}
+	for tag, check := range checks {
+		err := engine.RegisterValidation(tag, func(field validator.FieldLevel) bool {
+			return check(field.Field().String()) == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addressParam returns the named path parameter as a lowercase address,
+// writing a 400 problem if it is not one.
+func addressParam(c *gin.Context, name string) (string, bool) {
+	address, err := validate.Address(c.Param(name))
+	if err != nil {
+		problem(c, http.StatusBadRequest, CodeInvalidAddress, fmt.Sprintf("%s %v", name, err))
+		return "", false
+	}
+	return address, true
+}
+
+// uidParam returns the :uid path parameter, writing a 400 problem if it is
+// not a valid UID.
+func uidParam(c *gin.Context) (string, bool) {
+	uid := c.Param("uid")
+	if err := validate.UID(uid); err != nil {
+		problem(c, http.StatusBadRequest, CodeInvalidUID, fmt.Sprintf("uid %v", err))
+		return "", false
+	}
+	return uid, true
+}
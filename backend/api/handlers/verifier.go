@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cyrup/backend/api/services"
+	"github.com/cyrup/backend/internal/database"
+	"github.com/gin-gonic/gin"
+)
+
+// VerifierHandler serves the verifier workbench: the queue of submissions
+// awaiting a verifier's review and the reviews they record.
+type VerifierHandler struct {
+	challenges  database.ChallengeRepository
+	submissions database.SubmissionRepository
+	similarity  database.SimilarityRepository
+	reviews     database.ReviewRepository
+	checker     *services.SubmissionChecker
+}
+
+func NewVerifierHandler(repos *database.Repositories, checker *services.SubmissionChecker) *VerifierHandler {
+	return &VerifierHandler{
+		challenges:  repos.Challenges,
+		submissions: repos.Submissions,
+		similarity:  repos.Similarity,
+		reviews:     repos.Reviews,
+		checker:     checker,
+	}
+}
+
+// QueueItem is a submission awaiting review with everything known about
+// it. Verification is nil for submissions that were never checked.
+type QueueItem struct {
+	Submission      database.Submission              `json:"submission"`
+	Verification    *database.SubmissionVerification `json:"verification"`
+	SimilarityFlags []database.SimilarityFlag        `json:"similarity_flags"`
+	Review          *database.VerifierReview         `json:"review"`
+}
+
+type QueueChallenge struct {
+	Challenge   database.Challenge `json:"challenge"`
+	Submissions []QueueItem        `json:"submissions"`
+}
+
+// GetQueue lists the active challenges the wallet verifies and their
+// pending submissions. Only the verifier can read their own queue.
+func (h *VerifierHandler) GetQueue(c *gin.Context) {
+	wallet, ok := addressParam(c, "wallet")
+	if !ok {
+		return
+	}
+	if authenticatedWallet(c) != wallet {
+		problem(c, http.StatusForbidden, CodeForbidden, "A verifier can only read their own queue")
+		return
+	}
+
+	ctx := c.Request.Context()
+	challenges, err := h.challenges.ListChallengesByVerifier(ctx, wallet, database.ChallengeActive)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenges")
+		return
+	}
+
+	reviews, err := h.reviews.ListReviews(ctx, wallet)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch reviews")
+		return
+	}
+	reviewsByUID := make(map[string]*database.VerifierReview, len(reviews))
+	for i := range reviews {
+		reviewsByUID[reviews[i].SubmissionUID] = &reviews[i]
+	}
+
+	queue := make([]QueueChallenge, 0, len(challenges))
+	for _, challenge := range challenges {
+		items, err := h.queueItems(c, challenge.Address, reviewsByUID)
+		if err != nil {
+			problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch submissions")
+			return
+		}
+		queue = append(queue, QueueChallenge{Challenge: challenge, Submissions: items})
+	}
+
+	list(c, queue, Page{}, gin.H{"verifier": wallet})
+}
+
+func (h *VerifierHandler) queueItems(c *gin.Context, challengeAddress string, reviews map[string]*database.VerifierReview) ([]QueueItem, error) {
+	ctx := c.Request.Context()
+
+	verifications, err := h.reviews.ListVerifications(ctx, challengeAddress)
+	if err != nil {
+		return nil, err
+	}
+	verificationsByUID := make(map[string]*database.SubmissionVerification, len(verifications))
+	for i := range verifications {
+		// The raw output repeats the diagnostics; keep the queue compact.
+		verifications[i].Output = ""
+		verificationsByUID[verifications[i].SubmissionUID] = &verifications[i]
+	}
+
+	flags, err := h.similarity.ListSimilarityFlags(ctx, challengeAddress)
+	if err != nil {
+		return nil, err
+	}
+	flagsByUID := make(map[string][]database.SimilarityFlag)
+	for _, flag := range flags {
+		flagsByUID[flag.SubmissionUID] = append(flagsByUID[flag.SubmissionUID], flag)
+	}
+
+	query := database.SubmissionQuery{
+		ChallengeAddress: challengeAddress,
+		Statuses:         []string{database.SubmissionPending},
+		Sort:             database.SortOldest,
+		IncludeCode:      true,
+		Limit:            100,
+	}
+
+	items := []QueueItem{}
+	for {
+		submissions, err := h.submissions.ListSubmissions(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, submission := range submissions {
+			item := QueueItem{
+				Submission:      submission,
+				Verification:    verificationsByUID[submission.UID],
+				SimilarityFlags: flagsByUID[submission.UID],
+				Review:          reviews[submission.UID],
+			}
+			if item.SimilarityFlags == nil {
+				item.SimilarityFlags = []database.SimilarityFlag{}
+			}
+			items = append(items, item)
+		}
+
+		if len(submissions) < query.Limit {
+			return items, nil
+		}
+		cursor := database.CursorAfter(submissions[len(submissions)-1], query.Sort)
+		query.After = &cursor
+	}
+}
+
+type ReviewRequest struct {
+	Recommendation string `json:"recommendation" binding:"required,oneof=approve reject needs_changes"`
+	Notes          string `json:"notes"`
+}
+
+// RecordReview stores the selected verifier's recommendation and notes for
+// a submission. Recording again replaces the earlier review.
+func (h *VerifierHandler) RecordReview(c *gin.Context) {
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingProblem(c, err)
+		return
+	}
+
+	submission, ok := h.verifierSubmission(c)
+	if !ok {
+		return
+	}
+
+	review := &database.VerifierReview{
+		SubmissionUID:    submission.UID,
+		ChallengeAddress: strings.ToLower(submission.ChallengeAddress),
+		VerifierAddress:  authenticatedWallet(c),
+		Recommendation:   req.Recommendation,
+		Notes:            req.Notes,
+	}
+	if err := h.reviews.SaveReview(c.Request.Context(), review); err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to record review")
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// Recheck runs the automatic Lean check of a submission again.
+func (h *VerifierHandler) Recheck(c *gin.Context) {
+	submission, ok := h.verifierSubmission(c)
+	if !ok {
+		return
+	}
+
+	if err := h.checker.Check(c.Request.Context(), submission); err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to queue check")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Check queued", "submission_uid": submission.UID})
+}
+
+// verifierSubmission loads the :uid submission and checks that the caller
+// is the selected verifier of its challenge, responding otherwise.
+func (h *VerifierHandler) verifierSubmission(c *gin.Context) (*database.Submission, bool) {
+	ctx := c.Request.Context()
+
+	uid, ok := uidParam(c)
+	if !ok {
+		return nil, false
+	}
+
+	submission, err := h.submissions.GetSubmissionByUID(ctx, uid)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch submission")
+		return nil, false
+	}
+	if submission == nil {
+		problem(c, http.StatusNotFound, CodeNotFound, "Submission not found")
+		return nil, false
+	}
+
+	challenge, err := h.challenges.GetChallenge(ctx, strings.ToLower(submission.ChallengeAddress))
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch challenge")
+		return nil, false
+	}
+	wallet := authenticatedWallet(c)
+	if challenge == nil || challenge.Verifier == nil || wallet == "" || *challenge.Verifier != wallet {
+		problem(c, http.StatusForbidden, CodeForbidden, "Only the challenge's selected verifier can review its submissions")
+		return nil, false
+	}
+
+	return submission, true
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required,min=1"`
+}
+
+// WebhookHandler manages a wallet's webhook subscriptions and their
+// delivery log. Every endpoint requires a signed wallet, and a wallet only
+// sees its own webhooks.
+type WebhookHandler struct {
+	webhooks database.WebhookRepository
+}
+
+func NewWebhookHandler(webhooks database.WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	owner := authenticatedWallet(c)
+	if owner == "" {
+		problem(c, http.StatusUnauthorized, CodeUnauthorized, "A signed wallet is required to manage webhooks")
+		return
+	}
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingProblem(c, err)
+		return
+	}
+
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		problem(c, http.StatusBadRequest, CodeInvalidRequest, "url must be an absolute http or https URL")
+		return
+	}
+	if err := webhook.CheckTarget(c.Request.Context(), target.Hostname()); err != nil {
+		if errors.Is(err, webhook.ErrForbiddenTarget) {
+			problem(c, http.StatusBadRequest, CodeInvalidRequest, "url must not point at a loopback, private or link-local address")
+		} else {
+			problem(c, http.StatusBadRequest, CodeInvalidRequest, "url host could not be resolved")
+		}
+		return
+	}
+	for _, event := range req.Events {
+		if !slices.Contains(webhook.EventTypes, event) {
+			problemWith(c, http.StatusBadRequest, CodeInvalidRequest, "unknown event "+event, gin.H{"events": webhook.EventTypes})
+			return
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to generate webhook secret")
+		return
+	}
+
+	subscription := &database.Webhook{
+		Owner:  owner,
+		URL:    target.String(),
+		Secret: hex.EncodeToString(secret),
+		Events: slices.Compact(slices.Sorted(slices.Values(req.Events))),
+	}
+	if err := h.webhooks.CreateWebhook(c.Request.Context(), subscription); err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to create webhook")
+		return
+	}
+
+	// The secret is only ever returned here.
+	c.JSON(http.StatusCreated, gin.H{"webhook": subscription, "secret": subscription.Secret})
+}
+
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	owner := authenticatedWallet(c)
+	if owner == "" {
+		problem(c, http.StatusUnauthorized, CodeUnauthorized, "A signed wallet is required to manage webhooks")
+		return
+	}
+
+	webhooks, err := h.webhooks.ListWebhooks(c.Request.Context(), owner)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch webhooks")
+		return
+	}
+
+	list(c, webhooks, Page{}, nil)
+}
+
+// DeleteWebhook deactivates a webhook. Queued deliveries are dropped when
+// they come due; the delivery log stays readable.
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	subscription, ok := h.ownedWebhook(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhooks.DeactivateWebhook(c.Request.Context(), subscription.ID); err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to delete webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deactivated"})
+}
+
+// ListDeliveries returns the webhook's delivery log, newest first.
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	subscription, ok := h.ownedWebhook(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	deliveries, err := h.webhooks.ListDeliveries(c.Request.Context(), subscription.ID, limit)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch deliveries")
+		return
+	}
+
+	list(c, deliveries, Page{Limit: limit}, nil)
+}
+
+// Redeliver queues a delivery to be sent again on the next dispatch, with
+// a fresh set of retries.
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	subscription, ok := h.ownedWebhook(c)
+	if !ok {
+		return
+	}
+	if !subscription.Active {
+		problem(c, http.StatusConflict, CodeConflict, "Webhook is not active")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("delivery"))
+	if err != nil {
+		problem(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid delivery id")
+		return
+	}
+
+	ctx := c.Request.Context()
+	delivery, err := h.webhooks.GetDelivery(ctx, id)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch delivery")
+		return
+	}
+	if delivery == nil || delivery.WebhookID != subscription.ID {
+		problem(c, http.StatusNotFound, CodeNotFound, "Delivery not found")
+		return
+	}
+
+	if err := h.webhooks.Redeliver(ctx, delivery.ID, time.Now()); err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to queue redelivery")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Delivery queued", "delivery_id": delivery.ID})
+}
+
+// ownedWebhook loads the webhook named by :id, writing the error response
+// unless it belongs to the caller.
+func (h *WebhookHandler) ownedWebhook(c *gin.Context) (*database.Webhook, bool) {
+	owner := authenticatedWallet(c)
+	if owner == "" {
+		problem(c, http.StatusUnauthorized, CodeUnauthorized, "A signed wallet is required to manage webhooks")
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		problem(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid webhook id")
+		return nil, false
+	}
+
+	subscription, err := h.webhooks.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		problem(c, http.StatusInternalServerError, CodeInternal, "Failed to fetch webhook")
+		return nil, false
+	}
+	if subscription == nil || subscription.Owner != owner {
+		problem(c, http.StatusNotFound, CodeNotFound, "Webhook not found")
+		return nil, false
+	}
+
+	return subscription, true
+}
+
+// publish queues a webhook event. The action that caused it has already
+// happened, so a failure is only logged.
+func publish(ctx context.Context, publisher *webhook.Publisher, eventType string, data interface{}) {
+	if _, err := publisher.Publish(ctx, eventType, data); err != nil {
+		log.Printf("Warning: failed to queue %s webhooks: %v", eventType, err)
+	}
+}
+
+// publishTo is publish for events only the owner may see.
+func publishTo(ctx context.Context, publisher *webhook.Publisher, owner, eventType string, data interface{}) {
+	if _, err := publisher.PublishTo(ctx, owner, eventType, data); err != nil {
+		log.Printf("Warning: failed to queue %s webhooks: %v", eventType, err)
+	}
+}
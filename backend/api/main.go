@@ -1,27 +1,50 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/cyrup/backend/api/handlers"
 	"github.com/cyrup/backend/api/services"
+	"github.com/cyrup/backend/internal/auth"
 	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/database/memory"
+	"github.com/cyrup/backend/internal/money"
+	"github.com/cyrup/backend/internal/pricing"
+	"github.com/cyrup/backend/internal/project"
+	"github.com/cyrup/backend/internal/queue"
+	"github.com/cyrup/backend/internal/ratelimit"
+	"github.com/cyrup/backend/internal/reputation"
+	"github.com/cyrup/backend/internal/scheduler"
+	"github.com/cyrup/backend/internal/similarity"
+	"github.com/cyrup/backend/internal/webhook"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	// Initialize database
-	if err := database.Initialize(); err != nil {
+	tokens, err := money.LoadTokenRegistry()
+	if err != nil {
+		log.Fatal("Failed to load token registry:", err)
+	}
+
+	repos, closeStore, err := openRepositories(tokens.USDC().Address)
+	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
-	defer database.Close()
+	defer closeStore()
 
 	leanService := services.NewLeanHTTPService()
 
@@ -31,44 +54,144 @@ func main() {
 		log.Printf("Continuing anyway - the lean runner might start later")
 	}
 
-	leanHandler := handlers.NewLeanHandler(leanService)
+	publisher := webhook.NewPublisher(repos.Webhooks)
+	webhookInterval, err := webhook.LoadInterval()
+	if err != nil {
+		log.Fatal("Failed to load webhook interval:", err)
+	}
+	go webhook.NewDispatcher(repos.Webhooks).Run(context.Background(), webhookInterval)
+	webhookHandler := handlers.NewWebhookHandler(repos.Webhooks)
 
-	r := gin.Default()
+	limits, err := ratelimit.LoadConfig()
+	if err != nil {
+		log.Fatal("Failed to load rate limits:", err)
+	}
+	limiter := ratelimit.New(repos.RateLimits, limits)
+	go limiter.Run(context.Background(), time.Minute)
+
+	queueConfig, err := queue.LoadConfig()
+	if err != nil {
+		log.Fatal("Failed to load verification queue config:", err)
+	}
+	verifyQueue := queue.New(queueConfig.Workers)
+	go verifyQueue.Run(context.Background())
+
+	projectLimits, err := project.LoadLimits()
+	if err != nil {
+		log.Fatal("Failed to load project limits:", err)
+	}
+
+	// Bodies carry at most one file or project of code, or a batch of them.
+	maxBody := handlers.MaxBodyBytes(max(limits.MaxCodeBytes, projectLimits.MaxBytes))
+
+	leanHandler := handlers.NewLeanHandler(leanService, publisher, verifyQueue, limiter, limits.MaxCodeBytes, queueConfig.MaxBatchItems, projectLimits)
+
+	tiers, err := reputation.LoadTiers()
+	if err != nil {
+		log.Fatal("Failed to load reputation tiers:", err)
+	}
+	reputationHandler := handlers.NewReputationHandler(repos.Reputation, tiers, tokens, publisher)
+
+	seasons, err := reputation.LoadSeasons()
+	if err != nil {
+		log.Fatal("Failed to load seasons:", err)
+	}
+	prices, err := pricing.LoadPriceSource()
+	if err != nil {
+		log.Fatal("Failed to load price source:", err)
+	}
+	threshold, err := similarity.LoadThreshold()
+	if err != nil {
+		log.Fatal("Failed to load similarity threshold:", err)
+	}
+	scanner := similarity.NewScanner(repos.Submissions, repos.Similarity, threshold)
+	checker := services.NewSubmissionChecker(leanService, verifyQueue, repos.Reviews)
+	leaderboardHandler := handlers.NewLeaderboardHandler(repos, seasons, tokens, prices)
+	submissionHandler := handlers.NewSubmissionHandler(repos.Submissions, repos.Challenges, scanner, checker, publisher, limits.MaxCodeBytes)
+	lifecycle := scheduler.New(repos, publisher, scheduler.SystemClock{})
+	interval, err := scheduler.LoadInterval()
+	if err != nil {
+		log.Fatal("Failed to load scheduler interval:", err)
+	}
+	if err := lifecycle.Sync(context.Background()); err != nil {
+		log.Printf("Warning: failed to schedule existing challenge deadlines: %v", err)
+	}
+	go lifecycle.Run(context.Background(), interval)
+	challengeHandler := handlers.NewChallengeHandler(repos.Challenges, repos.Jobs, lifecycle, publisher)
+	commitmentHandler := handlers.NewCommitmentHandler(repos.Commitments, repos.Challenges, scanner, checker, limits.MaxCodeBytes)
+	similarityHandler := handlers.NewSimilarityHandler(repos.Similarity, repos.Challenges, scanner)
+	verifierHandler := handlers.NewVerifierHandler(repos, checker)
+	adminHandler := handlers.NewAdminHandler(repos.Leaderboard, scanner)
+
+	if err := handlers.RegisterValidators(); err != nil {
+		log.Fatal("Failed to register validators:", err)
+	}
+
+	r := gin.New()
+	r.Use(handlers.RequestID(), gin.Logger(), handlers.Recover())
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(handlers.NoRoute)
+	r.NoMethod(handlers.NoMethod)
+	// Gin trusts every proxy by default, which would let any client pick
+	// its rate-limit key with X-Forwarded-For.
+	var trustedProxies []string
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		trustedProxies = strings.Split(proxies, ",")
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal("Failed to load trusted proxies:", err)
+	}
 
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{"http://localhost:3000", "https://*.railway.app"}
-	config.AllowMethods = []string{"GET", "POST", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept"}
+	config.AllowMethods = []string{"GET", "POST", "DELETE", "OPTIONS"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Wallet-Address", "X-Wallet-Signature", "X-Wallet-Timestamp", "X-Request-ID"}
+	config.ExposeHeaders = []string{"X-Request-ID", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
 	r.Use(cors.New(config))
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "healthy"})
-	})
-
-	api := r.Group("/api")
-	{
-		// LEAN verification endpoints
-		api.POST("/verify", leanHandler.VerifyProof)
-		api.GET("/status/:id", leanHandler.GetStatus)
-		api.GET("/result/:id", leanHandler.GetResult)
-		
-		// Submission endpoints
-		api.POST("/submissions", handlers.CreateSubmission)
-		api.GET("/submissions/:uid", handlers.GetSubmission)
-		api.PUT("/submissions/:uid/status", handlers.UpdateSubmissionStatus)
-		api.GET("/submissions/wallet/:wallet", handlers.GetUserSubmissions)
-		api.GET("/submissions/challenge/:address", handlers.GetChallengeSubmissions)
-		
-		// Leaderboard endpoints
-		api.GET("/leaderboard", handlers.GetLeaderboard)
-		api.GET("/leaderboard/top", handlers.GetTopPerformers)
-		api.GET("/leaderboard/user/:wallet", handlers.GetUserStats)
-		api.POST("/leaderboard/events", handlers.RecordReputationEvent)
-		api.GET("/leaderboard/events/recent", handlers.GetRecentEvents)
-	}
+	handlers.Routes{
+		Lean:              leanHandler,
+		Submissions:       submissionHandler,
+		Challenges:        challengeHandler,
+		Commitments:       commitmentHandler,
+		Similarity:        similarityHandler,
+		Verifier:          verifierHandler,
+		Webhooks:          webhookHandler,
+		Leaderboard:       leaderboardHandler,
+		Reputation:        reputationHandler,
+		Admin:             adminHandler,
+		Wallets:           auth.NewVerifier(auth.DefaultMaxAge),
+		Limiter:           limiter,
+		MaxBodyBytes:      maxBody,
+		MaxBatchBodyBytes: int64(queueConfig.MaxBatchItems) * maxBody,
+	}.Register(r)
 
 	log.Printf("Server starting on port %s", port)
 	if err := r.Run(":" + port); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
-}
\ No newline at end of file
+}
+
+// openRepositories selects the storage backend. DATABASE_DRIVER=memory keeps
+// everything in process, which is handy for local development; anything
+// else uses Postgres. usdc is the USDC token address the leaderboard's USDC
+// columns are summed in.
+func openRepositories(usdc string) (*database.Repositories, func(), error) {
+	if os.Getenv("DATABASE_DRIVER") == "memory" {
+		log.Printf("Using in-memory storage; data will not survive a restart")
+		return memory.NewStore(usdc).Repositories(), func() {}, nil
+	}
+
+	db, err := database.Initialize()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryConfig, err := database.LoadQueryConfig()
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	return database.NewPostgresStore(db, queryConfig, usdc).Repositories(), func() { db.Close() }, nil
+}
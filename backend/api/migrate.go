@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/cyrup/backend/internal/database"
+)
+
+const migrateUsage = `Usage: main migrate <command> [--dry-run]
+
+Commands:
+  status     List migrations and whether they are applied
+  up         Apply all pending migrations
+  down N     Revert the N most recently applied migrations
+`
+
+// runMigrate implements the "migrate" subcommand. It connects without
+// auto-migrating so that status and down see the schema as it is.
+func runMigrate(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the SQL that would run without applying it")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, migrateUsage) }
+
+	if len(args) == 0 {
+		flags.Usage()
+		return 2
+	}
+	command, rest := args[0], args[1:]
+
+	// Accept --dry-run before or after the positional count.
+	var positional []string
+	for len(rest) > 0 {
+		if err := flags.Parse(rest); err != nil {
+			return 2
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		rest = flags.Args()[1:]
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		log.Printf("Failed to connect to database: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Printf("Failed to load migrations: %v", err)
+		return 1
+	}
+	migrator.DryRun = *dryRun
+	migrator.Logf = func(format string, args ...interface{}) {
+		fmt.Printf(format+"\n", args...)
+	}
+
+	switch command {
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Printf("Failed to read migration status: %v", err)
+			return 1
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-32s %s\n", status.Version, status.Name, state)
+		}
+
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			log.Printf("Migration failed: %v", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+
+	case "down":
+		if len(positional) != 1 {
+			flags.Usage()
+			return 2
+		}
+		n, err := strconv.Atoi(positional[0])
+		if err != nil || n <= 0 {
+			fmt.Fprintln(os.Stderr, "down requires a positive number of migrations")
+			return 2
+		}
+		reverted, err := migrator.Down(n)
+		if err != nil {
+			log.Printf("Migration failed: %v", err)
+			return 1
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No applied migrations to revert")
+		}
+
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	return 0
+}
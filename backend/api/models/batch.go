@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+type BatchStatus string
+
+const (
+	BatchQueued    BatchStatus = "queued"
+	BatchRunning   BatchStatus = "running"
+	BatchCompleted BatchStatus = "completed"
+)
+
+// BatchItemRequest is a VerifyRequest with a label: the client's own name
+// for the item, such as a file path, echoed back with its result.
+type BatchItemRequest struct {
+	VerifyRequest
+	Label string `json:"label,omitempty" binding:"max=200"`
+}
+
+type BatchVerifyRequest struct {
+	Items []BatchItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// BatchItem is a batch item with its verification result. The embedded
+// result is the one /api/result/:id serves for the item's ID.
+type BatchItem struct {
+	Index int    `json:"index"`
+	Label string `json:"label,omitempty"`
+	ProofResult
+}
+
+// BatchProgress counts a batch's items by status.
+type BatchProgress struct {
+	Total      int `json:"total"`
+	Queued     int `json:"queued"`
+	Processing int `json:"processing"`
+	Succeeded  int `json:"succeeded"`
+	Failed     int `json:"failed"`
+	TimedOut   int `json:"timed_out"`
+}
+
+type BatchResponse struct {
+	ID          string        `json:"id"`
+	Status      BatchStatus   `json:"status"`
+	Progress    BatchProgress `json:"progress"`
+	CreatedAt   time.Time     `json:"created_at"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+	// Items is only included when the batch is created, to hand out the
+	// item IDs.
+	Items []BatchItem `json:"items,omitempty"`
+}
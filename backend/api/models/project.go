@@ -0,0 +1,21 @@
+package models
+
+import "github.com/cyrup/backend/internal/lean"
+
+type FileStatus string
+
+const (
+	FileBuilt  FileStatus = "built"
+	FileFailed FileStatus = "failed"
+	// FileSkipped files were not built because a module they import
+	// failed, or the build stopped first.
+	FileSkipped FileStatus = "skipped"
+)
+
+// FileResult is how one file of a multi-file project fared in the build.
+type FileResult struct {
+	Path        string           `json:"path"`
+	Module      string           `json:"module"`
+	Status      FileStatus       `json:"status"`
+	Diagnostics lean.Diagnostics `json:"diagnostics"`
+}
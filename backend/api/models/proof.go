@@ -14,9 +14,15 @@ const (
 	StatusTimeout    ProofStatus = "timeout"
 )
 
+// VerifyRequest carries either Code, a single file, or a multi-file
+// project as Files, keyed by path, or as a tar, tar.gz or zip Archive.
+// Entry names a project's root module.
 type VerifyRequest struct {
-	Code    string `json:"code" binding:"required"`
-	Timeout int    `json:"timeout,omitempty"`
+	Code    string            `json:"code,omitempty"`
+	Files   map[string]string `json:"files,omitempty"`
+	Archive []byte            `json:"archive,omitempty"`
+	Entry   string            `json:"entry,omitempty"`
+	Timeout int               `json:"timeout,omitempty"`
 }
 
 type VerifyResponse struct {
@@ -32,6 +38,8 @@ type ProofResult struct {
 	ExecutionTime time.Duration `json:"executionTime,omitempty"`
 	CreatedAt     time.Time     `json:"createdAt"`
 	CompletedAt   *time.Time    `json:"completedAt,omitempty"`
+	// Files is set for multi-file projects.
+	Files []FileResult `json:"files,omitempty"`
 }
 
 type StatusResponse struct {
@@ -1,23 +1,32 @@
 package services
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 )
 
 type DockerService struct {
 	client    *client.Client
 	imageName string
+	// seccompProfile is a seccomp profile in JSON, or empty for Docker's
+	// default profile.
+	seccompProfile string
 }
 
 type LeanResult struct {
@@ -32,10 +41,19 @@ func NewDockerService() (*DockerService, error) {
 		return nil, err
 	}
 
-	return &DockerService{
+	service := &DockerService{
 		client:    cli,
 		imageName: "lean-runner:latest",
-	}, nil
+	}
+	if path := os.Getenv("LEAN_SECCOMP_PROFILE"); path != "" {
+		profile, err := os.ReadFile(path)
+		if err != nil {
+			cli.Close()
+			return nil, fmt.Errorf("failed to read LEAN_SECCOMP_PROFILE: %w", err)
+		}
+		service.seccompProfile = string(profile)
+	}
+	return service, nil
 }
 
 func (s *DockerService) Close() error {
@@ -78,33 +96,50 @@ func (s *DockerService) EnsureImage(ctx context.Context) error {
 	return s.BuildImage(ctx)
 }
 
+// Paths in the proof container. /work is an anonymous volume, so the proof
+// can be copied in before the container starts and the result read back
+// after it exits even though the root filesystem is read-only.
+const (
+	workDir    = "/work"
+	proofFile  = "proof.lean"
+	resultPath = workDir + "/result.json"
+)
+
+const (
+	containerMemory = 1024 * 1024 * 1024
+	containerPids   = 256
+	// maxResultBytes bounds how much of the result file is read.
+	maxResultBytes = 8 << 20
+)
+
+// RunLeanProof checks code in a throwaway lean-runner container. The code
+// is copied in as a file and the runner's check command writes its verdict
+// to a result file, which is read back once the container exits; the logs
+// only serve to explain a missing result.
 func (s *DockerService) RunLeanProof(code string, timeout int) (string, error) {
 	ctx := context.Background()
-	
+
 	if err := s.EnsureImage(ctx); err != nil {
 		return "", fmt.Errorf("failed to ensure image: %w", err)
 	}
 
 	config := &container.Config{
-		Image: s.imageName,
-		Cmd:   []string{"/bin/bash", "-c", fmt.Sprintf("echo '%s' | /scripts/run_lean.sh", strings.ReplaceAll(code, "'", "'\\''"))},
-		Env: []string{
-			fmt.Sprintf("TIMEOUT=%d", timeout),
-		},
-		Tty: false,
+		Image:           s.imageName,
+		Cmd:             []string{"/usr/local/bin/lean-server", "check", "-timeout", strconv.Itoa(timeout), workDir + "/" + proofFile, resultPath},
+		NetworkDisabled: true,
 	}
-
-	hostConfig := &container.HostConfig{
-		AutoRemove: true,
-		Resources: container.Resources{
-			Memory:   1024 * 1024 * 1024,
-			NanoCPUs: 1000000000,
-		},
+	resp, err := s.client.ContainerCreate(ctx, config, s.hostConfig(), nil, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to create container: %w", err)
 	}
+	defer s.client.ContainerRemove(context.Background(), resp.ID, container.RemoveOptions{Force: true, RemoveVolumes: true})
 
-	resp, err := s.client.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
+	proof, err := tarFile(proofFile, code)
 	if err != nil {
-		return "", fmt.Errorf("failed to create container: %w", err)
+		return "", fmt.Errorf("failed to pack proof: %w", err)
+	}
+	if err := s.client.CopyToContainer(ctx, resp.ID, workDir, proof, container.CopyToContainerOptions{}); err != nil {
+		return "", fmt.Errorf("failed to copy proof into container: %w", err)
 	}
 
 	if err := s.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
@@ -115,67 +150,109 @@ func (s *DockerService) RunLeanProof(code string, timeout int) (string, error) {
 	defer cancel()
 
 	statusCh, errCh := s.client.ContainerWait(timeoutCtx, resp.ID, container.WaitConditionNotRunning)
-	
+	var exitCode int64
 	select {
 	case err := <-errCh:
-		if err != nil {
-			s.client.ContainerKill(ctx, resp.ID, "KILL")
-			return "", fmt.Errorf("container error: %w", err)
-		}
-	case <-statusCh:
-		// Container finished
-	case <-timeoutCtx.Done():
 		s.client.ContainerKill(ctx, resp.ID, "KILL")
+		if timeoutCtx.Err() != nil {
+			return "", fmt.Errorf("timeout")
+		}
+		return "", fmt.Errorf("container error: %w", err)
+	case status := <-statusCh:
+		exitCode = status.StatusCode
+	}
+
+	result, err := s.readResult(ctx, resp.ID)
+	if err != nil {
+		return "", fmt.Errorf("lean runner exited with code %d and no result (%v): %s", exitCode, err, s.logs(ctx, resp.ID))
+	}
+
+	switch result.Status {
+	case "success":
+		return result.Output, nil
+	case "timeout":
 		return "", fmt.Errorf("timeout")
+	default:
+		return "", errors.New(result.Error)
 	}
+}
 
-	// Get logs after container finishes
-	logsReader, err := s.client.ContainerLogs(ctx, resp.ID, container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-	})
+// hostConfig sandboxes the proof container: no network, a read-only root
+// filesystem with only /work and a small /tmp writable, no capabilities or
+// privilege escalation, the configured or default seccomp profile, and caps
+// on memory, CPU and processes.
+func (s *DockerService) hostConfig() *container.HostConfig {
+	pids := int64(containerPids)
+	securityOpt := []string{"no-new-privileges:true"}
+	if s.seccompProfile != "" {
+		securityOpt = append(securityOpt, "seccomp="+s.seccompProfile)
+	}
+
+	return &container.HostConfig{
+		NetworkMode:    "none",
+		ReadonlyRootfs: true,
+		CapDrop:        []string{"ALL"},
+		SecurityOpt:    securityOpt,
+		Tmpfs:          map[string]string{"/tmp": "rw,noexec,nosuid,size=64m"},
+		Mounts:         []mount.Mount{{Type: mount.TypeVolume, Target: workDir}},
+		Resources: container.Resources{
+			Memory:     containerMemory,
+			MemorySwap: containerMemory,
+			NanoCPUs:   1000000000,
+			PidsLimit:  &pids,
+		},
+	}
+}
+
+// readResult copies the result file out of the stopped container.
+func (s *DockerService) readResult(ctx context.Context, id string) (*LeanResult, error) {
+	archive, _, err := s.client.CopyFromContainer(ctx, id, resultPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to get logs: %w", err)
+		return nil, err
 	}
-	defer logsReader.Close()
+	defer archive.Close()
 
-	var output bytes.Buffer
-	io.Copy(&output, logsReader)
-	
-	// Clean the output (remove Docker log headers)
-	outputStr := output.String()
-	lines := strings.Split(outputStr, "\n")
-	var cleanLines []string
-	for _, line := range lines {
-		// Skip Docker log header bytes (first 8 bytes of each line)
-		if len(line) > 8 {
-			cleanLines = append(cleanLines, line[8:])
-		} else if line != "" {
-			cleanLines = append(cleanLines, line)
-		}
+	files := tar.NewReader(archive)
+	if _, err := files.Next(); err != nil {
+		return nil, err
 	}
-	outputStr = strings.Join(cleanLines, "\n")
-	outputStr = strings.TrimSpace(outputStr)
-	
-	// Try to parse as JSON
-	if strings.Contains(outputStr, "{") && strings.Contains(outputStr, "}") {
-		// Extract JSON from output
-		startIdx := strings.Index(outputStr, "{")
-		endIdx := strings.LastIndex(outputStr, "}")
-		if startIdx >= 0 && endIdx > startIdx {
-			jsonStr := outputStr[startIdx : endIdx+1]
-			var result LeanResult
-			if err := json.Unmarshal([]byte(jsonStr), &result); err == nil {
-				if result.Status == "success" {
-					return result.Output, nil
-				} else if result.Status == "timeout" {
-					return "", fmt.Errorf("timeout")
-				} else {
-					return "", fmt.Errorf(result.Error)
-				}
-			}
-		}
+	var result LeanResult
+	if err := json.NewDecoder(io.LimitReader(files, maxResultBytes)).Decode(&result); err != nil {
+		return nil, err
 	}
+	return &result, nil
+}
+
+// logs returns what the container wrote, demultiplexed, for error
+// messages.
+func (s *DockerService) logs(ctx context.Context, id string) string {
+	reader, err := s.client.ContainerLogs(ctx, id, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return fmt.Sprintf("logs unavailable: %v", err)
+	}
+	defer reader.Close()
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, reader); err != nil {
+		return fmt.Sprintf("logs unreadable: %v", err)
+	}
+	return strings.TrimSpace(stdout.String() + "\n" + stderr.String())
+}
 
-	return outputStr, nil
-}
\ No newline at end of file
+// tarFile packs content as a one-file tar archive, the form
+// CopyToContainer takes.
+func tarFile(name, content string) (io.Reader, error) {
+	var buf bytes.Buffer
+	archive := tar.NewWriter(&buf)
+	header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: time.Now()}
+	if err := archive.WriteHeader(header); err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(archive, content); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"os"
 	"time"
+
+	"github.com/cyrup/backend/api/models"
 )
 
 type LeanHTTPService struct {
@@ -17,12 +19,17 @@ type LeanHTTPService struct {
 type LeanVerifyRequest struct {
 	Code    string `json:"code"`
 	Timeout int    `json:"timeout"`
+	// Files and Entry send a multi-file project instead of Code.
+	Files map[string]string `json:"files,omitempty"`
+	Entry string            `json:"entry,omitempty"`
 }
 
 type LeanVerifyResponse struct {
 	Status string `json:"status"`
 	Output string `json:"output"`
 	Error  string `json:"error"`
+	// Files is set for projects.
+	Files []models.FileResult `json:"files,omitempty"`
 }
 
 func NewLeanHTTPService() *LeanHTTPService {
@@ -42,14 +49,35 @@ func NewLeanHTTPService() *LeanHTTPService {
 }
 
 func (s *LeanHTTPService) RunLeanProof(code string, timeout int) (string, error) {
-	req := LeanVerifyRequest{
+	result, err := s.Verify(code, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	switch result.Status {
+	case "success":
+		return result.Output, nil
+	case "timeout":
+		return "", fmt.Errorf("timeout")
+	default:
+		return "", fmt.Errorf(result.Error)
+	}
+}
+
+// Verify runs code on the lean runner and returns its response as is, so
+// callers can read the compiler output whatever the outcome.
+func (s *LeanHTTPService) Verify(code string, timeout int) (*LeanVerifyResponse, error) {
+	return s.Run(LeanVerifyRequest{
 		Code:    code,
 		Timeout: timeout,
-	}
+	})
+}
 
+// Run sends req to the lean runner and returns its response as is.
+func (s *LeanHTTPService) Run(req LeanVerifyRequest) (*LeanVerifyResponse, error) {
 	jsonData, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	resp, err := s.client.Post(
@@ -58,23 +86,16 @@ func (s *LeanHTTPService) RunLeanProof(code string, timeout int) (string, error)
 		bytes.NewBuffer(jsonData),
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to call lean runner: %w", err)
+		return nil, fmt.Errorf("failed to call lean runner: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var result LeanVerifyResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	switch result.Status {
-	case "success":
-		return result.Output, nil
-	case "timeout":
-		return "", fmt.Errorf("timeout")
-	default:
-		return "", fmt.Errorf(result.Error)
-	}
+	return &result, nil
 }
 
 func (s *LeanHTTPService) HealthCheck() error {
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/lean"
+	"github.com/cyrup/backend/internal/queue"
+)
+
+// submissionCheckTimeout is the lean runner timeout for automatic checks,
+// in seconds.
+const submissionCheckTimeout = 60
+
+// SubmissionChecker runs submissions through the lean runner in the
+// background and stores the verdict, diagnostics and axiom audit for the
+// verifier workbench.
+type SubmissionChecker struct {
+	lean    *LeanHTTPService
+	queue   *queue.Queue
+	reviews database.ReviewRepository
+}
+
+func NewSubmissionChecker(leanService *LeanHTTPService, jobs *queue.Queue, reviews database.ReviewRepository) *SubmissionChecker {
+	return &SubmissionChecker{lean: leanService, queue: jobs, reviews: reviews}
+}
+
+// Check marks the submission's check as pending and queues it in the
+// interactive lane. Checks lost to a restart stay pending until rechecked.
+func (c *SubmissionChecker) Check(ctx context.Context, submission *database.Submission) error {
+	pending := &database.SubmissionVerification{
+		SubmissionUID: submission.UID,
+		Verdict:       lean.VerdictPending,
+	}
+	if err := c.reviews.SaveVerification(ctx, pending); err != nil {
+		return err
+	}
+
+	uid, code := submission.UID, submission.SolutionCode
+	c.queue.Submit(func() { c.run(uid, code) })
+	return nil
+}
+
+// run checks code as a project: the submission is one module and the
+// axiom reports come from a separate file that imports it. Commands that
+// could fake those reports are rejected before anything runs.
+func (c *SubmissionChecker) run(uid, code string) {
+	verification := &database.SubmissionVerification{
+		SubmissionUID: uid,
+		Diagnostics:   lean.Diagnostics{},
+	}
+	if rejected := lean.CheckCommands(code); len(rejected) > 0 {
+		verification.Verdict = lean.VerdictFailed
+		verification.Diagnostics = rejected
+		verification.Output = rejected.String()
+	} else {
+		c.verify(verification, code)
+	}
+	checkedAt := time.Now()
+	verification.CheckedAt = &checkedAt
+
+	if err := c.reviews.SaveVerification(context.Background(), verification); err != nil {
+		log.Printf("Warning: failed to store check of submission %s: %v", uid, err)
+	}
+}
+
+func (c *SubmissionChecker) verify(verification *database.SubmissionVerification, code string) {
+	files, entry, theorems := lean.AuditProject(code)
+	response, err := c.lean.Run(LeanVerifyRequest{Files: files, Entry: entry, Timeout: submissionCheckTimeout})
+	if err != nil {
+		verification.Verdict = lean.VerdictError
+		verification.Output = err.Error()
+		return
+	}
+
+	output := response.Output
+	if response.Status != lean.RunnerSuccess {
+		output = response.Error
+	}
+	verification.Output = output
+
+	var audit lean.Diagnostics
+	for _, file := range response.Files {
+		switch file.Path {
+		case lean.SubmissionFile:
+			verification.Diagnostics = file.Diagnostics
+		case lean.AuditFile:
+			audit = file.Diagnostics
+		}
+	}
+	// The runner reports no files when it could not build the project.
+	if len(response.Files) == 0 {
+		verification.Diagnostics = lean.ParseDiagnostics(output)
+	}
+	verification.Axioms = lean.AuditAxioms(audit, theorems)
+	verification.Verdict = lean.Evaluate(response.Status, verification.Diagnostics, verification.Axioms)
+}
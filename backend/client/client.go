@@ -0,0 +1,159 @@
+// Package client is a Go client for the Cyrup API. The types and methods in
+// client_gen.go are generated from internal/openapi/openapi.json; run
+// go generate after changing the document.
+package client
+
+//go:generate go run ../cmd/openapi-gen -spec ../internal/openapi/openapi.json -out client_gen.go -package client -skip Problem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cyrup/backend/internal/auth"
+)
+
+// Client calls the API at BaseURL.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	// Prepare, when set, runs on every request before it is sent. Use it to
+	// add the wallet signature headers or the admin bearer token.
+	Prepare func(*http.Request) error
+}
+
+// New returns a client for the API at baseURL, such as
+// http://localhost:8080.
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Wallet returns a Prepare function that signs every request with the
+// wallet's hex private key, and the wallet's address.
+func Wallet(privateKey string) (func(*http.Request) error, string, error) {
+	signer, err := auth.NewSigner(privateKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prepare := func(req *http.Request) error {
+		for name, value := range signer.Headers(time.Now()) {
+			req.Header.Set(name, value)
+		}
+		return nil
+	}
+	return prepare, signer.Address(), nil
+}
+
+// AdminToken returns a Prepare function that sends the ADMIN_TOKEN bearer
+// token the /api/admin endpoints require.
+func AdminToken(token string) func(*http.Request) error {
+	return func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// Problem is an application/problem+json error returned by the API.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Instance  string       `json:"instance"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id"`
+	Errors    []FieldError `json:"errors,omitempty"`
+
+	// Extensions holds every member of the problem, including ones such as
+	// retry_after or expected that only some codes carry.
+	Extensions map[string]json.RawMessage `json:"-"`
+}
+
+func (p *Problem) Error() string {
+	message := fmt.Sprintf("%d %s: %s", p.Status, p.Code, p.Detail)
+	for _, fieldErr := range p.Errors {
+		message += fmt.Sprintf("; %s: %s", fieldErr.Field, fieldErr.Message)
+	}
+	return message
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Prepare != nil {
+		if err := c.Prepare(req); err != nil {
+			return err
+		}
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return decodeProblem(resp.StatusCode, data)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// decodeProblem turns an error response into a *Problem, falling back to
+// the status text when the body is not a problem document.
+func decodeProblem(status int, data []byte) error {
+	p := &Problem{Status: status}
+	if err := json.Unmarshal(data, p); err != nil || p.Code == "" {
+		p.Status = status
+		p.Title = http.StatusText(status)
+		p.Detail = strings.TrimSpace(string(data))
+		return p
+	}
+	json.Unmarshal(data, &p.Extensions)
+	return p
+}
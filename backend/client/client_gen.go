@@ -0,0 +1,1464 @@
+// Code generated by openapi-gen from the OpenAPI document. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AxiomAudit struct {
+	Declarations map[string][]string `json:"declarations"`
+	Missing      []string            `json:"missing,omitempty"`
+	Nonstandard  []string            `json:"nonstandard"`
+	UsesSorry    bool                `json:"uses_sorry"`
+}
+
+type BatchItem struct {
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Error       string     `json:"error,omitempty"`
+	// Nanoseconds.
+	ExecutionTime *int64 `json:"executionTime,omitempty"`
+	// Set for multi-file projects, in path order.
+	Files  []FileResult `json:"files,omitempty"`
+	ID     string       `json:"id"`
+	Index  int          `json:"index"`
+	Label  string       `json:"label,omitempty"`
+	Output string       `json:"output,omitempty"`
+	Status ProofStatus  `json:"status"`
+}
+
+type BatchItemList struct {
+	BatchID string      `json:"batch_id"`
+	Data    []BatchItem `json:"data"`
+	Page    Page        `json:"page"`
+}
+
+// BatchItemRequest: A VerifyRequest with a label. Like a single
+// verification, an item is code, files or an archive.
+type BatchItemRequest struct {
+	// A tar, tar.gz or zip archive of the project. Only .lean files outside
+	// dot directories are taken, and the root lakefile.lean is ignored.
+	Archive []byte `json:"archive,omitempty"`
+	Code    string `json:"code,omitempty"`
+	// The project's root module, such as Main. Required when the project has
+	// more than one file.
+	Entry string `json:"entry,omitempty"`
+	// Project files keyed by path from the workspace root; A/B.lean holds
+	// module A.B. At most MAX_PROJECT_FILES files and MAX_PROJECT_BYTES in
+	// total.
+	Files map[string]string `json:"files,omitempty"`
+	// The client's name for the item, echoed with its result.
+	Label string `json:"label,omitempty"`
+	// Milliseconds, at most 60000. Defaults to 30 seconds.
+	Timeout *int `json:"timeout,omitempty"`
+}
+
+type BatchProgress struct {
+	Failed     int `json:"failed"`
+	Processing int `json:"processing"`
+	Queued     int `json:"queued"`
+	Succeeded  int `json:"succeeded"`
+	TimedOut   int `json:"timed_out"`
+	Total      int `json:"total"`
+}
+
+type BatchResponse struct {
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ID          string     `json:"id"`
+	// Only when the batch is created.
+	Items    []BatchItem   `json:"items,omitempty"`
+	Progress BatchProgress `json:"progress"`
+	Status   BatchStatus   `json:"status"`
+}
+
+type BatchStatus string
+
+const (
+	BatchStatusQueued    BatchStatus = "queued"
+	BatchStatusRunning   BatchStatus = "running"
+	BatchStatusCompleted BatchStatus = "completed"
+)
+
+type BatchVerifyRequest struct {
+	// At most VERIFY_BATCH_MAX_ITEMS (default 100) items.
+	Items []BatchItemRequest `json:"items"`
+}
+
+type Challenge struct {
+	Address     string     `json:"address"`
+	ChallengeID *int64     `json:"challenge_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Creator     string     `json:"creator"`
+	Deadline    *time.Time `json:"deadline,omitempty"`
+	// Decimal amount in whole token units.
+	RewardRaw    string    `json:"reward_raw,omitempty"`
+	Status       string    `json:"status"`
+	TokenAddress string    `json:"token_address,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Verifier     string    `json:"verifier,omitempty"`
+}
+
+type ChallengeEventRequest struct {
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	ChallengeAddress string `json:"challenge_address"`
+	ChallengeID      *int64 `json:"challenge_id,omitempty"`
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	Creator string `json:"creator,omitempty"`
+	// Unix seconds.
+	Deadline *int64 `json:"deadline,omitempty"`
+	Event    string `json:"event"`
+	// Integer in token base units.
+	Reward string `json:"reward,omitempty"`
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	TokenAddress string `json:"token_address,omitempty"`
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	Verifier string `json:"verifier,omitempty"`
+}
+
+type ChallengeEventResult struct {
+	Challenge Challenge `json:"challenge"`
+	Changed   bool      `json:"changed"`
+}
+
+type ChallengeList struct {
+	Data []Challenge `json:"data"`
+	Page Page        `json:"page"`
+}
+
+type CheckQueued struct {
+	Message       string `json:"message"`
+	SubmissionUID string `json:"submission_uid"`
+}
+
+type CommitRequest struct {
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	ChallengeAddress string `json:"challenge_address"`
+	Commitment       string `json:"commitment"`
+}
+
+type Commitment struct {
+	ChallengeAddress string     `json:"challenge_address"`
+	Commitment       string     `json:"commitment"`
+	CommittedAt      time.Time  `json:"committed_at"`
+	ID               int        `json:"id"`
+	RevealedAt       *time.Time `json:"revealed_at,omitempty"`
+	SubmissionUID    string     `json:"submission_uid,omitempty"`
+	WalletAddress    string     `json:"wallet_address"`
+}
+
+type CommitmentList struct {
+	Data []CommitmentView `json:"data"`
+	Page Page             `json:"page"`
+}
+
+type CommitmentView struct {
+	ChallengeAddress string    `json:"challenge_address"`
+	Commitment       string    `json:"commitment"`
+	CommittedAt      time.Time `json:"committed_at"`
+	ID               int       `json:"id"`
+	// Rank among revealed commitments; null until revealed.
+	Priority      *int       `json:"priority"`
+	RevealedAt    *time.Time `json:"revealed_at,omitempty"`
+	SubmissionUID string     `json:"submission_uid,omitempty"`
+	WalletAddress string     `json:"wallet_address"`
+}
+
+type ConsistencyReport struct {
+	Consistent     bool               `json:"consistent"`
+	Drift          []LeaderboardDrift `json:"drift"`
+	EventsReplayed int                `json:"events_replayed"`
+	Gaps           []EventGap         `json:"gaps"`
+	WalletsChecked int                `json:"wallets_checked"`
+}
+
+type CreatedWebhook struct {
+	// HMAC key for X-Cyrup-Signature. Only returned here.
+	Secret  string  `json:"secret"`
+	Webhook Webhook `json:"webhook"`
+}
+
+type DeadlineList struct {
+	Data []Challenge `json:"data"`
+	Page Page        `json:"page"`
+	// The window searched, as a Go duration.
+	Within string `json:"within"`
+}
+
+type DeliveryQueued struct {
+	DeliveryID int    `json:"delivery_id"`
+	Message    string `json:"message"`
+}
+
+type Diagnostic struct {
+	Column   *int   `json:"column,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     *int   `json:"line,omitempty"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+type EventGap struct {
+	BlockNumber     int64  `json:"block_number"`
+	EventID         int    `json:"event_id"`
+	ReplayedTotal   int    `json:"replayed_total"`
+	ReportedTotal   int    `json:"reported_total"`
+	TransactionHash string `json:"transaction_hash,omitempty"`
+	WalletAddress   string `json:"wallet_address"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type FileResult struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Module      string       `json:"module"`
+	Path        string       `json:"path"`
+	Status      FileStatus   `json:"status"`
+}
+
+// FileStatus: How a file fared in the build. Skipped files were not built
+// because a module they import failed.
+type FileStatus string
+
+const (
+	FileStatusBuilt   FileStatus = "built"
+	FileStatusFailed  FileStatus = "failed"
+	FileStatusSkipped FileStatus = "skipped"
+)
+
+type Health struct {
+	Status string `json:"status"`
+}
+
+// Leaderboard: Rows are LeaderboardEntry by default,
+// SolverLeaderboardEntry or VerifierLeaderboardEntry with ?role=, and
+// WindowedLeaderboardEntry with ?window=.
+type Leaderboard struct {
+	Data   []json.RawMessage `json:"data"`
+	Page   Page              `json:"page"`
+	Role   string            `json:"role"`
+	Window *Window           `json:"window,omitempty"`
+}
+
+type LeaderboardDrift struct {
+	Field         string          `json:"field"`
+	Projected     json.RawMessage `json:"projected"`
+	Stored        json.RawMessage `json:"stored"`
+	WalletAddress string          `json:"wallet_address"`
+}
+
+type LeaderboardEntry struct {
+	ChallengesVerified int       `json:"challenges_verified"`
+	ChallengesWon      int       `json:"challenges_won"`
+	ID                 int       `json:"id"`
+	LastUpdated        time.Time `json:"last_updated"`
+	ReputationScore    int       `json:"reputation_score"`
+	// Decimal amount in whole token units.
+	TotalUSDCWon string `json:"total_usdc_won"`
+	// Decimal amount in whole token units.
+	VerifierFeesEarned string `json:"verifier_fees_earned"`
+	VerifierPoints     int    `json:"verifier_points"`
+	WalletAddress      string `json:"wallet_address"`
+}
+
+type LeaderboardEntryList struct {
+	Data []LeaderboardEntry `json:"data"`
+	Page Page               `json:"page"`
+}
+
+type Message struct {
+	Message string `json:"message"`
+}
+
+type Notification struct {
+	ChallengeAddress string    `json:"challenge_address"`
+	CreatedAt        time.Time `json:"created_at"`
+	ID               int       `json:"id"`
+	Kind             string    `json:"kind"`
+	Message          string    `json:"message"`
+}
+
+type NotificationList struct {
+	Data []Notification `json:"data"`
+	Page Page           `json:"page"`
+}
+
+// Page: Which part of a list a response holds. limit, offset and
+// next_cursor appear on endpoints paged that way.
+type Page struct {
+	Count int  `json:"count"`
+	Limit *int `json:"limit,omitempty"`
+	// Absent on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	Offset     *int   `json:"offset,omitempty"`
+}
+
+type PointsPreview struct {
+	Amount     int64 `json:"amount"`
+	IsVerifier bool  `json:"is_verifier"`
+	Points     int   `json:"points"`
+}
+
+type ProofResult struct {
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Error       string     `json:"error,omitempty"`
+	// Nanoseconds.
+	ExecutionTime *int64 `json:"executionTime,omitempty"`
+	// Set for multi-file projects, in path order.
+	Files  []FileResult `json:"files,omitempty"`
+	ID     string       `json:"id"`
+	Output string       `json:"output,omitempty"`
+	Status ProofStatus  `json:"status"`
+}
+
+type ProofStatus string
+
+const (
+	ProofStatusQueued     ProofStatus = "queued"
+	ProofStatusProcessing ProofStatus = "processing"
+	ProofStatusSuccess    ProofStatus = "success"
+	ProofStatusError      ProofStatus = "error"
+	ProofStatusTimeout    ProofStatus = "timeout"
+)
+
+type Qualification struct {
+	ComputedThreshold int     `json:"computed_threshold"`
+	Gap               int     `json:"gap"`
+	Percentile        float64 `json:"percentile"`
+	Points            int     `json:"points"`
+	Qualified         bool    `json:"qualified"`
+	Threshold         int     `json:"threshold"`
+	ThresholdSource   string  `json:"threshold_source"`
+	TotalUsers        int     `json:"total_users"`
+	WalletAddress     string  `json:"wallet_address"`
+}
+
+type QueueChallenge struct {
+	Challenge   Challenge   `json:"challenge"`
+	Submissions []QueueItem `json:"submissions"`
+}
+
+type QueueItem struct {
+	Review          *VerifierReview         `json:"review"`
+	SimilarityFlags []SimilarityFlag        `json:"similarity_flags"`
+	Submission      Submission              `json:"submission"`
+	Verification    *SubmissionVerification `json:"verification"`
+}
+
+type RebuildResult struct {
+	EventsReplayed int    `json:"events_replayed"`
+	Message        string `json:"message"`
+}
+
+type ReputationEvent struct {
+	// Decimal amount in whole token units.
+	AmountRaw       string     `json:"amount_raw"`
+	BlockNumber     *int64     `json:"block_number,omitempty"`
+	BlockTimestamp  *time.Time `json:"block_timestamp,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EventType       string     `json:"event_type"`
+	ID              int        `json:"id"`
+	IsVerifier      bool       `json:"is_verifier"`
+	LogIndex        *int       `json:"log_index,omitempty"`
+	PointsAdded     int        `json:"points_added"`
+	TokenAddress    string     `json:"token_address"`
+	TotalPoints     int        `json:"total_points"`
+	TransactionHash string     `json:"transaction_hash,omitempty"`
+	// Decimal amount in whole token units.
+	USDCAmount    string `json:"usdc_amount"`
+	WalletAddress string `json:"wallet_address"`
+}
+
+type ReputationEventList struct {
+	Data []ReputationEvent `json:"data"`
+	Page Page              `json:"page"`
+}
+
+type ReputationEventRequest struct {
+	// uint256 in base units of token_address.
+	AmountRaw   string `json:"amount_raw,omitempty"`
+	BlockNumber *int64 `json:"block_number,omitempty"`
+	// Unix seconds.
+	BlockTimestamp *int64 `json:"block_timestamp,omitempty"`
+	IsVerifier     *bool  `json:"is_verifier,omitempty"`
+	LogIndex       int    `json:"log_index"`
+	PointsAdded    *int   `json:"points_added,omitempty"`
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	TokenAddress    string `json:"token_address,omitempty"`
+	TotalPoints     *int   `json:"total_points,omitempty"`
+	TransactionHash string `json:"transaction_hash"`
+	// Decimal amount in whole token units.
+	USDCAmount string `json:"usdc_amount,omitempty"`
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	WalletAddress string `json:"wallet_address"`
+}
+
+// ReputationEventResult: event is set when the event was new; duplicate
+// when it was already recorded.
+type ReputationEventResult struct {
+	Duplicate *bool            `json:"duplicate,omitempty"`
+	Event     *ReputationEvent `json:"event,omitempty"`
+	Message   string           `json:"message"`
+}
+
+type RescanResult struct {
+	Message            string `json:"message"`
+	SubmissionsScanned int    `json:"submissions_scanned"`
+}
+
+type RevealRequest struct {
+	Commitment   string `json:"commitment"`
+	Salt         string `json:"salt"`
+	SolutionCode string `json:"solution_code"`
+	UID          string `json:"uid"`
+}
+
+type RevealResult struct {
+	CommittedAt time.Time  `json:"committed_at"`
+	Submission  Submission `json:"submission"`
+}
+
+type ReviewRequest struct {
+	Notes          string `json:"notes,omitempty"`
+	Recommendation string `json:"recommendation"`
+}
+
+type ScheduledJob struct {
+	Attempts         int        `json:"attempts"`
+	ChallengeAddress string     `json:"challenge_address"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ID               int        `json:"id"`
+	Kind             string     `json:"kind"`
+	LastError        string     `json:"last_error,omitempty"`
+	LockedUntil      *time.Time `json:"locked_until,omitempty"`
+	RunAt            time.Time  `json:"run_at"`
+	Status           string     `json:"status"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type ScheduledJobList struct {
+	Data []ScheduledJob `json:"data"`
+	Page Page           `json:"page"`
+}
+
+type Season struct {
+	End   time.Time `json:"end"`
+	ID    string    `json:"id"`
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+}
+
+type SeasonList struct {
+	Data []Season `json:"data"`
+	Page Page     `json:"page"`
+}
+
+type SimilarityFlag struct {
+	ChallengeAddress string    `json:"challenge_address"`
+	CreatedAt        time.Time `json:"created_at"`
+	ID               int       `json:"id"`
+	MatchedUID       string    `json:"matched_uid"`
+	MatchedWallet    string    `json:"matched_wallet"`
+	Score            float64   `json:"score"`
+	SubmissionUID    string    `json:"submission_uid"`
+	WalletAddress    string    `json:"wallet_address"`
+}
+
+type SimilarityReport struct {
+	ChallengeAddress string           `json:"challenge_address"`
+	Data             []SimilarityFlag `json:"data"`
+	Page             Page             `json:"page"`
+	Threshold        float64          `json:"threshold"`
+}
+
+type SolutionApproval struct {
+	Approver         string     `json:"approver"`
+	BlockNumber      *int64     `json:"block_number,omitempty"`
+	BlockTimestamp   *time.Time `json:"block_timestamp,omitempty"`
+	ChallengeAddress string     `json:"challenge_address"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ID               int        `json:"id"`
+	IsVerifier       bool       `json:"is_verifier"`
+	LogIndex         int        `json:"log_index"`
+	SubmissionID     int64      `json:"submission_id"`
+	SubmissionUID    string     `json:"submission_uid,omitempty"`
+	TransactionHash  string     `json:"transaction_hash"`
+}
+
+type SolutionApprovalRequest struct {
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	Approver    string `json:"approver"`
+	BlockNumber *int64 `json:"block_number,omitempty"`
+	// Unix seconds.
+	BlockTimestamp *int64 `json:"block_timestamp,omitempty"`
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	ChallengeAddress string `json:"challenge_address"`
+	IsVerifier       *bool  `json:"is_verifier,omitempty"`
+	LogIndex         int    `json:"log_index"`
+	SubmissionID     int64  `json:"submission_id"`
+	SubmissionUID    string `json:"submission_uid,omitempty"`
+	TransactionHash  string `json:"transaction_hash"`
+}
+
+// SolutionApprovalResult: approval is set when the event was new;
+// duplicate when it was already recorded.
+type SolutionApprovalResult struct {
+	Approval  *SolutionApproval `json:"approval,omitempty"`
+	Duplicate *bool             `json:"duplicate,omitempty"`
+	Message   string            `json:"message"`
+}
+
+type SolverLeaderboardEntry struct {
+	ChallengesEntered int `json:"challenges_entered"`
+	SolverPoints      int `json:"solver_points"`
+	// Decimal amount in whole token units.
+	TotalUSDCWon  string   `json:"total_usdc_won"`
+	WalletAddress string   `json:"wallet_address"`
+	WinRate       *float64 `json:"win_rate"`
+	Wins          int      `json:"wins"`
+}
+
+type StatusResponse struct {
+	ID     string      `json:"id"`
+	Status ProofStatus `json:"status"`
+}
+
+type Submission struct {
+	ChallengeAddress string    `json:"challenge_address"`
+	CreatedAt        time.Time `json:"created_at"`
+	ID               int       `json:"id"`
+	SolutionCode     string    `json:"solution_code"`
+	SolutionHash     string    `json:"solution_hash,omitempty"`
+	Status           string    `json:"status"`
+	UID              string    `json:"uid"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	WalletAddress    string    `json:"wallet_address"`
+}
+
+type SubmissionList struct {
+	Data []SubmissionSummary `json:"data"`
+	Page Page                `json:"page"`
+}
+
+type SubmissionRequest struct {
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	ChallengeAddress string `json:"challenge_address"`
+	SolutionCode     string `json:"solution_code"`
+	// 0x-prefixed 32-byte hash or IPFS CID.
+	SolutionHash string `json:"solution_hash,omitempty"`
+	UID          string `json:"uid"`
+	// 20-byte hex address; mixed case must be a valid EIP-55 checksum.
+	// Responses are lowercase.
+	WalletAddress string `json:"wallet_address"`
+}
+
+type SubmissionStatusUpdate struct {
+	SolutionHash string `json:"solution_hash,omitempty"`
+	Status       string `json:"status"`
+}
+
+// SubmissionSummary: A submission listing row. Only the fields requested
+// through ?fields= are present.
+type SubmissionSummary struct {
+	ChallengeAddress string `json:"challenge_address,omitempty"`
+	// Set when solution_code is withheld from the caller.
+	CodeHidden    *bool      `json:"code_hidden,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	ID            *int       `json:"id,omitempty"`
+	SolutionCode  string     `json:"solution_code,omitempty"`
+	SolutionHash  string     `json:"solution_hash,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	UID           string     `json:"uid,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	WalletAddress string     `json:"wallet_address,omitempty"`
+}
+
+type SubmissionVerification struct {
+	Axioms        AxiomAudit   `json:"axioms"`
+	CheckedAt     *time.Time   `json:"checked_at,omitempty"`
+	Diagnostics   []Diagnostic `json:"diagnostics"`
+	Output        string       `json:"output,omitempty"`
+	SubmissionUID string       `json:"submission_uid"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	Verdict       string       `json:"verdict"`
+}
+
+type SubmissionView struct {
+	ChallengeAddress string `json:"challenge_address"`
+	// Set when solution_code is withheld from the caller.
+	CodeHidden    *bool     `json:"code_hidden,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	ID            int       `json:"id"`
+	SolutionCode  string    `json:"solution_code"`
+	SolutionHash  string    `json:"solution_hash,omitempty"`
+	Status        string    `json:"status"`
+	UID           string    `json:"uid"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	WalletAddress string    `json:"wallet_address"`
+}
+
+type ThresholdUpdate struct {
+	BlockNumber     int64      `json:"block_number"`
+	BlockTimestamp  *time.Time `json:"block_timestamp,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ID              int        `json:"id"`
+	LogIndex        int        `json:"log_index"`
+	NewThreshold    int        `json:"new_threshold"`
+	OldThreshold    int        `json:"old_threshold"`
+	TotalUsers      int        `json:"total_users"`
+	TransactionHash string     `json:"transaction_hash"`
+}
+
+type ThresholdUpdateList struct {
+	Data []ThresholdUpdate `json:"data"`
+	Page Page              `json:"page"`
+}
+
+type ThresholdUpdateRequest struct {
+	BlockNumber int64 `json:"block_number"`
+	// Unix seconds.
+	BlockTimestamp  *int64 `json:"block_timestamp,omitempty"`
+	LogIndex        int    `json:"log_index"`
+	NewThreshold    *int   `json:"new_threshold,omitempty"`
+	OldThreshold    *int   `json:"old_threshold,omitempty"`
+	TotalUsers      int    `json:"total_users"`
+	TransactionHash string `json:"transaction_hash"`
+}
+
+// ThresholdUpdateResult: update is set when the event was new; duplicate
+// when it was already recorded.
+type ThresholdUpdateResult struct {
+	Duplicate *bool            `json:"duplicate,omitempty"`
+	Message   string           `json:"message"`
+	Update    *ThresholdUpdate `json:"update,omitempty"`
+}
+
+type Token struct {
+	Address  string `json:"address"`
+	Decimals int    `json:"decimals"`
+	Symbol   string `json:"symbol"`
+}
+
+type TokenList struct {
+	Data []Token `json:"data"`
+	Page Page    `json:"page"`
+	USDC string  `json:"usdc"`
+}
+
+type TokenWinnings struct {
+	// Decimal amount in whole token units.
+	AmountWon string `json:"amount_won"`
+	Decimals  int    `json:"decimals"`
+	// Decimal amount in whole token units.
+	FeesEarned   string    `json:"fees_earned"`
+	LastUpdated  time.Time `json:"last_updated"`
+	Symbol       string    `json:"symbol"`
+	TokenAddress string    `json:"token_address"`
+	// Decimal amount in whole token units.
+	USDValue      *string `json:"usd_value"`
+	Verifications int     `json:"verifications"`
+	WalletAddress string  `json:"wallet_address"`
+	Wins          int     `json:"wins"`
+}
+
+type UserStats struct {
+	Position int              `json:"position"`
+	Stats    LeaderboardEntry `json:"stats"`
+	// Decimal amount in whole token units.
+	TotalUSD       *string         `json:"total_usd"`
+	UnpricedTokens []string        `json:"unpriced_tokens"`
+	Winnings       []TokenWinnings `json:"winnings"`
+}
+
+type VerifierLeaderboardEntry struct {
+	AvgApprovalLatencySeconds *float64 `json:"avg_approval_latency_seconds"`
+	// Decimal amount in whole token units.
+	FeesEarned     string `json:"fees_earned"`
+	Verifications  int    `json:"verifications"`
+	VerifierPoints int    `json:"verifier_points"`
+	WalletAddress  string `json:"wallet_address"`
+}
+
+type VerifierQueue struct {
+	Data     []QueueChallenge `json:"data"`
+	Page     Page             `json:"page"`
+	Verifier string           `json:"verifier"`
+}
+
+type VerifierReview struct {
+	ApprovalLogIndex        *int       `json:"approval_log_index,omitempty"`
+	ApprovalTransactionHash string     `json:"approval_transaction_hash,omitempty"`
+	ApprovedAt              *time.Time `json:"approved_at,omitempty"`
+	ChallengeAddress        string     `json:"challenge_address"`
+	CreatedAt               time.Time  `json:"created_at"`
+	ID                      int        `json:"id"`
+	Notes                   string     `json:"notes"`
+	Recommendation          string     `json:"recommendation"`
+	SubmissionUID           string     `json:"submission_uid"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+	VerifierAddress         string     `json:"verifier_address"`
+}
+
+// VerifyRequest: Either code, a single file, or a multi-file project as
+// files or archive.
+type VerifyRequest struct {
+	// A tar, tar.gz or zip archive of the project. Only .lean files outside
+	// dot directories are taken, and the root lakefile.lean is ignored.
+	Archive []byte `json:"archive,omitempty"`
+	Code    string `json:"code,omitempty"`
+	// The project's root module, such as Main. Required when the project has
+	// more than one file.
+	Entry string `json:"entry,omitempty"`
+	// Project files keyed by path from the workspace root; A/B.lean holds
+	// module A.B. At most MAX_PROJECT_FILES files and MAX_PROJECT_BYTES in
+	// total.
+	Files map[string]string `json:"files,omitempty"`
+	// Milliseconds, at most 60000. Defaults to 30 seconds.
+	Timeout *int `json:"timeout,omitempty"`
+}
+
+type VerifyResponse struct {
+	ID     string      `json:"id"`
+	Status ProofStatus `json:"status"`
+}
+
+type Webhook struct {
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	Events    []string  `json:"events"`
+	ID        int       `json:"id"`
+	Owner     string    `json:"owner"`
+	URL       string    `json:"url"`
+}
+
+type WebhookDelivery struct {
+	Attempts       int             `json:"attempts"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	ID             int             `json:"id"`
+	LastError      string          `json:"last_error,omitempty"`
+	LockedUntil    *time.Time      `json:"locked_until,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	Payload        json.RawMessage `json:"payload"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	Status         string          `json:"status"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	WebhookID      int             `json:"webhook_id"`
+}
+
+type WebhookDeliveryList struct {
+	Data []WebhookDelivery `json:"data"`
+	Page Page              `json:"page"`
+}
+
+type WebhookList struct {
+	Data []Webhook `json:"data"`
+	Page Page      `json:"page"`
+}
+
+type WebhookRequest struct {
+	Events []string `json:"events"`
+	URL    string   `json:"url"`
+}
+
+type Window struct {
+	End   time.Time `json:"end"`
+	Label string    `json:"label"`
+	Start time.Time `json:"start"`
+}
+
+type WindowedLeaderboardEntry struct {
+	ChallengesVerified int `json:"challenges_verified"`
+	ChallengesWon      int `json:"challenges_won"`
+	Points             int `json:"points"`
+	// Decimal amount in whole token units.
+	TotalUSDCWon  string `json:"total_usdc_won"`
+	WalletAddress string `json:"wallet_address"`
+}
+
+// CheckLeaderboardConsistency calls GET /api/admin/leaderboard/consistency: Compare the leaderboard with replayed events.
+func (c *Client) CheckLeaderboardConsistency(ctx context.Context) (*ConsistencyReport, error) {
+	path := "/api/admin/leaderboard/consistency"
+	var out ConsistencyReport
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RebuildLeaderboard calls POST /api/admin/leaderboard/rebuild: Rebuild the leaderboard from events.
+func (c *Client) RebuildLeaderboard(ctx context.Context) (*RebuildResult, error) {
+	path := "/api/admin/leaderboard/rebuild"
+	var out RebuildResult
+	if err := c.do(ctx, http.MethodPost, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RescanSimilarity calls POST /api/admin/similarity/{address}/rescan: Fingerprint a challenge's submissions again.
+func (c *Client) RescanSimilarity(ctx context.Context, address string) (*RescanResult, error) {
+	path := fmt.Sprintf("/api/admin/similarity/%s/rescan", url.PathEscape(address))
+	var out RescanResult
+	if err := c.do(ctx, http.MethodPost, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListChallengesParams holds the query parameters of ListChallenges. Zero values are left out.
+type ListChallengesParams struct {
+	Limit  int
+	Offset int
+	Status string
+}
+
+// ListChallenges calls GET /api/challenges: List challenges.
+func (c *Client) ListChallenges(ctx context.Context, params ListChallengesParams) (*ChallengeList, error) {
+	path := "/api/challenges"
+	query := url.Values{}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	if params.Offset != 0 {
+		query.Set("offset", fmt.Sprint(params.Offset))
+	}
+	if params.Status != "" {
+		query.Set("status", params.Status)
+	}
+	var out ChallengeList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RecordChallengeEvent calls POST /api/challenges/events: Ingest a ChallengeEscrow event.
+func (c *Client) RecordChallengeEvent(ctx context.Context, body *ChallengeEventRequest) (*ChallengeEventResult, error) {
+	path := "/api/challenges/events"
+	var out ChallengeEventResult
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetChallenge calls GET /api/challenges/{address}: Get a challenge.
+func (c *Client) GetChallenge(ctx context.Context, address string) (*Challenge, error) {
+	path := fmt.Sprintf("/api/challenges/%s", url.PathEscape(address))
+	var out Challenge
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListCommitments calls GET /api/challenges/{address}/commitments: List a challenge's commitments.
+func (c *Client) ListCommitments(ctx context.Context, address string) (*CommitmentList, error) {
+	path := fmt.Sprintf("/api/challenges/%s/commitments", url.PathEscape(address))
+	var out CommitmentList
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetChallengeSchedule calls GET /api/challenges/{address}/schedule: Deadline jobs of a challenge.
+func (c *Client) GetChallengeSchedule(ctx context.Context, address string) (*ScheduledJobList, error) {
+	path := fmt.Sprintf("/api/challenges/%s/schedule", url.PathEscape(address))
+	var out ScheduledJobList
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetChallengeSimilarity calls GET /api/challenges/{address}/similarity: Similarity report.
+func (c *Client) GetChallengeSimilarity(ctx context.Context, address string) (*SimilarityReport, error) {
+	path := fmt.Sprintf("/api/challenges/%s/similarity", url.PathEscape(address))
+	var out SimilarityReport
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Commit calls POST /api/commitments: Register a commitment.
+func (c *Client) Commit(ctx context.Context, body *CommitRequest) (*Commitment, error) {
+	path := "/api/commitments"
+	var out Commitment
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Reveal calls POST /api/commitments/reveal: Reveal a committed solution.
+func (c *Client) Reveal(ctx context.Context, body *RevealRequest) (*RevealResult, error) {
+	path := "/api/commitments/reveal"
+	var out RevealResult
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListUpcomingDeadlinesParams holds the query parameters of ListUpcomingDeadlines. Zero values are left out.
+type ListUpcomingDeadlinesParams struct {
+	// Go duration.
+	Within string
+}
+
+// ListUpcomingDeadlines calls GET /api/deadlines: Challenges with upcoming deadlines.
+func (c *Client) ListUpcomingDeadlines(ctx context.Context, params ListUpcomingDeadlinesParams) (*DeadlineList, error) {
+	path := "/api/deadlines"
+	query := url.Values{}
+	if params.Within != "" {
+		query.Set("within", params.Within)
+	}
+	var out DeadlineList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetLeaderboardParams holds the query parameters of GetLeaderboard. Zero values are left out.
+type GetLeaderboardParams struct {
+	Limit  int
+	Offset int
+	Role   string
+	// all, a season ID, or a rolling window such as 30d.
+	Window string
+}
+
+// GetLeaderboard calls GET /api/leaderboard: Leaderboard.
+func (c *Client) GetLeaderboard(ctx context.Context, params GetLeaderboardParams) (*Leaderboard, error) {
+	path := "/api/leaderboard"
+	query := url.Values{}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	if params.Offset != 0 {
+		query.Set("offset", fmt.Sprint(params.Offset))
+	}
+	if params.Role != "" {
+		query.Set("role", params.Role)
+	}
+	if params.Window != "" {
+		query.Set("window", params.Window)
+	}
+	var out Leaderboard
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RecordSolutionApproval calls POST /api/leaderboard/approvals: Ingest a SolutionApproved event.
+func (c *Client) RecordSolutionApproval(ctx context.Context, body *SolutionApprovalRequest) (*SolutionApprovalResult, error) {
+	path := "/api/leaderboard/approvals"
+	var out SolutionApprovalResult
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RecordReputationEvent calls POST /api/leaderboard/events: Ingest a ReputationUpdated event.
+func (c *Client) RecordReputationEvent(ctx context.Context, body *ReputationEventRequest) (*ReputationEventResult, error) {
+	path := "/api/leaderboard/events"
+	var out ReputationEventResult
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRecentEventsParams holds the query parameters of GetRecentEvents. Zero values are left out.
+type GetRecentEventsParams struct {
+	Limit int
+}
+
+// GetRecentEvents calls GET /api/leaderboard/events/recent: Recent reputation events.
+func (c *Client) GetRecentEvents(ctx context.Context, params GetRecentEventsParams) (*ReputationEventList, error) {
+	path := "/api/leaderboard/events/recent"
+	query := url.Values{}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	var out ReputationEventList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CalculatePointsParams holds the query parameters of CalculatePoints. Zero values are left out.
+type CalculatePointsParams struct {
+	// USDC base units.
+	Amount     int64
+	IsVerifier bool
+}
+
+// CalculatePoints calls GET /api/leaderboard/points: Preview the points for an award.
+func (c *Client) CalculatePoints(ctx context.Context, params CalculatePointsParams) (*PointsPreview, error) {
+	path := "/api/leaderboard/points"
+	query := url.Values{}
+	query.Set("amount", fmt.Sprint(params.Amount))
+	if params.IsVerifier {
+		query.Set("is_verifier", strconv.FormatBool(params.IsVerifier))
+	}
+	var out PointsPreview
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSeasons calls GET /api/leaderboard/seasons: Configured seasons.
+func (c *Client) ListSeasons(ctx context.Context) (*SeasonList, error) {
+	path := "/api/leaderboard/seasons"
+	var out SeasonList
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetThresholdHistoryParams holds the query parameters of GetThresholdHistory. Zero values are left out.
+type GetThresholdHistoryParams struct {
+	Limit int
+}
+
+// GetThresholdHistory calls GET /api/leaderboard/thresholds: ThresholdUpdated history.
+func (c *Client) GetThresholdHistory(ctx context.Context, params GetThresholdHistoryParams) (*ThresholdUpdateList, error) {
+	path := "/api/leaderboard/thresholds"
+	query := url.Values{}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	var out ThresholdUpdateList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RecordThresholdUpdate calls POST /api/leaderboard/thresholds: Ingest a ThresholdUpdated event.
+func (c *Client) RecordThresholdUpdate(ctx context.Context, body *ThresholdUpdateRequest) (*ThresholdUpdateResult, error) {
+	path := "/api/leaderboard/thresholds"
+	var out ThresholdUpdateResult
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTopPerformersParams holds the query parameters of GetTopPerformers. Zero values are left out.
+type GetTopPerformersParams struct {
+	Limit int
+}
+
+// GetTopPerformers calls GET /api/leaderboard/top: Top of the all-time leaderboard.
+func (c *Client) GetTopPerformers(ctx context.Context, params GetTopPerformersParams) (*LeaderboardEntryList, error) {
+	path := "/api/leaderboard/top"
+	query := url.Values{}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	var out LeaderboardEntryList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserStats calls GET /api/leaderboard/user/{wallet}: A wallet's stats and winnings.
+func (c *Client) GetUserStats(ctx context.Context, wallet string) (*UserStats, error) {
+	path := fmt.Sprintf("/api/leaderboard/user/%s", url.PathEscape(wallet))
+	var out UserStats
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetVerifierQualification calls GET /api/leaderboard/user/{wallet}/qualification: Verifier qualification check.
+func (c *Client) GetVerifierQualification(ctx context.Context, wallet string) (*Qualification, error) {
+	path := fmt.Sprintf("/api/leaderboard/user/%s/qualification", url.PathEscape(wallet))
+	var out Qualification
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListNotificationsParams holds the query parameters of ListNotifications. Zero values are left out.
+type ListNotificationsParams struct {
+	Challenge string
+	Limit     int
+}
+
+// ListNotifications calls GET /api/notifications: Lifecycle notifications.
+func (c *Client) ListNotifications(ctx context.Context, params ListNotificationsParams) (*NotificationList, error) {
+	path := "/api/notifications"
+	query := url.Values{}
+	if params.Challenge != "" {
+		query.Set("challenge", params.Challenge)
+	}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	var out NotificationList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOpenAPI calls GET /api/openapi.json: This document.
+func (c *Client) GetOpenAPI(ctx context.Context) (*json.RawMessage, error) {
+	path := "/api/openapi.json"
+	var out json.RawMessage
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetVerificationResult calls GET /api/result/{id}: Verification result.
+func (c *Client) GetVerificationResult(ctx context.Context, id string) (*ProofResult, error) {
+	path := fmt.Sprintf("/api/result/%s", url.PathEscape(id))
+	var out ProofResult
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetVerificationStatus calls GET /api/status/{id}: Verification status.
+func (c *Client) GetVerificationStatus(ctx context.Context, id string) (*StatusResponse, error) {
+	path := fmt.Sprintf("/api/status/%s", url.PathEscape(id))
+	var out StatusResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateSubmission calls POST /api/submissions: Record a submission.
+func (c *Client) CreateSubmission(ctx context.Context, body *SubmissionRequest) (*Submission, error) {
+	path := "/api/submissions"
+	var out Submission
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListChallengeSubmissionsParams holds the query parameters of ListChallengeSubmissions. Zero values are left out.
+type ListChallengeSubmissionsParams struct {
+	// next_cursor from the previous page.
+	Cursor string
+	Limit  int
+	// Comma-separated statuses.
+	Status []string
+	// Inclusive.
+	CreatedAfter time.Time
+	// Exclusive.
+	CreatedBefore time.Time
+	Sort          string
+	// Comma-separated fields; solution_code only when listed.
+	Fields []string
+}
+
+// ListChallengeSubmissions calls GET /api/submissions/challenge/{address}: List a challenge's submissions.
+func (c *Client) ListChallengeSubmissions(ctx context.Context, address string, params ListChallengeSubmissionsParams) (*SubmissionList, error) {
+	path := fmt.Sprintf("/api/submissions/challenge/%s", url.PathEscape(address))
+	query := url.Values{}
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	if len(params.Status) > 0 {
+		query.Set("status", strings.Join(params.Status, ","))
+	}
+	if !params.CreatedAfter.IsZero() {
+		query.Set("created_after", params.CreatedAfter.Format(time.RFC3339))
+	}
+	if !params.CreatedBefore.IsZero() {
+		query.Set("created_before", params.CreatedBefore.Format(time.RFC3339))
+	}
+	if params.Sort != "" {
+		query.Set("sort", params.Sort)
+	}
+	if len(params.Fields) > 0 {
+		query.Set("fields", strings.Join(params.Fields, ","))
+	}
+	var out SubmissionList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWalletSubmissionsParams holds the query parameters of ListWalletSubmissions. Zero values are left out.
+type ListWalletSubmissionsParams struct {
+	// next_cursor from the previous page.
+	Cursor string
+	Limit  int
+	// Comma-separated statuses.
+	Status []string
+	// Inclusive.
+	CreatedAfter time.Time
+	// Exclusive.
+	CreatedBefore time.Time
+	Sort          string
+	// Comma-separated fields; solution_code only when listed.
+	Fields []string
+}
+
+// ListWalletSubmissions calls GET /api/submissions/wallet/{wallet}: List a wallet's submissions.
+func (c *Client) ListWalletSubmissions(ctx context.Context, wallet string, params ListWalletSubmissionsParams) (*SubmissionList, error) {
+	path := fmt.Sprintf("/api/submissions/wallet/%s", url.PathEscape(wallet))
+	query := url.Values{}
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	if len(params.Status) > 0 {
+		query.Set("status", strings.Join(params.Status, ","))
+	}
+	if !params.CreatedAfter.IsZero() {
+		query.Set("created_after", params.CreatedAfter.Format(time.RFC3339))
+	}
+	if !params.CreatedBefore.IsZero() {
+		query.Set("created_before", params.CreatedBefore.Format(time.RFC3339))
+	}
+	if params.Sort != "" {
+		query.Set("sort", params.Sort)
+	}
+	if len(params.Fields) > 0 {
+		query.Set("fields", strings.Join(params.Fields, ","))
+	}
+	var out SubmissionList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSubmission calls GET /api/submissions/{uid}: Get a submission.
+func (c *Client) GetSubmission(ctx context.Context, uid string) (*SubmissionView, error) {
+	path := fmt.Sprintf("/api/submissions/%s", url.PathEscape(uid))
+	var out SubmissionView
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateSubmissionStatus calls PUT /api/submissions/{uid}/status: Update a submission's status.
+func (c *Client) UpdateSubmissionStatus(ctx context.Context, uid string, body *SubmissionStatusUpdate) (*Message, error) {
+	path := fmt.Sprintf("/api/submissions/%s/status", url.PathEscape(uid))
+	var out Message
+	if err := c.do(ctx, http.MethodPut, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTokens calls GET /api/tokens: Reward token registry.
+func (c *Client) ListTokens(ctx context.Context) (*TokenList, error) {
+	path := "/api/tokens"
+	var out TokenList
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RecheckSubmission calls POST /api/verifier/submissions/{uid}/recheck: Run the automatic check again.
+func (c *Client) RecheckSubmission(ctx context.Context, uid string) (*CheckQueued, error) {
+	path := fmt.Sprintf("/api/verifier/submissions/%s/recheck", url.PathEscape(uid))
+	var out CheckQueued
+	if err := c.do(ctx, http.MethodPost, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RecordReview calls POST /api/verifier/submissions/{uid}/review: Record a review.
+func (c *Client) RecordReview(ctx context.Context, uid string, body *ReviewRequest) (*VerifierReview, error) {
+	path := fmt.Sprintf("/api/verifier/submissions/%s/review", url.PathEscape(uid))
+	var out VerifierReview
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetVerifierQueue calls GET /api/verifier/{wallet}/queue: A verifier's review queue.
+func (c *Client) GetVerifierQueue(ctx context.Context, wallet string) (*VerifierQueue, error) {
+	path := fmt.Sprintf("/api/verifier/%s/queue", url.PathEscape(wallet))
+	var out VerifierQueue
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// VerifyProof calls POST /api/verify: Queue Lean code or a multi-file project for verification.
+func (c *Client) VerifyProof(ctx context.Context, body *VerifyRequest) (*VerifyResponse, error) {
+	path := "/api/verify"
+	var out VerifyResponse
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// VerifyBatch calls POST /api/verify/batch: Queue many proofs for verification.
+func (c *Client) VerifyBatch(ctx context.Context, body *BatchVerifyRequest) (*BatchResponse, error) {
+	path := "/api/verify/batch"
+	var out BatchResponse
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBatch calls GET /api/verify/batch/{id}: Get a batch's progress.
+func (c *Client) GetBatch(ctx context.Context, id string) (*BatchResponse, error) {
+	path := fmt.Sprintf("/api/verify/batch/%s", url.PathEscape(id))
+	var out BatchResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBatchItemsParams holds the query parameters of ListBatchItems. Zero values are left out.
+type ListBatchItemsParams struct {
+	Limit  int
+	Offset int
+	Status string
+}
+
+// ListBatchItems calls GET /api/verify/batch/{id}/items: List a batch's items and results.
+func (c *Client) ListBatchItems(ctx context.Context, id string, params ListBatchItemsParams) (*BatchItemList, error) {
+	path := fmt.Sprintf("/api/verify/batch/%s/items", url.PathEscape(id))
+	query := url.Values{}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	if params.Offset != 0 {
+		query.Set("offset", fmt.Sprint(params.Offset))
+	}
+	if params.Status != "" {
+		query.Set("status", params.Status)
+	}
+	var out BatchItemList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWebhooks calls GET /api/webhooks: List the caller's webhooks.
+func (c *Client) ListWebhooks(ctx context.Context) (*WebhookList, error) {
+	path := "/api/webhooks"
+	var out WebhookList
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateWebhook calls POST /api/webhooks: Subscribe a webhook.
+func (c *Client) CreateWebhook(ctx context.Context, body *WebhookRequest) (*CreatedWebhook, error) {
+	path := "/api/webhooks"
+	var out CreatedWebhook
+	if err := c.do(ctx, http.MethodPost, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWebhook calls DELETE /api/webhooks/{id}: Deactivate a webhook.
+func (c *Client) DeleteWebhook(ctx context.Context, id int) (*Message, error) {
+	path := fmt.Sprintf("/api/webhooks/%s", url.PathEscape(fmt.Sprint(id)))
+	var out Message
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWebhookDeliveriesParams holds the query parameters of ListWebhookDeliveries. Zero values are left out.
+type ListWebhookDeliveriesParams struct {
+	Limit int
+}
+
+// ListWebhookDeliveries calls GET /api/webhooks/{id}/deliveries: A webhook's delivery log.
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id int, params ListWebhookDeliveriesParams) (*WebhookDeliveryList, error) {
+	path := fmt.Sprintf("/api/webhooks/%s/deliveries", url.PathEscape(fmt.Sprint(id)))
+	query := url.Values{}
+	if params.Limit != 0 {
+		query.Set("limit", fmt.Sprint(params.Limit))
+	}
+	var out WebhookDeliveryList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RedeliverWebhookDelivery calls POST /api/webhooks/{id}/deliveries/{delivery}/redeliver: Queue a delivery again.
+func (c *Client) RedeliverWebhookDelivery(ctx context.Context, id int, delivery int) (*DeliveryQueued, error) {
+	path := fmt.Sprintf("/api/webhooks/%s/deliveries/%s/redeliver", url.PathEscape(fmt.Sprint(id)), url.PathEscape(fmt.Sprint(delivery)))
+	var out DeliveryQueued
+	if err := c.do(ctx, http.MethodPost, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HealthCheck calls GET /health: Health check.
+func (c *Client) HealthCheck(ctx context.Context) (*Health, error) {
+	path := "/health"
+	var out Health
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cyrup/backend/client"
+	"github.com/google/uuid"
+)
+
+func (a *app) submit(ctx context.Context, args []string) error {
+	flags := a.flagSet("submit", "<file.lean>")
+	challenge := flags.String("challenge", "", "challenge contract address (required)")
+	wallet := flags.String("wallet", "", "solver wallet (default: the configured private key's address)")
+	uid := flags.String("uid", "", "submission UID (default: a new UUID)")
+	hash := flags.String("hash", "", "on-chain solution hash or IPFS CID")
+	if err := parse(flags, args, 1); err != nil {
+		return err
+	}
+	if *challenge == "" {
+		return usagef("-challenge is required")
+	}
+	if *wallet == "" {
+		*wallet = a.address
+	}
+	if *wallet == "" {
+		return usagef("-wallet is required without a private_key in the config")
+	}
+	if *uid == "" {
+		*uid = uuid.NewString()
+	}
+
+	code, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	submission, err := a.client.CreateSubmission(ctx, &client.SubmissionRequest{
+		UID:              *uid,
+		ChallengeAddress: *challenge,
+		WalletAddress:    *wallet,
+		SolutionCode:     string(code),
+		SolutionHash:     *hash,
+	})
+	if err != nil {
+		return err
+	}
+
+	if a.json {
+		return a.printJSON(submission)
+	}
+	fmt.Fprintf(a.stdout, "Created submission %s for challenge %s (%s)\n", submission.UID, submission.ChallengeAddress, submission.Status)
+	return nil
+}
+
+func (a *app) leaderboard(ctx context.Context, args []string) error {
+	flags := a.flagSet("leaderboard", "")
+	role := flags.String("role", "", "rank by solver or verifier points instead of reputation")
+	window := flags.String("window", "", "all, a season ID, or a rolling window such as 30d")
+	limit := flags.Int("limit", 20, "entries to show (at most 100)")
+	offset := flags.Int("offset", 0, "entries to skip")
+	if err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	board, err := a.client.GetLeaderboard(ctx, client.GetLeaderboardParams{
+		Role:   *role,
+		Window: *window,
+		Limit:  *limit,
+		Offset: *offset,
+	})
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(board)
+	}
+
+	table := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	defer table.Flush()
+	rank := *offset + 1
+
+	// Entries have a different shape per role and window.
+	switch {
+	case board.Window != nil:
+		fmt.Fprintf(table, "Window %s: %s to %s\n", board.Window.Label, board.Window.Start.Format(time.DateOnly), board.Window.End.Format(time.DateOnly))
+		fmt.Fprintln(table, "RANK\tWALLET\tPOINTS\tUSDC WON\tWON\tVERIFIED")
+		for _, raw := range board.Data {
+			var entry client.WindowedLeaderboardEntry
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				return err
+			}
+			fmt.Fprintf(table, "%d\t%s\t%d\t%s\t%d\t%d\n", rank, entry.WalletAddress, entry.Points, entry.TotalUSDCWon, entry.ChallengesWon, entry.ChallengesVerified)
+			rank++
+		}
+	case board.Role == "solver":
+		fmt.Fprintln(table, "RANK\tWALLET\tPOINTS\tUSDC WON\tWINS\tENTERED\tWIN RATE")
+		for _, raw := range board.Data {
+			var entry client.SolverLeaderboardEntry
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				return err
+			}
+			fmt.Fprintf(table, "%d\t%s\t%d\t%s\t%d\t%d\t%s\n", rank, entry.WalletAddress, entry.SolverPoints, entry.TotalUSDCWon, entry.Wins, entry.ChallengesEntered, percent(entry.WinRate))
+			rank++
+		}
+	case board.Role == "verifier":
+		fmt.Fprintln(table, "RANK\tWALLET\tPOINTS\tFEES EARNED\tVERIFICATIONS")
+		for _, raw := range board.Data {
+			var entry client.VerifierLeaderboardEntry
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				return err
+			}
+			fmt.Fprintf(table, "%d\t%s\t%d\t%s\t%d\n", rank, entry.WalletAddress, entry.VerifierPoints, entry.FeesEarned, entry.Verifications)
+			rank++
+		}
+	default:
+		fmt.Fprintln(table, "RANK\tWALLET\tREPUTATION\tUSDC WON\tWON\tVERIFIED")
+		for _, raw := range board.Data {
+			var entry client.LeaderboardEntry
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				return err
+			}
+			fmt.Fprintf(table, "%d\t%s\t%d\t%s\t%d\t%d\n", rank, entry.WalletAddress, entry.ReputationScore, entry.TotalUSDCWon, entry.ChallengesWon, entry.ChallengesVerified)
+			rank++
+		}
+	}
+	return nil
+}
+
+// challenges dispatches the challenges subcommands; list is the only one.
+func (a *app) challenges(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "list" {
+		return usagef("usage: cyrup challenges list [-status STATUS] [-limit N] [-offset N]")
+	}
+
+	flags := a.flagSet("challenges list", "")
+	status := flags.String("status", "", "only challenges in this status: open, active, completed or cancelled")
+	limit := flags.Int("limit", 50, "challenges to show (at most 100)")
+	offset := flags.Int("offset", 0, "challenges to skip")
+	if err := parse(flags, args[1:], 0); err != nil {
+		return err
+	}
+
+	challenges, err := a.client.ListChallenges(ctx, client.ListChallengesParams{
+		Status: *status,
+		Limit:  *limit,
+		Offset: *offset,
+	})
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(challenges)
+	}
+
+	table := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	defer table.Flush()
+	fmt.Fprintln(table, "ADDRESS\tSTATUS\tREWARD\tDEADLINE\tCREATOR")
+	for _, challenge := range challenges.Data {
+		deadline := "-"
+		if challenge.Deadline != nil {
+			deadline = challenge.Deadline.Local().Format(time.DateTime)
+		}
+		reward := orDash(challenge.RewardRaw)
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", challenge.Address, challenge.Status, reward, deadline, orDash(challenge.Creator))
+	}
+	return nil
+}
+
+func percent(rate *float64) string {
+	if rate == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", *rate*100)
+}
+
+func orDash(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const defaultAPIURL = "http://localhost:8080"
+
+// Config is read from a JSON file such as
+//
+//	{
+//	  "api_url": "https://api.cyrup.xyz",
+//	  "private_key": "0x..."
+//	}
+//
+// private_key signs requests as the wallet (see the README's Wallet
+// Authentication section); without it requests are anonymous. The
+// CYRUP_API_URL and CYRUP_PRIVATE_KEY environment variables override the
+// file.
+type Config struct {
+	APIURL     string `json:"api_url"`
+	PrivateKey string `json:"private_key,omitempty"`
+}
+
+// defaultConfigPath is where the config file lives when neither -config
+// nor CYRUP_CONFIG names one.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "cyrup.json"
+	}
+	return filepath.Join(dir, "cyrup", "config.json")
+}
+
+// LoadConfig reads the config file at path, or at CYRUP_CONFIG or the
+// default location when path is empty, and applies the environment. A
+// missing default file is not an error; a missing named one is.
+func LoadConfig(path string) (Config, error) {
+	config := Config{APIURL: defaultAPIURL}
+
+	named := path != ""
+	if !named {
+		path = os.Getenv("CYRUP_CONFIG")
+		named = path != ""
+	}
+	if !named {
+		path = defaultConfigPath()
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && !named:
+	case err != nil:
+		return Config{}, fmt.Errorf("read config: %w", err)
+	default:
+		if err := json.Unmarshal(data, &config); err != nil {
+			return Config{}, fmt.Errorf("parse config %s: %w", path, err)
+		}
+		if config.PrivateKey != "" {
+			if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0o077 != 0 {
+				fmt.Fprintf(os.Stderr, "Warning: %s holds a private key but is readable by other users; chmod 600 it\n", path)
+			}
+		}
+	}
+
+	if url := os.Getenv("CYRUP_API_URL"); url != "" {
+		config.APIURL = url
+	}
+	if key := os.Getenv("CYRUP_PRIVATE_KEY"); key != "" {
+		config.PrivateKey = key
+	}
+	if config.APIURL == "" {
+		config.APIURL = defaultAPIURL
+	}
+	return config, nil
+}
@@ -0,0 +1,197 @@
+// Command cyrup verifies Lean proofs and submits solutions against a Cyrup
+// API from the command line.
+//
+//	cyrup verify proof.lean
+//	cyrup verify -entry Main ./project
+//	cyrup submit -challenge 0x... proof.lean
+//	cyrup status <id>
+//	cyrup result <id>
+//	cyrup leaderboard -role solver
+//	cyrup challenges list -status open
+//
+// Every command takes -json to print the API's response for scripts. The
+// API URL and wallet key come from the config file (see config.go), the
+// CYRUP_API_URL and CYRUP_PRIVATE_KEY environment variables, or -api.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/cyrup/backend/client"
+)
+
+// Exit codes. A proof that fails to verify and an API error both exit 1 so
+// scripts can test for success alone.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+const usage = `Usage: cyrup [-api URL] [-config FILE] [-json] <command> [arguments]
+
+Commands:
+  verify <file.lean|dir>         verify a proof or project, print its diagnostics
+  submit -challenge ADDR <file>  create a submission for a challenge
+  status <id>                    print a verification job's status
+  result <id>                    print a verification job's result
+  leaderboard                    print the leaderboard
+  challenges list                list indexed challenges
+
+Run "cyrup <command> -h" for a command's flags.
+`
+
+// app holds what every command needs: the resolved config, the API client
+// and where to write.
+type app struct {
+	config Config
+	client *client.Client
+	json   bool
+	stdout io.Writer
+	stderr io.Writer
+
+	// address is the wallet the configured private key belongs to, or ""
+	// when requests are anonymous.
+	address string
+}
+
+// usageError is a mistake in the command line. It exits with exitUsage;
+// an empty message means the flag package has already reported it.
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// errFailed exits with exitFailure without printing anything more; the
+// command has already reported the failure.
+var errFailed = errors.New("failed")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	a := &app{stdout: stdout, stderr: stderr}
+
+	flags := flag.NewFlagSet("cyrup", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	configPath := flags.String("config", "", "config file (default $CYRUP_CONFIG or "+defaultConfigPath()+")")
+	apiURL := flags.String("api", "", "API base URL, overriding the config file")
+	flags.BoolVar(&a.json, "json", false, "print JSON for scripts")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	config, err := LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, "cyrup:", err)
+		return exitUsage
+	}
+	if *apiURL != "" {
+		config.APIURL = *apiURL
+	}
+	a.config = config
+	a.client = client.New(config.APIURL)
+	if config.PrivateKey != "" {
+		prepare, address, err := client.Wallet(config.PrivateKey)
+		if err != nil {
+			fmt.Fprintln(stderr, "cyrup: private_key:", err)
+			return exitUsage
+		}
+		a.client.Prepare = prepare
+		a.address = address
+	}
+
+	command, rest := flags.Arg(0), flags.Args()[1:]
+	commands := map[string]func(context.Context, []string) error{
+		"verify":      a.verify,
+		"submit":      a.submit,
+		"status":      a.status,
+		"result":      a.result,
+		"leaderboard": a.leaderboard,
+		"challenges":  a.challenges,
+	}
+	handler, ok := commands[command]
+	if !ok {
+		fmt.Fprintf(stderr, "cyrup: unknown command %q\n\n%s", command, usage)
+		return exitUsage
+	}
+
+	err = handler(ctx, rest)
+	var usageErr *usageError
+	var problem *client.Problem
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usageErr):
+		if usageErr.message != "" {
+			fmt.Fprintf(stderr, "cyrup %s: %s\n", command, usageErr.message)
+		}
+		return exitUsage
+	case errors.Is(err, errFailed):
+		return exitFailure
+	case errors.As(err, &problem):
+		if a.json {
+			a.printJSON(problemJSON(problem))
+		}
+		fmt.Fprintf(stderr, "cyrup %s: %s\n", command, problem.Error())
+		return exitFailure
+	default:
+		fmt.Fprintf(stderr, "cyrup %s: %v\n", command, err)
+		return exitFailure
+	}
+}
+
+// flagSet returns a flag set for a command that also accepts -json, so the
+// flag may come before or after the command name.
+func (a *app) flagSet(name, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	flags.BoolVar(&a.json, "json", a.json, "print JSON for scripts")
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: cyrup %s [flags] %s\n\nFlags:\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parse parses a command's flags and checks it got exactly want positional
+// arguments.
+func parse(flags *flag.FlagSet, args []string, want int) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{}
+	}
+	if flags.NArg() != want {
+		flags.Usage()
+		return usagef("expected %d argument(s), got %d", want, flags.NArg())
+	}
+	return nil
+}
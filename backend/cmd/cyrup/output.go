@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cyrup/backend/client"
+)
+
+// printJSON writes v to stdout as indented JSON.
+func (a *app) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(a.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// progress reports what a long-running command is doing. It goes to
+// stderr so that stdout holds only the result, JSON or not.
+func (a *app) progress(format string, args ...interface{}) {
+	fmt.Fprintf(a.stderr, format+"\n", args...)
+}
+
+// problemJSON returns the problem as the API sent it, including the
+// members specific to its code.
+func problemJSON(p *client.Problem) interface{} {
+	if p.Extensions != nil {
+		return p.Extensions
+	}
+	return p
+}
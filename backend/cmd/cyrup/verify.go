@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cyrup/backend/client"
+	"github.com/cyrup/backend/internal/lean"
+)
+
+// verification is what verify and result print with -json: the API's
+// result plus the diagnostics parsed from its output.
+type verification struct {
+	*client.ProofResult
+	Diagnostics lean.Diagnostics `json:"diagnostics"`
+}
+
+func (a *app) verify(ctx context.Context, args []string) error {
+	flags := a.flagSet("verify", "<file.lean|dir>")
+	entry := flags.String("entry", "", "root module of a project directory, such as Main")
+	timeout := flags.Duration("timeout", 0, "compile timeout, at most 60s (default: the server's)")
+	wait := flags.Duration("wait", 5*time.Minute, "how long to wait for the result")
+	poll := flags.Duration("poll", time.Second, "how often to poll the job's status")
+	if err := parse(flags, args, 1); err != nil {
+		return err
+	}
+	path := flags.Arg(0)
+
+	request := &client.VerifyRequest{}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		if request.Files, err = projectFiles(path); err != nil {
+			return err
+		}
+		request.Entry = *entry
+	} else {
+		code, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		request.Code = string(code)
+	}
+	if *timeout > 0 {
+		milliseconds := int(timeout.Milliseconds())
+		request.Timeout = &milliseconds
+	}
+	job, err := a.client.VerifyProof(ctx, request)
+	if err != nil {
+		return err
+	}
+	a.progress("%s: %s (job %s)", path, job.Status, job.ID)
+
+	ctx, cancel := context.WithTimeout(ctx, *wait)
+	defer cancel()
+
+	status := job.Status
+	ticker := time.NewTicker(*poll)
+	defer ticker.Stop()
+	for !finished(status) {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("job %s still %s after %s: %w", job.ID, status, *wait, ctx.Err())
+		case <-ticker.C:
+		}
+
+		current, err := a.client.GetVerificationStatus(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != status {
+			status = current.Status
+			a.progress("%s: %s", path, status)
+		}
+	}
+
+	result, err := a.client.GetVerificationResult(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	return a.report(path, result)
+}
+
+func (a *app) status(ctx context.Context, args []string) error {
+	flags := a.flagSet("status", "<id>")
+	if err := parse(flags, args, 1); err != nil {
+		return err
+	}
+
+	status, err := a.client.GetVerificationStatus(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(status)
+	}
+	fmt.Fprintf(a.stdout, "%s\t%s\n", status.ID, status.Status)
+	return nil
+}
+
+func (a *app) result(ctx context.Context, args []string) error {
+	flags := a.flagSet("result", "<id>")
+	if err := parse(flags, args, 1); err != nil {
+		return err
+	}
+
+	result, err := a.client.GetVerificationResult(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.report("", result)
+}
+
+// finished reports whether a job has stopped changing.
+func finished(status client.ProofStatus) bool {
+	switch status {
+	case client.ProofStatusSuccess, client.ProofStatusError, client.ProofStatusTimeout:
+		return true
+	}
+	return false
+}
+
+// projectFiles reads the .lean files under dir for a project request,
+// skipping dot directories such as .lake and the lakefile, which the lean
+// runner provides.
+func projectFiles(dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !strings.HasSuffix(rel, ".lean") || rel == "lakefile.lean" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[rel] = string(content)
+		return nil
+	})
+	if err == nil && len(files) == 0 {
+		err = fmt.Errorf("%s has no .lean files", dir)
+	}
+	return files, err
+}
+
+// report prints a result's diagnostics and fails unless the proof checked
+// cleanly. Diagnostics of a single file name it by the server's temporary
+// path, and those of a project by the path within the project; when path
+// is set they are shown against the local file or directory instead.
+func (a *app) report(path string, result *client.ProofResult) error {
+	diagnostics := lean.ParseDiagnostics(result.Output + "\n" + result.Error)
+	if path != "" {
+		for i := range diagnostics {
+			switch {
+			case diagnostics[i].File == "":
+			case len(result.Files) > 0:
+				diagnostics[i].File = filepath.Join(path, filepath.FromSlash(diagnostics[i].File))
+			default:
+				diagnostics[i].File = path
+			}
+		}
+	}
+
+	failed := result.Status != client.ProofStatusSuccess || diagnostics.Count(lean.SeverityError) > 0
+
+	if a.json {
+		if err := a.printJSON(verification{ProofResult: result, Diagnostics: diagnostics}); err != nil {
+			return err
+		}
+	} else {
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintln(a.stdout, formatDiagnostic(diagnostic))
+		}
+		for _, file := range result.Files {
+			if file.Status != client.FileStatusBuilt {
+				fmt.Fprintf(a.stdout, "%s: %s\n", filepath.Join(path, filepath.FromSlash(file.Path)), file.Status)
+			}
+		}
+
+		name := path
+		if name == "" {
+			name = result.ID
+		}
+		summary := fmt.Sprintf("%s: %s", name, result.Status)
+		if result.ExecutionTime != nil {
+			summary += fmt.Sprintf(" in %s", time.Duration(*result.ExecutionTime).Round(time.Millisecond))
+		}
+		if errors := diagnostics.Count(lean.SeverityError); errors > 0 {
+			summary += fmt.Sprintf(", %d error(s)", errors)
+		}
+		if warnings := diagnostics.Count(lean.SeverityWarning); warnings > 0 {
+			summary += fmt.Sprintf(", %d warning(s)", warnings)
+		}
+		fmt.Fprintln(a.stdout, summary)
+	}
+
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+// formatDiagnostic prints a diagnostic the way Lean does, so editors and
+// CI annotations can pick it up.
+func formatDiagnostic(d lean.Diagnostic) string {
+	if d.File == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", filepath.ToSlash(d.File), d.Line, d.Column, d.Severity, d.Message)
+}
@@ -0,0 +1,505 @@
+// Command openapi-gen writes the Go client's types and methods from the
+// OpenAPI document. It understands the subset of OpenAPI the Cyrup spec
+// uses: object and enum schemas, local $refs, nullable types and JSON
+// request and response bodies.
+//
+//	go run ./cmd/openapi-gen -spec internal/openapi/openapi.json -out client/client_gen.go -package client
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cyrup/backend/internal/openapi"
+)
+
+func main() {
+	specPath := flag.String("spec", "internal/openapi/openapi.json", "OpenAPI document to read")
+	out := flag.String("out", "client/client_gen.go", "Go file to write")
+	pkg := flag.String("package", "client", "package name of the generated file")
+	skip := flag.String("skip", "", "comma-separated schemas that are written by hand")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal("Failed to read spec:", err)
+	}
+	doc, err := openapi.Parse(data)
+	if err != nil {
+		log.Fatal("Failed to parse spec:", err)
+	}
+
+	g := &generator{doc: doc, skip: make(map[string]bool)}
+	for _, name := range strings.Split(*skip, ",") {
+		if name != "" {
+			g.skip[name] = true
+		}
+	}
+
+	source, err := g.generate(*pkg)
+	if err != nil {
+		log.Fatal("Failed to generate client:", err)
+	}
+	if err := os.WriteFile(*out, source, 0o644); err != nil {
+		log.Fatal("Failed to write client:", err)
+	}
+}
+
+type generator struct {
+	doc     *openapi.Document
+	skip    map[string]bool
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) generate(pkg string) ([]byte, error) {
+	g.imports = map[string]bool{"context": true, "net/http": true}
+
+	var body bytes.Buffer
+	g.buf = bytes.Buffer{}
+	if err := g.types(); err != nil {
+		return nil, err
+	}
+	if err := g.operations(); err != nil {
+		return nil, err
+	}
+	body.Write(g.buf.Bytes())
+
+	g.buf = bytes.Buffer{}
+	g.printf("// Code generated by openapi-gen from the OpenAPI document. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", pkg)
+	g.printf("import (\n")
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	for _, path := range imports {
+		g.printf("\t%q\n", path)
+	}
+	g.printf(")\n\n")
+	g.buf.Write(body.Bytes())
+
+	source, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, g.buf.Bytes())
+	}
+	return source, nil
+}
+
+func (g *generator) types() error {
+	names := make([]string, 0, len(g.doc.Components.Schemas))
+	for name := range g.doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if g.skip[name] {
+			continue
+		}
+		schema := g.doc.Components.Schemas[name]
+		comment(&g.buf, name, schema.Description)
+
+		switch {
+		case schema.Type() == "string" && len(schema.Enum) > 0:
+			g.printf("type %s string\n\n", name)
+			g.printf("const (\n")
+			for _, value := range schema.Enum {
+				g.printf("\t%s%s %s = %q\n", name, goName(fmt.Sprint(value)), name, value)
+			}
+			g.printf(")\n\n")
+		case schema.Type() == "object" && schema.Properties != nil:
+			g.printf("type %s struct {\n", name)
+			for _, prop := range sortedKeys(schema.Properties) {
+				required := schema.IsRequired(prop)
+				propSchema := schema.Properties[prop]
+				if propSchema.Description != "" {
+					comment(&g.buf, "", propSchema.Description)
+				}
+				tag := prop
+				if !required {
+					tag += ",omitempty"
+				}
+				g.printf("\t%s %s `json:%q`\n", goName(prop), g.goType(propSchema, required), tag)
+			}
+			g.printf("}\n\n")
+		default:
+			g.printf("type %s = %s\n\n", name, g.goType(schema, true))
+		}
+	}
+	return nil
+}
+
+// goType is the Go type for a schema. Optional and nullable structs,
+// numbers, booleans and times become pointers so that absence survives a
+// round trip.
+func (g *generator) goType(schema *openapi.Schema, required bool) string {
+	optional := !required || schema.Nullable()
+	target := schema.NonNull()
+
+	if target.Ref != "" {
+		name := openapi.RefName(target.Ref)
+		referenced := g.doc.Components.Schemas[name]
+		if optional && referenced != nil && referenced.Type() == "object" {
+			return "*" + name
+		}
+		return name
+	}
+
+	pointer := ""
+	if optional {
+		pointer = "*"
+	}
+	switch target.Type() {
+	case "string":
+		if target.Format == "date-time" {
+			g.imports["time"] = true
+			return pointer + "time.Time"
+		}
+		if target.Format == "byte" {
+			return "[]byte"
+		}
+		if schema.Nullable() {
+			return "*string"
+		}
+		return "string"
+	case "integer":
+		if target.Format == "int64" {
+			return pointer + "int64"
+		}
+		return pointer + "int"
+	case "number":
+		return pointer + "float64"
+	case "boolean":
+		return pointer + "bool"
+	case "array":
+		if target.Items == nil {
+			g.imports["encoding/json"] = true
+			return "[]json.RawMessage"
+		}
+		return "[]" + g.goType(target.Items, true)
+	case "object":
+		if len(target.AdditionalProperties) > 0 && target.AdditionalProperties[0] == '{' {
+			var values openapi.Schema
+			if err := json.Unmarshal(target.AdditionalProperties, &values); err == nil {
+				return "map[string]" + g.goType(&values, true)
+			}
+		}
+	}
+	g.imports["encoding/json"] = true
+	return "json.RawMessage"
+}
+
+func (g *generator) operations() error {
+	paths := sortedKeys(g.doc.Paths)
+	for _, path := range paths {
+		item := g.doc.Paths[path]
+		for _, method := range openapi.Methods(item) {
+			if err := g.operation(path, strings.ToUpper(method), item[method]); err != nil {
+				return fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) operation(path, method string, op *openapi.Operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("operation has no operationId")
+	}
+	name := goName(op.OperationID)
+
+	var pathParams, queryParams []openapi.Parameter
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			pathParams = append(pathParams, param)
+		case "query":
+			queryParams = append(queryParams, param)
+		}
+	}
+
+	if len(queryParams) > 0 {
+		g.printf("// %sParams holds the query parameters of %s. Zero values are left out.\n", name, name)
+		g.printf("type %sParams struct {\n", name)
+		for _, param := range queryParams {
+			if param.Description != "" {
+				comment(&g.buf, "", param.Description)
+			}
+			g.printf("\t%s %s\n", goName(param.Name), g.paramType(param.Schema))
+		}
+		g.printf("}\n\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, param := range pathParams {
+		args = append(args, lowerName(param.Name)+" "+g.paramType(param.Schema))
+	}
+	if len(queryParams) > 0 {
+		args = append(args, fmt.Sprintf("params %sParams", name))
+	}
+	if op.RequestBody != nil {
+		schema := op.RequestBody.Content["application/json"].Schema
+		if schema == nil || schema.Ref == "" {
+			return fmt.Errorf("request body must reference a schema")
+		}
+		args = append(args, "body *"+openapi.RefName(schema.Ref))
+	}
+
+	result, err := g.result(op)
+	if err != nil {
+		return err
+	}
+
+	summary := op.Summary
+	if summary != "" {
+		summary = ": " + summary
+	}
+	g.printf("// %s calls %s %s%s.\n", name, method, path, strings.TrimSuffix(summary, "."))
+	if result == "" {
+		g.printf("func (c *Client) %s(%s) error {\n", name, strings.Join(args, ", "))
+	} else {
+		g.printf("func (c *Client) %s(%s) (*%s, error) {\n", name, strings.Join(args, ", "), result)
+	}
+
+	g.printf("\tpath := %s\n", g.pathExpr(path, pathParams))
+	query := "nil"
+	if len(queryParams) > 0 {
+		g.imports["net/url"] = true
+		query = "query"
+		g.printf("\tquery := url.Values{}\n")
+		for _, param := range queryParams {
+			g.queryParam(param)
+		}
+	}
+	bodyArg := "nil"
+	if op.RequestBody != nil {
+		bodyArg = "body"
+	}
+
+	if result == "" {
+		g.printf("\treturn c.do(ctx, http.Method%s, path, %s, %s, nil)\n", methodConst(method), query, bodyArg)
+	} else {
+		g.printf("\tvar out %s\n", result)
+		g.printf("\tif err := c.do(ctx, http.Method%s, path, %s, %s, &out); err != nil {\n", methodConst(method), query, bodyArg)
+		g.printf("\t\treturn nil, err\n\t}\n")
+		g.printf("\treturn &out, nil\n")
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+// result is the type of the operation's successful JSON response. Every
+// 2xx response must share one schema.
+func (g *generator) result(op *openapi.Operation) (string, error) {
+	result := ""
+	for _, status := range sortedKeys(op.Responses) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		media, ok := op.Responses[status].Content["application/json"]
+		if !ok || media.Schema == nil {
+			continue
+		}
+		var name string
+		if media.Schema.Ref != "" {
+			name = openapi.RefName(media.Schema.Ref)
+		} else {
+			name = g.goType(media.Schema, true)
+		}
+		if result != "" && result != name {
+			return "", fmt.Errorf("2xx responses use both %s and %s", result, name)
+		}
+		result = name
+	}
+	return result, nil
+}
+
+// paramType is the Go type of a path or query parameter; unlike body
+// fields, absence is the zero value.
+func (g *generator) paramType(schema *openapi.Schema) string {
+	switch schema.Type() {
+	case "string":
+		if schema.Format == "date-time" {
+			g.imports["time"] = true
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if schema.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.paramType(schema.Items)
+	}
+	return "string"
+}
+
+// pathExpr builds the request path, escaping each parameter.
+func (g *generator) pathExpr(path string, params []openapi.Parameter) string {
+	if len(params) == 0 {
+		return fmt.Sprintf("%q", path)
+	}
+	g.imports["net/url"] = true
+	g.imports["fmt"] = true
+
+	format := path
+	values := make([]string, 0, len(params))
+	for _, param := range params {
+		format = strings.Replace(format, "{"+param.Name+"}", "%s", 1)
+		value := lowerName(param.Name)
+		if g.paramType(param.Schema) != "string" {
+			value = fmt.Sprintf("fmt.Sprint(%s)", value)
+		}
+		values = append(values, "url.PathEscape("+value+")")
+	}
+	return fmt.Sprintf("fmt.Sprintf(%q, %s)", format, strings.Join(values, ", "))
+}
+
+// queryParam writes the code that adds one query parameter. Arrays are
+// comma-separated, matching style=form, explode=false.
+func (g *generator) queryParam(param openapi.Parameter) {
+	field := "params." + goName(param.Name)
+	typ := g.paramType(param.Schema)
+	switch typ {
+	case "string":
+		g.printf("\tif %s != \"\" {\n\t\tquery.Set(%q, %s)\n\t}\n", field, param.Name, field)
+	case "time.Time":
+		g.printf("\tif !%s.IsZero() {\n\t\tquery.Set(%q, %s.Format(time.RFC3339))\n\t}\n", field, param.Name, field)
+	case "bool":
+		g.imports["strconv"] = true
+		g.printf("\tif %s {\n\t\tquery.Set(%q, strconv.FormatBool(%s))\n\t}\n", field, param.Name, field)
+	case "int", "int64", "float64":
+		g.imports["fmt"] = true
+		if param.Required {
+			g.printf("\tquery.Set(%q, fmt.Sprint(%s))\n", param.Name, field)
+		} else {
+			g.printf("\tif %s != 0 {\n\t\tquery.Set(%q, fmt.Sprint(%s))\n\t}\n", field, param.Name, field)
+		}
+	default:
+		g.imports["strings"] = true
+		g.printf("\tif len(%s) > 0 {\n\t\tquery.Set(%q, strings.Join(%s, \",\"))\n\t}\n", field, param.Name, field)
+	}
+}
+
+func methodConst(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+// comment writes text as a Go comment, starting with name when given.
+func comment(buf *bytes.Buffer, name, text string) {
+	if text == "" {
+		return
+	}
+	if name != "" {
+		text = name + ": " + text
+	}
+	indent := ""
+	if name == "" {
+		indent = "\t"
+	}
+	for _, line := range wrap(text, 72) {
+		fmt.Fprintf(buf, "%s// %s\n", indent, line)
+	}
+}
+
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"api": true, "id": true, "uid": true, "url": true, "usd": true, "usdc": true, "json": true, "http": true,
+}
+
+// goName converts snake_case, kebab-case, dotted and camelCase names to an
+// exported Go name.
+func goName(name string) string {
+	var words []string
+	word := ""
+	flush := func() {
+		if word != "" {
+			words = append(words, word)
+			word = ""
+		}
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == '.' || r == ' ' || r == '/':
+			flush()
+		case r >= 'A' && r <= 'Z' && i > 0 && name[i-1] >= 'a' && name[i-1] <= 'z':
+			flush()
+			word += string(r)
+		default:
+			word += string(r)
+		}
+	}
+	flush()
+
+	var out strings.Builder
+	for _, w := range words {
+		lower := strings.ToLower(w)
+		if initialisms[lower] {
+			out.WriteString(strings.ToUpper(lower))
+			continue
+		}
+		out.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return out.String()
+}
+
+// lowerName is goName with a lower-case first word, for arguments.
+func lowerName(name string) string {
+	upper := goName(name)
+	for i, r := range upper {
+		if r < 'A' || r > 'Z' {
+			if i > 1 {
+				i--
+			}
+			return strings.ToLower(upper[:i]) + upper[i:]
+		}
+	}
+	return strings.ToLower(upper)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
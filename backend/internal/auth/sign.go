@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// ErrInvalidKey is returned for private keys that are not 32 hex-encoded
+// bytes.
+var ErrInvalidKey = errors.New("private key must be 32 bytes of hex")
+
+// Signer authenticates requests as a wallet, for clients and tools that
+// hold the wallet's private key.
+type Signer struct {
+	key     *secp256k1.PrivateKey
+	address string
+}
+
+// NewSigner parses a hex private key, with or without 0x.
+func NewSigner(privateKey string) (*Signer, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(privateKey), "0x"))
+	if err != nil || len(raw) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	key := secp256k1.PrivKeyFromBytes(raw)
+	uncompressed := key.PubKey().SerializeUncompressed()
+	return &Signer{
+		key:     key,
+		address: "0x" + hex.EncodeToString(Keccak256(uncompressed[1:])[12:]),
+	}, nil
+}
+
+// Address returns the wallet's lowercase address.
+func (s *Signer) Address() string {
+	return s.address
+}
+
+// SignMessage returns the 65-byte r || s || v personal_sign signature over
+// message, with v as 27 or 28 like wallets produce.
+func (s *Signer) SignMessage(message []byte) []byte {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	compact := ecdsa.SignCompact(s.key, Keccak256([]byte(prefix), message), false)
+
+	// SignCompact puts the recovery code first.
+	signature := make([]byte, 0, 65)
+	signature = append(signature, compact[1:]...)
+	return append(signature, compact[0])
+}
+
+// Headers returns the X-Wallet-* header values that authenticate a request
+// made at issuedAt.
+func (s *Signer) Headers(issuedAt time.Time) map[string]string {
+	signature := s.SignMessage([]byte(Message(s.address, issuedAt)))
+	return map[string]string{
+		"X-Wallet-Address":   s.address,
+		"X-Wallet-Signature": "0x" + hex.EncodeToString(signature),
+		"X-Wallet-Timestamp": fmt.Sprint(issuedAt.Unix()),
+	}
+}
@@ -0,0 +1,125 @@
+// Package auth verifies that a request was made by the owner of a wallet.
+// Clients sign a short message with personal_sign (EIP-191); the server
+// recovers the signer and compares it with the claimed address.
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// DefaultMaxAge bounds how long a signed message can be replayed.
+const DefaultMaxAge = 5 * time.Minute
+
+var (
+	ErrExpired           = errors.New("signature has expired")
+	ErrSignerMismatch    = errors.New("signature was not made by the claimed wallet")
+	ErrMalformedRequest  = errors.New("wallet address, signature and timestamp are required")
+	ErrInvalidSignature  = errors.New("invalid signature")
+	ErrInvalidTimestamp  = errors.New("invalid timestamp")
+	ErrTimestampInFuture = errors.New("timestamp is in the future")
+)
+
+// Message is the text a wallet signs to authenticate requests made at
+// issuedAt.
+func Message(address string, issuedAt time.Time) string {
+	return fmt.Sprintf("Sign in to Cyrup\nAddress: %s\nIssued At: %d", strings.ToLower(address), issuedAt.Unix())
+}
+
+// Keccak256 hashes data the way Ethereum does.
+func Keccak256(data ...[]byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	for _, chunk := range data {
+		hash.Write(chunk)
+	}
+	return hash.Sum(nil)
+}
+
+// RecoverAddress returns the lowercase address that produced an EIP-191
+// personal_sign signature over message. The signature is the 65-byte
+// r || s || v produced by wallets, with v either 0/1 or 27/28.
+func RecoverAddress(message []byte, signature []byte) (string, error) {
+	if len(signature) != 65 {
+		return "", ErrInvalidSignature
+	}
+
+	v := signature[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return "", ErrInvalidSignature
+	}
+
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	digest := Keccak256([]byte(prefix), message)
+
+	// RecoverCompact takes the recovery code first: 27 + v for keys
+	// serialised uncompressed.
+	compact := make([]byte, 0, 65)
+	compact = append(compact, 27+v)
+	compact = append(compact, signature[:64]...)
+
+	publicKey, _, err := ecdsa.RecoverCompact(compact, digest)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	uncompressed := publicKey.SerializeUncompressed()
+	return "0x" + hex.EncodeToString(Keccak256(uncompressed[1:])[12:]), nil
+}
+
+// Verifier checks signed authentication messages.
+type Verifier struct {
+	MaxAge time.Duration
+	Now    func() time.Time
+}
+
+func NewVerifier(maxAge time.Duration) *Verifier {
+	return &Verifier{MaxAge: maxAge, Now: time.Now}
+}
+
+// Verify checks that signature is the wallet's signature over
+// Message(address, timestamp) and that the timestamp is recent. It returns
+// the address in lowercase.
+func (v *Verifier) Verify(address, signature, timestamp string) (string, error) {
+	if address == "" || signature == "" || timestamp == "" {
+		return "", ErrMalformedRequest
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", ErrInvalidTimestamp
+	}
+	issuedAt := time.Unix(seconds, 0)
+
+	now := v.Now()
+	if issuedAt.After(now.Add(time.Minute)) {
+		return "", ErrTimestampInFuture
+	}
+	if now.Sub(issuedAt) > v.MaxAge {
+		return "", ErrExpired
+	}
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	signer, err := RecoverAddress([]byte(Message(address, issuedAt)), raw)
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(signer, address) {
+		return "", ErrSignerMismatch
+	}
+
+	return signer, nil
+}
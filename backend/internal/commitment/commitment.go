@@ -0,0 +1,49 @@
+// Package commitment implements the hash used by the commit-reveal
+// submission flow. A solver first publishes Hash(code, salt, wallet), then
+// reveals code and salt once the submission window has closed, so nobody
+// watching the commitment can copy the proof in the meantime.
+package commitment
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/cyrup/backend/internal/auth"
+)
+
+// SaltSize is the salt length in bytes. A bytes32 salt keeps the hash
+// reproducible on-chain as keccak256(abi.encodePacked(code, salt, wallet)).
+const SaltSize = 32
+
+// Hash returns the 0x-prefixed keccak256 of code || salt || wallet, where
+// salt is 32 hex-encoded bytes and wallet a 20-byte address.
+func Hash(code string, salt string, wallet string) (string, error) {
+	saltBytes, err := decodeHex(salt, SaltSize)
+	if err != nil {
+		return "", fmt.Errorf("salt must be %d hex-encoded bytes", SaltSize)
+	}
+	walletBytes, err := decodeHex(wallet, 20)
+	if err != nil {
+		return "", fmt.Errorf("wallet must be a 20-byte hex address")
+	}
+
+	return "0x" + hex.EncodeToString(auth.Keccak256([]byte(code), saltBytes, walletBytes)), nil
+}
+
+// Valid reports whether s looks like a commitment: 0x and 32 hex bytes.
+func Valid(s string) bool {
+	_, err := decodeHex(s, 32)
+	return err == nil
+}
+
+func decodeHex(s string, size int) ([]byte, error) {
+	decoded, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(decoded))
+	}
+	return decoded, nil
+}
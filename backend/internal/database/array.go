@@ -0,0 +1,21 @@
+package database
+
+import (
+	"database/sql/driver"
+
+	"github.com/lib/pq"
+)
+
+// Int64Array is a BIGINT[] column. It is read and written through lib/pq
+// here so that packages filling in models need no driver import.
+type Int64Array []int64
+
+// Scan reads a Postgres array literal.
+func (a *Int64Array) Scan(src interface{}) error {
+	return (*pq.Int64Array)(a).Scan(src)
+}
+
+// Value writes the array as a Postgres array literal.
+func (a Int64Array) Value() (driver.Value, error) {
+	return pq.Int64Array(a).Value()
+}
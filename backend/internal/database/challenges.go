@@ -0,0 +1,110 @@
+package database
+
+import "strings"
+
+// statusRank orders challenge statuses along the escrow lifecycle. Completed
+// and cancelled are both terminal.
+var statusRank = map[string]int{
+	ChallengeOpen:      0,
+	ChallengeActive:    1,
+	ChallengeCompleted: 2,
+	ChallengeCancelled: 2,
+}
+
+// ApplyChallengeEvent folds an event into a challenge's state and reports
+// whether anything changed. current may be nil for a challenge not seen
+// before. Status only ever moves forward, and the fields ChallengeCreated
+// sets and the verifier are written once, so events can be applied in any
+// order, applying one twice is a no-op and a replayed event cannot rewrite
+// the creator, deadline or verifier.
+func ApplyChallengeEvent(current *Challenge, event ChallengeEvent) (Challenge, bool) {
+	var next Challenge
+	if current != nil {
+		next = *current
+	} else {
+		next = Challenge{Address: event.ChallengeAddress, Status: ChallengeOpen}
+	}
+	before := next
+
+	advance := func(status string) {
+		if statusRank[status] > statusRank[next.Status] {
+			next.Status = status
+		}
+	}
+	setVerifier := func() {
+		if event.Verifier != "" && next.Verifier == nil {
+			verifier := event.Verifier
+			next.Verifier = &verifier
+		}
+	}
+
+	switch event.Event {
+	case EventChallengeCreated:
+		if next.ChallengeID == nil {
+			next.ChallengeID = event.ChallengeID
+		}
+		if next.Creator == "" {
+			next.Creator = event.Creator
+		}
+		if event.TokenAddress != "" && next.TokenAddress == nil {
+			token := event.TokenAddress
+			next.TokenAddress = &token
+		}
+		if next.RewardRaw == nil {
+			next.RewardRaw = event.RewardRaw
+		}
+		if next.Deadline == nil {
+			next.Deadline = event.Deadline
+		}
+	case EventVerifierSelected:
+		setVerifier()
+		advance(ChallengeActive)
+	case EventRewardsDistributed:
+		setVerifier()
+		advance(ChallengeCompleted)
+	case EventChallengeCancelled:
+		advance(ChallengeCancelled)
+	}
+
+	return next, current == nil || !sameChallenge(before, next)
+}
+
+func sameChallenge(a, b Challenge) bool {
+	return equalPtr(a.ChallengeID, b.ChallengeID) &&
+		a.Creator == b.Creator &&
+		equalPtr(a.Verifier, b.Verifier) &&
+		a.Status == b.Status &&
+		equalPtr(a.TokenAddress, b.TokenAddress) &&
+		(a.RewardRaw == nil) == (b.RewardRaw == nil) &&
+		(a.RewardRaw == nil || a.RewardRaw.Cmp(*b.RewardRaw) == 0) &&
+		(a.Deadline == nil) == (b.Deadline == nil) &&
+		(a.Deadline == nil || a.Deadline.Equal(*b.Deadline))
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// CodeVisibleTo reports whether viewer may read the solution code that
+// solver submitted to this challenge. While the challenge is open or active
+// only the solver, the creator and the selected verifier can; once rewards
+// are distributed or the challenge is cancelled the code is public. A nil
+// challenge (not yet indexed) is treated as open.
+func (c *Challenge) CodeVisibleTo(viewer, solver string) bool {
+	if c != nil && (c.Status == ChallengeCompleted || c.Status == ChallengeCancelled) {
+		return true
+	}
+	if viewer == "" {
+		return false
+	}
+	if strings.EqualFold(viewer, solver) {
+		return true
+	}
+	if c == nil {
+		return false
+	}
+	return strings.EqualFold(viewer, c.Creator) || (c.Verifier != nil && strings.EqualFold(viewer, *c.Verifier))
+}
@@ -0,0 +1,1325 @@
+// Package memory provides in-memory implementations of the database
+// repositories. They mirror the Postgres semantics closely enough for local
+// development without a database and for exercising handlers in isolation.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+)
+
+type eventKey struct {
+	transactionHash string
+	logIndex        int
+}
+
+// Store holds every table in memory behind a single mutex, which also gives
+// RecordReputationEvent the same all-or-nothing behaviour as a transaction.
+type Store struct {
+	mu sync.RWMutex
+
+	nextIDs       map[string]int
+	submissions   map[string]*database.Submission
+	leaderboard   map[string]*database.LeaderboardEntry
+	winnings      map[string][]database.TokenWinnings
+	events        []database.ReputationEvent
+	approvals     []database.SolutionApproval
+	thresholds    []database.ThresholdUpdate
+	challenges    map[string]*database.Challenge
+	commitments   []*database.Commitment
+	fingerprints  map[string]*database.SubmissionFingerprint
+	flags         []database.SimilarityFlag
+	verifications map[string]*database.SubmissionVerification
+	reviews       []*database.VerifierReview
+	jobs          []*database.ScheduledJob
+	notifications []database.Notification
+	webhooks      []*database.Webhook
+	deliveries    []*database.WebhookDelivery
+	rateBuckets   map[string]database.RateBucket
+	seenKeys      map[string]map[eventKey]bool
+	// usdc is the USDC token address; only its amounts count towards the
+	// leaderboard's USDC columns.
+	usdc string
+}
+
+func NewStore(usdc string) *Store {
+	return &Store{
+		usdc:          usdc,
+		nextIDs:       make(map[string]int),
+		submissions:   make(map[string]*database.Submission),
+		leaderboard:   make(map[string]*database.LeaderboardEntry),
+		winnings:      make(map[string][]database.TokenWinnings),
+		challenges:    make(map[string]*database.Challenge),
+		fingerprints:  make(map[string]*database.SubmissionFingerprint),
+		verifications: make(map[string]*database.SubmissionVerification),
+		rateBuckets:   make(map[string]database.RateBucket),
+		seenKeys:      make(map[string]map[eventKey]bool),
+	}
+}
+
+// Repositories returns the store's repositories for injection into handlers.
+func (s *Store) Repositories() *database.Repositories {
+	return &database.Repositories{
+		Submissions: s,
+		Leaderboard: s,
+		Reputation:  s,
+		Challenges:  s,
+		Commitments: s,
+		Similarity:  s,
+		Reviews:     s,
+		Jobs:        s,
+		Webhooks:    s,
+		RateLimits:  s,
+	}
+}
+
+// id hands out per-table serial IDs, like a Postgres SERIAL column.
+func (s *Store) id(table string) int {
+	s.nextIDs[table]++
+	return s.nextIDs[table]
+}
+
+// claim marks a chain event key as seen in the named table and reports
+// whether it was new.
+func (s *Store) claim(table string, key eventKey) bool {
+	keys, exists := s.seenKeys[table]
+	if !exists {
+		keys = make(map[eventKey]bool)
+		s.seenKeys[table] = keys
+	}
+	if keys[key] {
+		return false
+	}
+	keys[key] = true
+	return true
+}
+
+func page[T any](items []T, limit int, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+func (s *Store) CreateSubmission(ctx context.Context, submission *database.Submission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertSubmission(submission)
+}
+
+func (s *Store) insertSubmission(submission *database.Submission) error {
+	if _, exists := s.submissions[submission.UID]; exists {
+		return fmt.Errorf("%w: %s", database.ErrSubmissionExists, submission.UID)
+	}
+
+	now := time.Now()
+	submission.ID = s.id("submissions")
+	submission.CreatedAt = now
+	submission.UpdatedAt = now
+
+	stored := *submission
+	s.submissions[submission.UID] = &stored
+	return nil
+}
+
+func (s *Store) GetSubmissionByUID(ctx context.Context, uid string) (*database.Submission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	submission, exists := s.submissions[uid]
+	if !exists {
+		return nil, nil
+	}
+	result := *submission
+	return &result, nil
+}
+
+func (s *Store) UpdateSubmissionStatus(ctx context.Context, uid string, status string, solutionHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	submission, exists := s.submissions[uid]
+	if !exists {
+		return nil
+	}
+	submission.Status = status
+	submission.SolutionHash = &solutionHash
+	submission.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *Store) MarkLateSubmissions(ctx context.Context, challengeAddress string, deadline time.Time) ([]database.Submission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revealed := make(map[string]bool)
+	for _, commitment := range s.commitments {
+		if commitment.SubmissionUID != nil {
+			revealed[*commitment.SubmissionUID] = true
+		}
+	}
+
+	late := []database.Submission{}
+	now := time.Now()
+	for _, submission := range s.submissions {
+		if submission.ChallengeAddress == challengeAddress && submission.CreatedAt.After(deadline) &&
+			submission.Status == database.SubmissionPending && !revealed[submission.UID] {
+			submission.Status = database.SubmissionLate
+			submission.UpdatedAt = now
+			result := *submission
+			result.SolutionCode = ""
+			late = append(late, result)
+		}
+	}
+	return late, nil
+}
+
+func (s *Store) ListSubmissions(ctx context.Context, query database.SubmissionQuery) ([]database.Submission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	oldest := query.Sort == database.SortOldest
+	before := func(a, b *database.Submission) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt) == oldest
+		}
+		if a.ID == b.ID {
+			return false
+		}
+		return (a.ID < b.ID) == oldest
+	}
+
+	var cursor *database.Submission
+	if query.After != nil {
+		cursor = &database.Submission{ID: query.After.ID, CreatedAt: query.After.CreatedAt}
+	}
+
+	submissions := []database.Submission{}
+	for _, submission := range s.submissions {
+		switch {
+		case query.WalletAddress != "" && submission.WalletAddress != query.WalletAddress,
+			query.ChallengeAddress != "" && submission.ChallengeAddress != query.ChallengeAddress,
+			len(query.Statuses) > 0 && !slices.Contains(query.Statuses, submission.Status),
+			query.CreatedAfter != nil && submission.CreatedAt.Before(*query.CreatedAfter),
+			query.CreatedBefore != nil && !submission.CreatedAt.Before(*query.CreatedBefore),
+			cursor != nil && !before(cursor, submission):
+			continue
+		}
+
+		row := *submission
+		if !query.IncludeCode {
+			row.SolutionCode = ""
+		}
+		submissions = append(submissions, row)
+	}
+
+	sort.Slice(submissions, func(i, j int) bool {
+		return before(&submissions[i], &submissions[j])
+	})
+	return page(submissions, query.Limit, 0), nil
+}
+
+func (s *Store) sortedLeaderboard() []database.LeaderboardEntry {
+	entries := make([]database.LeaderboardEntry, 0, len(s.leaderboard))
+	for _, entry := range s.leaderboard {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.ReputationScore != b.ReputationScore {
+			return a.ReputationScore > b.ReputationScore
+		}
+		if cmp := a.TotalUSDCWon.Cmp(b.TotalUSDCWon); cmp != 0 {
+			return cmp > 0
+		}
+		return a.WalletAddress < b.WalletAddress
+	})
+	return entries
+}
+
+func (s *Store) GetLeaderboard(ctx context.Context, limit int, offset int) ([]database.LeaderboardEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return page(s.sortedLeaderboard(), limit, offset), nil
+}
+
+func eventTime(event database.ReputationEvent) time.Time {
+	if event.BlockTimestamp != nil {
+		return *event.BlockTimestamp
+	}
+	return event.CreatedAt
+}
+
+func (s *Store) GetWindowedLeaderboard(ctx context.Context, start, end time.Time, role string, limit int, offset int) ([]database.WindowedLeaderboardEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byWallet := make(map[string]*database.WindowedLeaderboardEntry)
+	for _, event := range s.events {
+		at := eventTime(event)
+		if at.Before(start) || !at.Before(end) {
+			continue
+		}
+		if role != "" && event.IsVerifier != (role == database.RoleVerifier) {
+			continue
+		}
+
+		entry, exists := byWallet[event.WalletAddress]
+		if !exists {
+			entry = &database.WindowedLeaderboardEntry{WalletAddress: event.WalletAddress}
+			byWallet[event.WalletAddress] = entry
+		}
+		entry.Points += event.PointsAdded
+		if event.IsVerifier {
+			entry.ChallengesVerified++
+		} else {
+			entry.ChallengesWon++
+			if database.IsUSDC(event.TokenAddress, s.usdc) {
+				entry.TotalUSDCWon = entry.TotalUSDCWon.Add(event.USDCAmount)
+			}
+		}
+	}
+
+	entries := make([]database.WindowedLeaderboardEntry, 0, len(byWallet))
+	for _, entry := range byWallet {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if cmp := a.TotalUSDCWon.Cmp(b.TotalUSDCWon); cmp != 0 {
+			return cmp > 0
+		}
+		return a.WalletAddress < b.WalletAddress
+	})
+
+	return page(entries, limit, offset), nil
+}
+
+func (s *Store) GetSolverLeaderboard(ctx context.Context, limit int, offset int) ([]database.SolverLeaderboardEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entered := make(map[string]map[string]bool)
+	for _, submission := range s.submissions {
+		if entered[submission.WalletAddress] == nil {
+			entered[submission.WalletAddress] = make(map[string]bool)
+		}
+		entered[submission.WalletAddress][submission.ChallengeAddress] = true
+	}
+
+	var entries []database.SolverLeaderboardEntry
+	for _, row := range s.leaderboard {
+		if row.ChallengesWon == 0 {
+			continue
+		}
+		entry := database.SolverLeaderboardEntry{
+			WalletAddress:     row.WalletAddress,
+			SolverPoints:      row.ReputationScore - row.VerifierPoints,
+			Wins:              row.ChallengesWon,
+			TotalUSDCWon:      row.TotalUSDCWon,
+			ChallengesEntered: len(entered[row.WalletAddress]),
+		}
+		if entry.ChallengesEntered > 0 {
+			rate := float64(entry.Wins) / float64(entry.ChallengesEntered)
+			if rate > 1 {
+				rate = 1
+			}
+			entry.WinRate = &rate
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.SolverPoints != b.SolverPoints {
+			return a.SolverPoints > b.SolverPoints
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if cmp := a.TotalUSDCWon.Cmp(b.TotalUSDCWon); cmp != 0 {
+			return cmp > 0
+		}
+		return a.WalletAddress < b.WalletAddress
+	})
+
+	return page(entries, limit, offset), nil
+}
+
+func (s *Store) GetVerifierLeaderboard(ctx context.Context, limit int, offset int) ([]database.VerifierLeaderboardEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	latencyTotal := make(map[string]float64)
+	latencyCount := make(map[string]int)
+	for _, approval := range s.approvals {
+		if !approval.IsVerifier || approval.SubmissionUID == nil {
+			continue
+		}
+		submission, exists := s.submissions[*approval.SubmissionUID]
+		if !exists {
+			continue
+		}
+		approvedAt := approval.CreatedAt
+		if approval.BlockTimestamp != nil {
+			approvedAt = *approval.BlockTimestamp
+		}
+		latencyTotal[approval.Approver] += approvedAt.Sub(submission.CreatedAt).Seconds()
+		latencyCount[approval.Approver]++
+	}
+
+	var entries []database.VerifierLeaderboardEntry
+	for _, row := range s.leaderboard {
+		if row.ChallengesVerified == 0 {
+			continue
+		}
+		entry := database.VerifierLeaderboardEntry{
+			WalletAddress:  row.WalletAddress,
+			VerifierPoints: row.VerifierPoints,
+			Verifications:  row.ChallengesVerified,
+			FeesEarned:     row.VerifierFeesEarned,
+		}
+		if count := latencyCount[row.WalletAddress]; count > 0 {
+			average := latencyTotal[row.WalletAddress] / float64(count)
+			entry.AvgApprovalLatencySeconds = &average
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.VerifierPoints != b.VerifierPoints {
+			return a.VerifierPoints > b.VerifierPoints
+		}
+		if a.Verifications != b.Verifications {
+			return a.Verifications > b.Verifications
+		}
+		return a.WalletAddress < b.WalletAddress
+	})
+
+	return page(entries, limit, offset), nil
+}
+
+func (s *Store) GetUserStats(ctx context.Context, walletAddress string) (*database.LeaderboardEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.leaderboard[walletAddress]
+	if !exists {
+		return nil, nil
+	}
+	result := *entry
+	return &result, nil
+}
+
+func (s *Store) GetTokenWinnings(ctx context.Context, walletAddress string) ([]database.TokenWinnings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]database.TokenWinnings(nil), s.winnings[walletAddress]...), nil
+}
+
+func (s *Store) GetLeaderboardPosition(ctx context.Context, walletAddress string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.leaderboard[walletAddress]
+	if !exists {
+		return 1, nil
+	}
+
+	position := 1
+	for _, other := range s.leaderboard {
+		if other.ReputationScore > entry.ReputationScore {
+			position++
+		}
+	}
+	return position, nil
+}
+
+func (s *Store) GetTopPoints(ctx context.Context, limit int) ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var points []int
+	for _, entry := range s.sortedLeaderboard() {
+		points = append(points, entry.ReputationScore)
+	}
+	return page(points, limit, 0), nil
+}
+
+func (s *Store) CountUsers(ctx context.Context, points int) (int, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	below := 0
+	for _, entry := range s.leaderboard {
+		if entry.ReputationScore < points {
+			below++
+		}
+	}
+	return len(s.leaderboard), below, nil
+}
+
+// replayOrdered returns events in block order, matching the Postgres
+// ORDER BY block_number, log_index NULLS FIRST, id.
+func replayOrdered(events []database.ReputationEvent) []database.ReputationEvent {
+	ordered := append([]database.ReputationEvent(nil), events...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.BlockNumber != b.BlockNumber {
+			return a.BlockNumber < b.BlockNumber
+		}
+		if (a.LogIndex == nil) != (b.LogIndex == nil) {
+			return a.LogIndex == nil
+		}
+		if a.LogIndex != nil && *a.LogIndex != *b.LogIndex {
+			return *a.LogIndex < *b.LogIndex
+		}
+		return a.ID < b.ID
+	})
+	return ordered
+}
+
+// writeProjection stores the leaderboard and winnings rows replayed from
+// events, replacing the winnings of every wallet the events touch.
+func (s *Store) writeProjection(events []database.ReputationEvent) {
+	s.writeEntries(database.ReplayReputationEvents(events, s.usdc))
+
+	now := time.Now()
+	for _, event := range events {
+		delete(s.winnings, event.WalletAddress)
+	}
+	for _, winnings := range database.ReplayTokenWinnings(events) {
+		winnings.LastUpdated = now
+		s.winnings[winnings.WalletAddress] = append(s.winnings[winnings.WalletAddress], winnings)
+	}
+}
+
+func (s *Store) writeEntries(entries []database.LeaderboardEntry) {
+	now := time.Now()
+	for _, entry := range entries {
+		if existing, exists := s.leaderboard[entry.WalletAddress]; exists {
+			entry.ID = existing.ID
+		} else {
+			entry.ID = s.id("leaderboard")
+		}
+		entry.LastUpdated = now
+		stored := entry
+		s.leaderboard[entry.WalletAddress] = &stored
+	}
+}
+
+func (s *Store) RebuildLeaderboard(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.leaderboard = make(map[string]*database.LeaderboardEntry)
+	s.winnings = make(map[string][]database.TokenWinnings)
+	s.writeProjection(replayOrdered(s.events))
+	return len(s.events), nil
+}
+
+func (s *Store) CheckLeaderboardConsistency(ctx context.Context) (*database.ConsistencyReport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored := make([]database.LeaderboardEntry, 0, len(s.leaderboard))
+	for _, entry := range s.leaderboard {
+		stored = append(stored, *entry)
+	}
+	var storedWinnings []database.TokenWinnings
+	for _, winnings := range s.winnings {
+		storedWinnings = append(storedWinnings, winnings...)
+	}
+	return database.BuildConsistencyReport(replayOrdered(s.events), s.usdc, stored, storedWinnings), nil
+}
+
+func (s *Store) RecordReputationEvent(ctx context.Context, event *database.ReputationEvent) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.LogIndex != nil && !s.claim("reputation_events", eventKey{event.TransactionHash, *event.LogIndex}) {
+		return false, nil
+	}
+
+	event.ID = s.id("reputation_events")
+	event.CreatedAt = time.Now()
+	s.events = append(s.events, *event)
+
+	var walletEvents []database.ReputationEvent
+	for _, recorded := range s.events {
+		if recorded.WalletAddress == event.WalletAddress {
+			walletEvents = append(walletEvents, recorded)
+		}
+	}
+	s.writeProjection(replayOrdered(walletEvents))
+
+	return true, nil
+}
+
+func (s *Store) GetPointsBefore(ctx context.Context, walletAddress string, blockNumber int64, logIndex int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	points := 0
+	for _, event := range s.events {
+		if event.WalletAddress != walletAddress {
+			continue
+		}
+		eventLogIndex := -1
+		if event.LogIndex != nil {
+			eventLogIndex = *event.LogIndex
+		}
+		if event.BlockNumber < blockNumber || (event.BlockNumber == blockNumber && eventLogIndex < logIndex) {
+			points += event.PointsAdded
+		}
+	}
+	return points, nil
+}
+
+func (s *Store) GetRecentReputationEvents(ctx context.Context, limit int) ([]database.ReputationEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := append([]database.ReputationEvent(nil), s.events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].ID > events[j].ID
+	})
+	return page(events, limit, 0), nil
+}
+
+func (s *Store) CreateSolutionApproval(ctx context.Context, approval *database.SolutionApproval) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.claim("solution_approvals", eventKey{approval.TransactionHash, approval.LogIndex}) {
+		return false, nil
+	}
+
+	approval.ID = s.id("solution_approvals")
+	approval.CreatedAt = time.Now()
+	s.approvals = append(s.approvals, *approval)
+
+	if approval.SubmissionUID != nil {
+		approvedAt := approval.CreatedAt
+		if approval.BlockTimestamp != nil {
+			approvedAt = *approval.BlockTimestamp
+		}
+		for _, review := range s.reviews {
+			if review.SubmissionUID == *approval.SubmissionUID && strings.EqualFold(review.VerifierAddress, approval.Approver) {
+				txHash, logIndex := approval.TransactionHash, approval.LogIndex
+				review.ApprovalTransactionHash = &txHash
+				review.ApprovalLogIndex = &logIndex
+				review.ApprovedAt = &approvedAt
+			}
+		}
+	}
+	return true, nil
+}
+
+func (s *Store) CreateThresholdUpdate(ctx context.Context, update *database.ThresholdUpdate) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.claim("threshold_updates", eventKey{update.TransactionHash, update.LogIndex}) {
+		return false, nil
+	}
+
+	update.ID = s.id("threshold_updates")
+	update.CreatedAt = time.Now()
+	s.thresholds = append(s.thresholds, *update)
+	return true, nil
+}
+
+func (s *Store) GetThresholdHistory(ctx context.Context, limit int) ([]database.ThresholdUpdate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	updates := append([]database.ThresholdUpdate(nil), s.thresholds...)
+	sort.Slice(updates, func(i, j int) bool {
+		if updates[i].BlockNumber != updates[j].BlockNumber {
+			return updates[i].BlockNumber > updates[j].BlockNumber
+		}
+		return updates[i].LogIndex > updates[j].LogIndex
+	})
+	return page(updates, limit, 0), nil
+}
+
+func (s *Store) GetChallenge(ctx context.Context, address string) (*database.Challenge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	challenge, exists := s.challenges[address]
+	if !exists {
+		return nil, nil
+	}
+	result := *challenge
+	return &result, nil
+}
+
+func (s *Store) RecordChallengeEvent(ctx context.Context, event database.ChallengeEvent) (*database.Challenge, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.challenges[event.ChallengeAddress]
+	next, changed := database.ApplyChallengeEvent(current, event)
+	if !changed {
+		result := *current
+		return &result, false, nil
+	}
+
+	now := time.Now()
+	if current == nil {
+		next.CreatedAt = now
+	}
+	next.UpdatedAt = now
+	s.challenges[event.ChallengeAddress] = &next
+
+	result := next
+	return &result, true, nil
+}
+
+func (s *Store) CreateCommitment(ctx context.Context, commitment *database.Commitment) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.commitments {
+		if existing.Commitment == commitment.Commitment {
+			return false, nil
+		}
+	}
+
+	commitment.ID = s.id("commitments")
+	commitment.CommittedAt = time.Now()
+	stored := *commitment
+	s.commitments = append(s.commitments, &stored)
+	return true, nil
+}
+
+func (s *Store) GetCommitment(ctx context.Context, hash string) (*database.Commitment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, commitment := range s.commitments {
+		if commitment.Commitment == hash {
+			result := *commitment
+			return &result, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) RevealCommitment(ctx context.Context, hash string, submission *database.Submission) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, commitment := range s.commitments {
+		if commitment.Commitment != hash {
+			continue
+		}
+		if commitment.RevealedAt != nil {
+			return false, nil
+		}
+		if err := s.insertSubmission(submission); err != nil {
+			return false, err
+		}
+
+		now := time.Now()
+		uid := submission.UID
+		commitment.RevealedAt = &now
+		commitment.SubmissionUID = &uid
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *Store) ListCommitments(ctx context.Context, challengeAddress string) ([]database.Commitment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	commitments := []database.Commitment{}
+	for _, commitment := range s.commitments {
+		if commitment.ChallengeAddress == challengeAddress {
+			commitments = append(commitments, *commitment)
+		}
+	}
+	// Commitments are appended in CommittedAt order already.
+	return commitments, nil
+}
+
+func (s *Store) SaveFingerprint(ctx context.Context, fingerprint *database.SubmissionFingerprint, flags []database.SimilarityFlag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.submissions[fingerprint.SubmissionUID]; !exists {
+		return fmt.Errorf("submission %q does not exist", fingerprint.SubmissionUID)
+	}
+
+	if existing, exists := s.fingerprints[fingerprint.SubmissionUID]; exists {
+		fingerprint.CreatedAt = existing.CreatedAt
+	} else {
+		fingerprint.CreatedAt = time.Now()
+	}
+	stored := *fingerprint
+	stored.Fingerprint = slices.Clone(fingerprint.Fingerprint)
+	s.fingerprints[fingerprint.SubmissionUID] = &stored
+
+	s.flags = slices.DeleteFunc(s.flags, func(flag database.SimilarityFlag) bool {
+		return flag.SubmissionUID == fingerprint.SubmissionUID
+	})
+	now := time.Now()
+	for i := range flags {
+		flags[i].ID = s.id("similarity_flags")
+		flags[i].CreatedAt = now
+		s.flags = append(s.flags, flags[i])
+	}
+	return nil
+}
+
+func (s *Store) ListFingerprints(ctx context.Context, challengeAddress string) ([]database.SubmissionFingerprint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fingerprints := []database.SubmissionFingerprint{}
+	for _, fingerprint := range s.fingerprints {
+		if fingerprint.ChallengeAddress == challengeAddress {
+			fingerprints = append(fingerprints, *fingerprint)
+		}
+	}
+
+	// Submission IDs increase with creation time.
+	sort.Slice(fingerprints, func(i, j int) bool {
+		return s.submissions[fingerprints[i].SubmissionUID].ID < s.submissions[fingerprints[j].SubmissionUID].ID
+	})
+	return fingerprints, nil
+}
+
+func (s *Store) ListSimilarityFlags(ctx context.Context, challengeAddress string) ([]database.SimilarityFlag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flags := []database.SimilarityFlag{}
+	for _, flag := range s.flags {
+		if flag.ChallengeAddress == challengeAddress {
+			flags = append(flags, flag)
+		}
+	}
+
+	sort.SliceStable(flags, func(i, j int) bool {
+		if flags[i].Score != flags[j].Score {
+			return flags[i].Score > flags[j].Score
+		}
+		return flags[i].ID < flags[j].ID
+	})
+	return flags, nil
+}
+
+func (s *Store) ListChallengesByVerifier(ctx context.Context, verifier string, status string) ([]database.Challenge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	challenges := []database.Challenge{}
+	for _, challenge := range s.challenges {
+		if challenge.Status == status && challenge.Verifier != nil && strings.EqualFold(*challenge.Verifier, verifier) {
+			challenges = append(challenges, *challenge)
+		}
+	}
+
+	sort.Slice(challenges, func(i, j int) bool {
+		if !challenges[i].CreatedAt.Equal(challenges[j].CreatedAt) {
+			return challenges[i].CreatedAt.Before(challenges[j].CreatedAt)
+		}
+		return challenges[i].Address < challenges[j].Address
+	})
+	return challenges, nil
+}
+
+func (s *Store) SaveVerification(ctx context.Context, verification *database.SubmissionVerification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.submissions[verification.SubmissionUID]; !exists {
+		return fmt.Errorf("submission %q does not exist", verification.SubmissionUID)
+	}
+
+	verification.UpdatedAt = time.Now()
+	stored := *verification
+	s.verifications[verification.SubmissionUID] = &stored
+	return nil
+}
+
+func (s *Store) ListVerifications(ctx context.Context, challengeAddress string) ([]database.SubmissionVerification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	verifications := []database.SubmissionVerification{}
+	for uid, verification := range s.verifications {
+		if s.submissions[uid].ChallengeAddress == challengeAddress {
+			verifications = append(verifications, *verification)
+		}
+	}
+	return verifications, nil
+}
+
+func (s *Store) SaveReview(ctx context.Context, review *database.VerifierReview) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.submissions[review.SubmissionUID]; !exists {
+		return fmt.Errorf("submission %q does not exist", review.SubmissionUID)
+	}
+
+	now := time.Now()
+	for _, existing := range s.reviews {
+		if existing.SubmissionUID == review.SubmissionUID && existing.VerifierAddress == review.VerifierAddress {
+			existing.Recommendation = review.Recommendation
+			existing.Notes = review.Notes
+			existing.UpdatedAt = now
+			*review = *existing
+			return nil
+		}
+	}
+
+	review.ID = s.id("verifier_reviews")
+	review.CreatedAt = now
+	review.UpdatedAt = now
+	stored := *review
+	s.reviews = append(s.reviews, &stored)
+	return nil
+}
+
+func (s *Store) ListReviews(ctx context.Context, verifier string) ([]database.VerifierReview, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reviews := []database.VerifierReview{}
+	for i := len(s.reviews) - 1; i >= 0; i-- {
+		if s.reviews[i].VerifierAddress == verifier {
+			reviews = append(reviews, *s.reviews[i])
+		}
+	}
+	return reviews, nil
+}
+
+func (s *Store) ListChallengesWithDeadlines(ctx context.Context, after time.Time) ([]database.Challenge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	challenges := []database.Challenge{}
+	for _, challenge := range s.challenges {
+		if (challenge.Status == database.ChallengeOpen || challenge.Status == database.ChallengeActive) &&
+			challenge.Deadline != nil && !challenge.Deadline.Before(after) {
+			challenges = append(challenges, *challenge)
+		}
+	}
+
+	sort.Slice(challenges, func(i, j int) bool {
+		if !challenges[i].Deadline.Equal(*challenges[j].Deadline) {
+			return challenges[i].Deadline.Before(*challenges[j].Deadline)
+		}
+		return challenges[i].Address < challenges[j].Address
+	})
+	return challenges, nil
+}
+
+func (s *Store) ListChallenges(ctx context.Context, status string, limit, offset int) ([]database.Challenge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	challenges := []database.Challenge{}
+	for _, challenge := range s.challenges {
+		if status == "" || challenge.Status == status {
+			challenges = append(challenges, *challenge)
+		}
+	}
+
+	sort.Slice(challenges, func(i, j int) bool {
+		if !challenges[i].CreatedAt.Equal(challenges[j].CreatedAt) {
+			return challenges[i].CreatedAt.After(challenges[j].CreatedAt)
+		}
+		return challenges[i].Address < challenges[j].Address
+	})
+
+	if offset >= len(challenges) {
+		return []database.Challenge{}, nil
+	}
+	challenges = challenges[offset:]
+	if len(challenges) > limit {
+		challenges = challenges[:limit]
+	}
+	return challenges, nil
+}
+
+func (s *Store) ScheduleJob(ctx context.Context, job *database.ScheduledJob) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, existing := range s.jobs {
+		if existing.Kind != job.Kind || existing.ChallengeAddress != job.ChallengeAddress {
+			continue
+		}
+		if existing.Status != database.JobPending {
+			return false, nil
+		}
+		existing.RunAt = job.RunAt
+		existing.UpdatedAt = now
+		*job = *existing
+		return true, nil
+	}
+
+	job.ID = s.id("scheduled_jobs")
+	job.Status = database.JobPending
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	stored := *job
+	s.jobs = append(s.jobs, &stored)
+	return true, nil
+}
+
+func (s *Store) ClaimDueJobs(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]database.ScheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*database.ScheduledJob
+	for _, job := range s.jobs {
+		if (job.Status == database.JobPending && !job.RunAt.After(now)) ||
+			(job.Status == database.JobRunning && job.LockedUntil != nil && job.LockedUntil.Before(now)) {
+			due = append(due, job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].RunAt.Equal(due[j].RunAt) {
+			return due[i].RunAt.Before(due[j].RunAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+
+	claimed := []database.ScheduledJob{}
+	lockedUntil := now.Add(lease)
+	for _, job := range page(due, limit, 0) {
+		job.Status = database.JobRunning
+		job.LockedUntil = &lockedUntil
+		job.Attempts++
+		job.UpdatedAt = time.Now()
+		claimed = append(claimed, *job)
+	}
+	return claimed, nil
+}
+
+func (s *Store) CompleteJob(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.ID == id {
+			job.Status = database.JobDone
+			job.LockedUntil = nil
+			job.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (s *Store) RetryJob(ctx context.Context, id int, runAt *time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.ID != id {
+			continue
+		}
+		job.Status = database.JobFailed
+		if runAt != nil {
+			job.Status = database.JobPending
+			job.RunAt = *runAt
+		}
+		job.LastError = &lastError
+		job.LockedUntil = nil
+		job.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) ListJobs(ctx context.Context, challengeAddress string) ([]database.ScheduledJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := []database.ScheduledJob{}
+	for _, job := range s.jobs {
+		if job.ChallengeAddress == challengeAddress {
+			jobs = append(jobs, *job)
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].RunAt.Equal(jobs[j].RunAt) {
+			return jobs[i].RunAt.Before(jobs[j].RunAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs, nil
+}
+
+func (s *Store) CreateNotification(ctx context.Context, notification *database.Notification) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.notifications {
+		if existing.ChallengeAddress == notification.ChallengeAddress && existing.Kind == notification.Kind {
+			return false, nil
+		}
+	}
+
+	notification.ID = s.id("notifications")
+	notification.CreatedAt = time.Now()
+	s.notifications = append(s.notifications, *notification)
+	return true, nil
+}
+
+func (s *Store) ListNotifications(ctx context.Context, challengeAddress string, limit int) ([]database.Notification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	notifications := []database.Notification{}
+	for i := len(s.notifications) - 1; i >= 0; i-- {
+		if challengeAddress == "" || s.notifications[i].ChallengeAddress == challengeAddress {
+			notifications = append(notifications, s.notifications[i])
+		}
+	}
+	return page(notifications, limit, 0), nil
+}
+
+func (s *Store) CreateWebhook(ctx context.Context, webhook *database.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook.ID = s.id("webhooks")
+	webhook.Active = true
+	webhook.CreatedAt = time.Now()
+	stored := *webhook
+	stored.Events = slices.Clone(webhook.Events)
+	s.webhooks = append(s.webhooks, &stored)
+	return nil
+}
+
+func (s *Store) GetWebhook(ctx context.Context, id int) (*database.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, webhook := range s.webhooks {
+		if webhook.ID == id {
+			found := *webhook
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) ListWebhooks(ctx context.Context, owner string) ([]database.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhooks := []database.Webhook{}
+	for _, webhook := range s.webhooks {
+		if webhook.Owner == owner {
+			webhooks = append(webhooks, *webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (s *Store) DeactivateWebhook(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, webhook := range s.webhooks {
+		if webhook.ID == id {
+			webhook.Active = false
+		}
+	}
+	return nil
+}
+
+func (s *Store) EnqueueDeliveries(ctx context.Context, eventID, eventType, owner string, payload []byte, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queued := 0
+	for _, webhook := range s.webhooks {
+		if !webhook.Active || !slices.Contains(webhook.Events, eventType) || (owner != "" && webhook.Owner != owner) {
+			continue
+		}
+		s.deliveries = append(s.deliveries, &database.WebhookDelivery{
+			ID:            s.id("webhook_deliveries"),
+			WebhookID:     webhook.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       slices.Clone(payload),
+			Status:        database.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		})
+		queued++
+	}
+	return queued, nil
+}
+
+func (s *Store) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]database.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*database.WebhookDelivery
+	for _, delivery := range s.deliveries {
+		if (delivery.Status == database.DeliveryPending && !delivery.NextAttemptAt.After(now)) ||
+			(delivery.Status == database.DeliveryDelivering && delivery.LockedUntil != nil && delivery.LockedUntil.Before(now)) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+
+	claimed := []database.WebhookDelivery{}
+	lockedUntil := now.Add(lease)
+	for _, delivery := range page(due, limit, 0) {
+		delivery.Status = database.DeliveryDelivering
+		delivery.LockedUntil = &lockedUntil
+		delivery.Attempts++
+		delivery.UpdatedAt = time.Now()
+		claimed = append(claimed, *delivery)
+	}
+	return claimed, nil
+}
+
+func (s *Store) CompleteDelivery(ctx context.Context, id int, responseStatus int, deliveredAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, delivery := range s.deliveries {
+		if delivery.ID == id {
+			delivery.Status = database.DeliveryDelivered
+			delivery.ResponseStatus = &responseStatus
+			delivery.DeliveredAt = &deliveredAt
+			delivery.LastError = nil
+			delivery.LockedUntil = nil
+			delivery.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (s *Store) RetryDelivery(ctx context.Context, id int, nextAttempt *time.Time, responseStatus *int, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, delivery := range s.deliveries {
+		if delivery.ID != id {
+			continue
+		}
+		delivery.Status = database.DeliveryFailed
+		if nextAttempt != nil {
+			delivery.Status = database.DeliveryPending
+			delivery.NextAttemptAt = *nextAttempt
+		}
+		delivery.ResponseStatus = responseStatus
+		delivery.LastError = &lastError
+		delivery.LockedUntil = nil
+		delivery.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) GetDelivery(ctx context.Context, id int) (*database.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, delivery := range s.deliveries {
+		if delivery.ID == id {
+			found := *delivery
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, webhookID int, limit int) ([]database.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deliveries := []database.WebhookDelivery{}
+	for i := len(s.deliveries) - 1; i >= 0; i-- {
+		if s.deliveries[i].WebhookID == webhookID {
+			deliveries = append(deliveries, *s.deliveries[i])
+		}
+	}
+	return page(deliveries, limit, 0), nil
+}
+
+func (s *Store) Redeliver(ctx context.Context, id int, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, delivery := range s.deliveries {
+		if delivery.ID == id {
+			delivery.Status = database.DeliveryPending
+			delivery.Attempts = 0
+			delivery.NextAttemptAt = now
+			delivery.LockedUntil = nil
+			delivery.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (s *Store) UpdateRateBuckets(ctx context.Context, keys []string, update func(buckets []*database.RateBucket)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buckets := make([]*database.RateBucket, len(keys))
+	for i, key := range keys {
+		bucket, found := s.rateBuckets[key]
+		if !found {
+			bucket.Key = key
+		}
+		buckets[i] = &bucket
+	}
+	update(buckets)
+	for _, bucket := range buckets {
+		s.rateBuckets[bucket.Key] = *bucket
+	}
+	return nil
+}
+
+func (s *Store) DeleteIdleRateBuckets(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key, bucket := range s.rateBuckets {
+		if bucket.UpdatedAt.Before(before) {
+			delete(s.rateBuckets, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,280 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the pg_advisory_lock key held while migrating, so
+// replicas starting at the same time apply each migration exactly once.
+const migrationLockKey int64 = 0x6379727570 // "cyrup"
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64      `db:"version" json:"version"`
+	Name      string     `db:"name" json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `db:"applied_at" json:"applied_at,omitempty"`
+}
+
+// LoadMigrations parses the embedded migrations/NNNN_name.{up,down}.sql
+// files, ordered by version. Every version needs both scripts.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		contents, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both up and down scripts", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator applies the embedded migrations and records them in
+// schema_migrations. When DryRun is set it reports what would run without
+// changing the database.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+	DryRun     bool
+	Logf       func(format string, args ...interface{})
+}
+
+func NewMigrator(db *sqlx.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		Logf:       func(string, ...interface{}) {},
+	}, nil
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	ctx := context.Background()
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Up applies every pending migration in version order and returns them.
+func (m *Migrator) Up() ([]Migration, error) {
+	var run []Migration
+	err := m.withLock(func(ctx context.Context, conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration, true); err != nil {
+				return err
+			}
+			run = append(run, migration)
+		}
+		return nil
+	})
+	return run, err
+}
+
+// Down reverts the most recently applied n migrations, newest first.
+func (m *Migrator) Down(n int) ([]Migration, error) {
+	var run []Migration
+	err := m.withLock(func(ctx context.Context, conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(run) < n; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			run = append(run, migration)
+		}
+		return nil
+	})
+	return run, err
+}
+
+func (m *Migrator) withLock(fn func(ctx context.Context, conn *sqlx.Conn) error) error {
+	ctx := context.Background()
+
+	// Advisory locks belong to a session, so hold one connection throughout.
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	if m.DryRun {
+		return fn(ctx, conn)
+	}
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(ctx, conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sqlx.Conn) (map[int64]MigrationStatus, error) {
+	var exists bool
+	if err := conn.GetContext(ctx, &exists, `SELECT to_regclass('schema_migrations') IS NOT NULL`); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]MigrationStatus)
+	if !exists {
+		return applied, nil
+	}
+
+	var records []MigrationStatus
+	if err := conn.SelectContext(ctx, &records, `SELECT version, name, applied_at FROM schema_migrations`); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		record.Applied = true
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// apply runs one migration script and updates schema_migrations in the
+// same transaction, so a failed script leaves no record behind.
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, migration Migration, up bool) error {
+	direction, script := "up", migration.Up
+	if !up {
+		direction, script = "down", migration.Down
+	}
+
+	if m.DryRun {
+		m.Logf("-- [dry-run] %04d_%s (%s)\n%s", migration.Version, migration.Name, direction, script)
+		return nil
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %04d_%s %s failed: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	m.Logf("Applied migration %04d_%s (%s)", migration.Version, migration.Name, direction)
+	return nil
+}
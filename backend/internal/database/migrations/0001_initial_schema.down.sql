@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reputation_events;
+DROP TABLE IF EXISTS leaderboard;
+DROP TABLE IF EXISTS submissions;
@@ -0,0 +1,45 @@
+-- Baseline schema previously created by createTables. IF NOT EXISTS keeps
+-- this safe to apply to databases that predate schema_migrations.
+CREATE TABLE IF NOT EXISTS submissions (
+	id SERIAL PRIMARY KEY,
+	uid VARCHAR(255) UNIQUE NOT NULL,
+	challenge_address VARCHAR(42) NOT NULL,
+	wallet_address VARCHAR(42) NOT NULL,
+	solution_code TEXT NOT NULL,
+	solution_hash VARCHAR(255),
+	status VARCHAR(50) DEFAULT 'pending',
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_submissions_uid ON submissions(uid);
+CREATE INDEX IF NOT EXISTS idx_submissions_wallet ON submissions(wallet_address);
+CREATE INDEX IF NOT EXISTS idx_submissions_challenge ON submissions(challenge_address);
+
+CREATE TABLE IF NOT EXISTS leaderboard (
+	id SERIAL PRIMARY KEY,
+	wallet_address VARCHAR(42) UNIQUE NOT NULL,
+	reputation_score INTEGER DEFAULT 0,
+	total_usdc_won DECIMAL(20, 6) DEFAULT 0,
+	challenges_won INTEGER DEFAULT 0,
+	challenges_verified INTEGER DEFAULT 0,
+	last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_leaderboard_reputation ON leaderboard(reputation_score DESC);
+CREATE INDEX IF NOT EXISTS idx_leaderboard_wallet ON leaderboard(wallet_address);
+
+CREATE TABLE IF NOT EXISTS reputation_events (
+	id SERIAL PRIMARY KEY,
+	wallet_address VARCHAR(42) NOT NULL,
+	event_type VARCHAR(50) NOT NULL,
+	points_added INTEGER NOT NULL,
+	total_points INTEGER NOT NULL,
+	is_verifier BOOLEAN DEFAULT FALSE,
+	transaction_hash VARCHAR(66),
+	block_number BIGINT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reputation_events_wallet ON reputation_events(wallet_address);
+CREATE INDEX IF NOT EXISTS idx_reputation_events_block ON reputation_events(block_number);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reputation_events_key;
+ALTER TABLE reputation_events DROP COLUMN IF EXISTS usdc_amount;
+ALTER TABLE reputation_events DROP COLUMN IF EXISTS log_index;
@@ -0,0 +1,6 @@
+ALTER TABLE reputation_events ADD COLUMN IF NOT EXISTS log_index INTEGER;
+ALTER TABLE reputation_events ADD COLUMN IF NOT EXISTS usdc_amount DECIMAL(20, 6) NOT NULL DEFAULT 0;
+
+-- A chain event is identified by its transaction hash and log index, so
+-- replays of the same event are ignored instead of double counted.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reputation_events_key ON reputation_events(transaction_hash, log_index);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reputation_events_time;
+ALTER TABLE reputation_events DROP COLUMN IF EXISTS block_timestamp;
@@ -0,0 +1,2 @@
+ALTER TABLE reputation_events ADD COLUMN IF NOT EXISTS block_timestamp TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_reputation_events_time ON reputation_events((COALESCE(block_timestamp, created_at)));
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS solution_approvals;
+ALTER TABLE leaderboard DROP COLUMN IF EXISTS verifier_fees_earned;
+ALTER TABLE leaderboard DROP COLUMN IF EXISTS verifier_points;
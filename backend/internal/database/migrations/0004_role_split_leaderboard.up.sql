@@ -0,0 +1,20 @@
+ALTER TABLE leaderboard ADD COLUMN IF NOT EXISTS verifier_points INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN IF NOT EXISTS verifier_fees_earned DECIMAL(20, 6) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS solution_approvals (
+	id SERIAL PRIMARY KEY,
+	challenge_address VARCHAR(42) NOT NULL,
+	submission_id BIGINT NOT NULL,
+	submission_uid VARCHAR(255),
+	approver VARCHAR(42) NOT NULL,
+	is_verifier BOOLEAN NOT NULL DEFAULT TRUE,
+	transaction_hash VARCHAR(66) NOT NULL,
+	log_index INTEGER NOT NULL,
+	block_number BIGINT,
+	block_timestamp TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (transaction_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_solution_approvals_approver ON solution_approvals(approver);
+CREATE INDEX IF NOT EXISTS idx_solution_approvals_submission ON solution_approvals(submission_uid);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS threshold_updates (
+	id SERIAL PRIMARY KEY,
+	old_threshold INTEGER NOT NULL,
+	new_threshold INTEGER NOT NULL,
+	total_users INTEGER NOT NULL,
+	transaction_hash VARCHAR(66) NOT NULL,
+	log_index INTEGER NOT NULL,
+	block_number BIGINT NOT NULL,
+	block_timestamp TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (transaction_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_threshold_updates_block ON threshold_updates(block_number DESC, log_index DESC);
@@ -0,0 +1,6 @@
+ALTER TABLE reputation_events DROP COLUMN IF EXISTS token_address;
+ALTER TABLE reputation_events DROP COLUMN IF EXISTS amount_raw;
+
+ALTER TABLE leaderboard ALTER COLUMN verifier_fees_earned TYPE DECIMAL(20, 6);
+ALTER TABLE leaderboard ALTER COLUMN total_usdc_won TYPE DECIMAL(20, 6);
+ALTER TABLE reputation_events ALTER COLUMN usdc_amount TYPE DECIMAL(20, 6);
@@ -0,0 +1,12 @@
+-- Amounts are exact decimals in the token's own units. Unconstrained
+-- NUMERIC holds tokens with more than 6 decimals without rounding.
+ALTER TABLE reputation_events ALTER COLUMN usdc_amount TYPE NUMERIC;
+ALTER TABLE leaderboard ALTER COLUMN total_usdc_won TYPE NUMERIC;
+ALTER TABLE leaderboard ALTER COLUMN verifier_fees_earned TYPE NUMERIC;
+
+-- amount_raw is the uint256 passed to ReputationSystem.updateReputation.
+-- Events recorded before this migration were all USDC.
+ALTER TABLE reputation_events ADD COLUMN IF NOT EXISTS amount_raw NUMERIC(78, 0);
+ALTER TABLE reputation_events ADD COLUMN IF NOT EXISTS token_address VARCHAR(42);
+UPDATE reputation_events SET amount_raw = usdc_amount * 1000000 WHERE amount_raw IS NULL;
+ALTER TABLE reputation_events ALTER COLUMN amount_raw SET NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS winnings_by_token;
+ALTER TABLE reputation_events ALTER COLUMN token_address DROP NOT NULL;
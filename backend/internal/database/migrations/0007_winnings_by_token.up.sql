@@ -0,0 +1,32 @@
+-- Events recorded without a token address were paid in USDC. Deploy.s.sol
+-- targets the Ethereum mainnet deployment; databases indexing another chain
+-- should update these rows to their USDC address.
+UPDATE reputation_events
+SET token_address = '0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48'
+WHERE token_address IS NULL;
+ALTER TABLE reputation_events ALTER COLUMN token_address SET NOT NULL;
+
+-- Per-token projection of reputation_events, maintained alongside the
+-- leaderboard so amounts in different tokens are never added together.
+CREATE TABLE IF NOT EXISTS winnings_by_token (
+	wallet_address VARCHAR(42) NOT NULL,
+	token_address VARCHAR(42) NOT NULL,
+	amount_won NUMERIC NOT NULL DEFAULT 0,
+	wins INTEGER NOT NULL DEFAULT 0,
+	fees_earned NUMERIC NOT NULL DEFAULT 0,
+	verifications INTEGER NOT NULL DEFAULT 0,
+	last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (wallet_address, token_address)
+);
+
+INSERT INTO winnings_by_token (wallet_address, token_address, amount_won, wins, fees_earned, verifications)
+SELECT
+	wallet_address,
+	token_address,
+	COALESCE(SUM(usdc_amount) FILTER (WHERE NOT is_verifier), 0),
+	COUNT(*) FILTER (WHERE NOT is_verifier),
+	COALESCE(SUM(usdc_amount) FILTER (WHERE is_verifier), 0),
+	COUNT(*) FILTER (WHERE is_verifier)
+FROM reputation_events
+GROUP BY wallet_address, token_address
+ON CONFLICT (wallet_address, token_address) DO NOTHING;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_submissions_challenge_created;
+DROP INDEX IF EXISTS idx_submissions_wallet_created;
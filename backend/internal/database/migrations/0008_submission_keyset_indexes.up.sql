@@ -0,0 +1,3 @@
+-- Keyset pagination orders submission listings by (created_at, id).
+CREATE INDEX IF NOT EXISTS idx_submissions_wallet_created ON submissions(wallet_address, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_submissions_challenge_created ON submissions(challenge_address, created_at, id);
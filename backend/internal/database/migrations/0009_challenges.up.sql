@@ -0,0 +1,16 @@
+-- Challenge state folded from ChallengeEscrow events. Solution code
+-- visibility depends on the status, creator and selected verifier.
+CREATE TABLE IF NOT EXISTS challenges (
+	address VARCHAR(42) PRIMARY KEY,
+	challenge_id BIGINT,
+	creator VARCHAR(42) NOT NULL DEFAULT '',
+	verifier VARCHAR(42),
+	status VARCHAR(20) NOT NULL DEFAULT 'open',
+	token_address VARCHAR(42),
+	reward_raw NUMERIC(78, 0),
+	deadline TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_challenges_status ON challenges(status);
@@ -0,0 +1,13 @@
+-- Commit-reveal submissions. committed_at is assigned by the server and
+-- decides priority between solvers who reveal the same proof.
+CREATE TABLE IF NOT EXISTS commitments (
+	id SERIAL PRIMARY KEY,
+	challenge_address VARCHAR(42) NOT NULL,
+	wallet_address VARCHAR(42) NOT NULL,
+	commitment VARCHAR(66) UNIQUE NOT NULL,
+	committed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	revealed_at TIMESTAMP,
+	submission_uid VARCHAR(255) REFERENCES submissions(uid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commitments_challenge ON commitments(challenge_address, committed_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS similarity_flags;
+DROP TABLE IF EXISTS submission_fingerprints;
@@ -0,0 +1,26 @@
+-- Near-duplicate detection. Each submission's normalized code is
+-- fingerprinted once; flags record earlier submissions to the same challenge
+-- whose fingerprints are at least the configured similarity.
+CREATE TABLE IF NOT EXISTS submission_fingerprints (
+	submission_uid VARCHAR(255) PRIMARY KEY REFERENCES submissions(uid) ON DELETE CASCADE,
+	challenge_address VARCHAR(42) NOT NULL,
+	wallet_address VARCHAR(42) NOT NULL,
+	fingerprint BIGINT[] NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_submission_fingerprints_challenge ON submission_fingerprints(challenge_address);
+
+CREATE TABLE IF NOT EXISTS similarity_flags (
+	id SERIAL PRIMARY KEY,
+	challenge_address VARCHAR(42) NOT NULL,
+	submission_uid VARCHAR(255) NOT NULL REFERENCES submissions(uid) ON DELETE CASCADE,
+	wallet_address VARCHAR(42) NOT NULL,
+	matched_uid VARCHAR(255) NOT NULL REFERENCES submissions(uid) ON DELETE CASCADE,
+	matched_wallet VARCHAR(42) NOT NULL,
+	score DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (submission_uid, matched_uid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_similarity_flags_challenge ON similarity_flags(challenge_address);
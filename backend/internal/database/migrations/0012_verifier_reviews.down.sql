@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS verifier_reviews;
+DROP TABLE IF EXISTS submission_verifications;
@@ -0,0 +1,30 @@
+-- Automatic Lean checks of submissions, shown to the selected verifier.
+CREATE TABLE IF NOT EXISTS submission_verifications (
+	submission_uid VARCHAR(255) PRIMARY KEY REFERENCES submissions(uid) ON DELETE CASCADE,
+	verdict VARCHAR(20) NOT NULL,
+	diagnostics JSONB NOT NULL DEFAULT '[]',
+	axioms JSONB NOT NULL DEFAULT '{}',
+	output TEXT NOT NULL DEFAULT '',
+	checked_at TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A verifier's notes and recommendation for a submission. The approval
+-- columns are filled in when the verifier's SolutionApproved event for the
+-- submission is indexed.
+CREATE TABLE IF NOT EXISTS verifier_reviews (
+	id SERIAL PRIMARY KEY,
+	submission_uid VARCHAR(255) NOT NULL REFERENCES submissions(uid) ON DELETE CASCADE,
+	challenge_address VARCHAR(42) NOT NULL,
+	verifier_address VARCHAR(42) NOT NULL,
+	recommendation VARCHAR(20) NOT NULL,
+	notes TEXT NOT NULL DEFAULT '',
+	approval_transaction_hash VARCHAR(66),
+	approval_log_index INTEGER,
+	approved_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (submission_uid, verifier_address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_verifier_reviews_verifier ON verifier_reviews(verifier_address);
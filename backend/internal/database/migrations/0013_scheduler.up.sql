@@ -0,0 +1,29 @@
+-- Time-based challenge lifecycle jobs. A job is claimed by setting it
+-- running with a lease; jobs whose lease expired are claimed again, so work
+-- survives restarts.
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+	id SERIAL PRIMARY KEY,
+	kind VARCHAR(40) NOT NULL,
+	challenge_address VARCHAR(42) NOT NULL,
+	run_at TIMESTAMP NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT,
+	locked_until TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (kind, challenge_address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_due ON scheduled_jobs(status, run_at);
+
+CREATE TABLE IF NOT EXISTS notifications (
+	id SERIAL PRIMARY KEY,
+	challenge_address VARCHAR(42) NOT NULL,
+	kind VARCHAR(40) NOT NULL,
+	message TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (challenge_address, kind)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_created ON notifications(created_at);
@@ -0,0 +1,37 @@
+-- Webhook subscriptions. The secret signs deliveries and is only shown to
+-- the owner when the webhook is created.
+CREATE TABLE IF NOT EXISTS webhooks (
+	id SERIAL PRIMARY KEY,
+	owner VARCHAR(42) NOT NULL,
+	url TEXT NOT NULL,
+	secret VARCHAR(64) NOT NULL,
+	events TEXT[] NOT NULL,
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_owner ON webhooks(owner);
+
+-- Outbox of deliveries, one row per event and subscribed webhook. Rows are
+-- written when the event happens and claimed with a lease by the
+-- dispatcher, so deliveries survive restarts. The row doubles as the
+-- delivery log.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	id SERIAL PRIMARY KEY,
+	webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+	event_id VARCHAR(36) NOT NULL,
+	event_type VARCHAR(40) NOT NULL,
+	payload JSONB NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at TIMESTAMP NOT NULL,
+	locked_until TIMESTAMP,
+	response_status INTEGER,
+	last_error TEXT,
+	delivered_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id);
@@ -0,0 +1,10 @@
+-- Token buckets for rate limiting, shared by every API replica. A bucket
+-- that has not been touched for a full refill period is full again, so idle
+-- rows are pruned.
+CREATE TABLE IF NOT EXISTS rate_buckets (
+	key VARCHAR(128) PRIMARY KEY,
+	tokens DOUBLE PRECISION NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_buckets_updated ON rate_buckets(updated_at);
@@ -0,0 +1,3 @@
+-- The original case of each address is not kept, so there is nothing to
+-- restore; addresses stay lowercase.
+SELECT 1;
@@ -0,0 +1,72 @@
+-- Addresses are stored lowercase. Rows written before the API normalized
+-- them are lowercased here, and leaderboard rows that differed only in case
+-- are merged. Reputation history is kept as recorded; run the leaderboard
+-- consistency check afterwards to spot wallets whose on-chain totals were
+-- split across case variants.
+UPDATE submissions
+SET challenge_address = LOWER(challenge_address), wallet_address = LOWER(wallet_address)
+WHERE challenge_address <> LOWER(challenge_address) OR wallet_address <> LOWER(wallet_address);
+
+UPDATE reputation_events
+SET wallet_address = LOWER(wallet_address), token_address = LOWER(token_address)
+WHERE wallet_address <> LOWER(wallet_address) OR token_address <> LOWER(token_address);
+
+UPDATE solution_approvals
+SET challenge_address = LOWER(challenge_address), approver = LOWER(approver)
+WHERE challenge_address <> LOWER(challenge_address) OR approver <> LOWER(approver);
+
+UPDATE submission_fingerprints
+SET challenge_address = LOWER(challenge_address), wallet_address = LOWER(wallet_address)
+WHERE challenge_address <> LOWER(challenge_address) OR wallet_address <> LOWER(wallet_address);
+
+UPDATE similarity_flags
+SET challenge_address = LOWER(challenge_address),
+	wallet_address = LOWER(wallet_address),
+	matched_wallet = LOWER(matched_wallet)
+WHERE challenge_address <> LOWER(challenge_address)
+	OR wallet_address <> LOWER(wallet_address)
+	OR matched_wallet <> LOWER(matched_wallet);
+
+CREATE TEMPORARY TABLE merged_leaderboard AS
+SELECT
+	LOWER(wallet_address) AS wallet_address,
+	SUM(reputation_score) AS reputation_score,
+	SUM(total_usdc_won) AS total_usdc_won,
+	SUM(challenges_won) AS challenges_won,
+	SUM(challenges_verified) AS challenges_verified,
+	SUM(verifier_points) AS verifier_points,
+	SUM(verifier_fees_earned) AS verifier_fees_earned,
+	MAX(last_updated) AS last_updated
+FROM leaderboard
+GROUP BY LOWER(wallet_address)
+HAVING COUNT(*) > 1 OR BOOL_OR(wallet_address <> LOWER(wallet_address));
+
+DELETE FROM leaderboard WHERE LOWER(wallet_address) IN (SELECT wallet_address FROM merged_leaderboard);
+
+INSERT INTO leaderboard (wallet_address, reputation_score, total_usdc_won, challenges_won, challenges_verified, verifier_points, verifier_fees_earned, last_updated)
+SELECT wallet_address, reputation_score, total_usdc_won, challenges_won, challenges_verified, verifier_points, verifier_fees_earned, last_updated
+FROM merged_leaderboard;
+
+DROP TABLE merged_leaderboard;
+
+CREATE TEMPORARY TABLE merged_winnings AS
+SELECT
+	LOWER(wallet_address) AS wallet_address,
+	LOWER(token_address) AS token_address,
+	SUM(amount_won) AS amount_won,
+	SUM(wins) AS wins,
+	SUM(fees_earned) AS fees_earned,
+	SUM(verifications) AS verifications,
+	MAX(last_updated) AS last_updated
+FROM winnings_by_token
+GROUP BY LOWER(wallet_address), LOWER(token_address)
+HAVING COUNT(*) > 1 OR BOOL_OR(wallet_address <> LOWER(wallet_address) OR token_address <> LOWER(token_address));
+
+DELETE FROM winnings_by_token
+WHERE (LOWER(wallet_address), LOWER(token_address)) IN (SELECT wallet_address, token_address FROM merged_winnings);
+
+INSERT INTO winnings_by_token (wallet_address, token_address, amount_won, wins, fees_earned, verifications, last_updated)
+SELECT wallet_address, token_address, amount_won, wins, fees_earned, verifications, last_updated
+FROM merged_winnings;
+
+DROP TABLE merged_winnings;
@@ -1,8 +1,18 @@
 package database
 
 import (
-	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/cyrup/backend/internal/lean"
+	"github.com/cyrup/backend/internal/money"
+	"github.com/lib/pq"
+)
+
+// Leaderboard roles accepted by the role-split rankings.
+const (
+	RoleSolver   = "solver"
+	RoleVerifier = "verifier"
 )
 
 type Submission struct {
@@ -11,30 +21,347 @@ type Submission struct {
 	ChallengeAddress string         `db:"challenge_address" json:"challenge_address"`
 	WalletAddress    string         `db:"wallet_address" json:"wallet_address"`
 	SolutionCode     string         `db:"solution_code" json:"solution_code"`
-	SolutionHash     sql.NullString `db:"solution_hash" json:"solution_hash,omitempty"`
+	SolutionHash     *string        `db:"solution_hash" json:"solution_hash,omitempty"`
 	Status           string         `db:"status" json:"status"`
 	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
 }
 
+// Submission statuses. Submissions start pending and the scheduler marks
+// the ones that arrived after the deadline late; the others are set by an
+// admin through PUT /api/submissions/:uid/status.
+const (
+	SubmissionPending  = "pending"
+	SubmissionVerified = "verified"
+	SubmissionFailed   = "failed"
+	SubmissionApproved = "approved"
+	SubmissionRejected = "rejected"
+	SubmissionLate     = "late"
+)
+
 type LeaderboardEntry struct {
-	ID                 int       `db:"id" json:"id"`
-	WalletAddress      string    `db:"wallet_address" json:"wallet_address"`
-	ReputationScore    int       `db:"reputation_score" json:"reputation_score"`
-	TotalUSDCWon       float64   `db:"total_usdc_won" json:"total_usdc_won"`
-	ChallengesWon      int       `db:"challenges_won" json:"challenges_won"`
-	ChallengesVerified int       `db:"challenges_verified" json:"challenges_verified"`
-	LastUpdated        time.Time `db:"last_updated" json:"last_updated"`
+	ID                 int          `db:"id" json:"id"`
+	WalletAddress      string       `db:"wallet_address" json:"wallet_address"`
+	ReputationScore    int          `db:"reputation_score" json:"reputation_score"`
+	TotalUSDCWon       money.Amount `db:"total_usdc_won" json:"total_usdc_won"`
+	ChallengesWon      int          `db:"challenges_won" json:"challenges_won"`
+	ChallengesVerified int          `db:"challenges_verified" json:"challenges_verified"`
+	VerifierPoints     int          `db:"verifier_points" json:"verifier_points"`
+	VerifierFeesEarned money.Amount `db:"verifier_fees_earned" json:"verifier_fees_earned"`
+	LastUpdated        time.Time    `db:"last_updated" json:"last_updated"`
+}
+
+// SolverLeaderboardEntry ranks a wallet by the points it earned winning
+// challenges. WinRate is nil when none of its submissions are on record.
+type SolverLeaderboardEntry struct {
+	WalletAddress     string       `db:"wallet_address" json:"wallet_address"`
+	SolverPoints      int          `db:"solver_points" json:"solver_points"`
+	Wins              int          `db:"wins" json:"wins"`
+	TotalUSDCWon      money.Amount `db:"total_usdc_won" json:"total_usdc_won"`
+	ChallengesEntered int          `db:"challenges_entered" json:"challenges_entered"`
+	WinRate           *float64     `db:"win_rate" json:"win_rate"`
 }
 
+// VerifierLeaderboardEntry ranks a wallet by verifier points. Approval
+// latency is measured from submission to the verifier's SolutionApproved
+// event and is nil when no approvals have been recorded.
+type VerifierLeaderboardEntry struct {
+	WalletAddress             string       `db:"wallet_address" json:"wallet_address"`
+	VerifierPoints            int          `db:"verifier_points" json:"verifier_points"`
+	Verifications             int          `db:"verifications" json:"verifications"`
+	FeesEarned                money.Amount `db:"fees_earned" json:"fees_earned"`
+	AvgApprovalLatencySeconds *float64     `db:"avg_approval_latency_seconds" json:"avg_approval_latency_seconds"`
+}
+
+// SolutionApproval records a SolutionApproved event. The creator's approval
+// in awardSolution emits the same event, so IsVerifier tells them apart.
+type SolutionApproval struct {
+	ID               int        `db:"id" json:"id"`
+	ChallengeAddress string     `db:"challenge_address" json:"challenge_address"`
+	SubmissionID     int64      `db:"submission_id" json:"submission_id"`
+	SubmissionUID    *string    `db:"submission_uid" json:"submission_uid,omitempty"`
+	Approver         string     `db:"approver" json:"approver"`
+	IsVerifier       bool       `db:"is_verifier" json:"is_verifier"`
+	TransactionHash  string     `db:"transaction_hash" json:"transaction_hash"`
+	LogIndex         int        `db:"log_index" json:"log_index"`
+	BlockNumber      int64      `db:"block_number" json:"block_number,omitempty"`
+	BlockTimestamp   *time.Time `db:"block_timestamp" json:"block_timestamp,omitempty"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+}
+
+// ReputationEvent records a ReputationUpdated event. AmountRaw is the
+// uint256 the escrow passed to updateReputation and USDCAmount the same
+// amount scaled by the decimals of TokenAddress.
 type ReputationEvent struct {
-	ID              int       `db:"id" json:"id"`
-	WalletAddress   string    `db:"wallet_address" json:"wallet_address"`
-	EventType       string    `db:"event_type" json:"event_type"`
-	PointsAdded     int       `db:"points_added" json:"points_added"`
-	TotalPoints     int       `db:"total_points" json:"total_points"`
-	IsVerifier      bool      `db:"is_verifier" json:"is_verifier"`
-	TransactionHash string    `db:"transaction_hash" json:"transaction_hash,omitempty"`
-	BlockNumber     int64     `db:"block_number" json:"block_number,omitempty"`
-	CreatedAt       time.Time `db:"created_at" json:"created_at"`
-}
\ No newline at end of file
+	ID              int          `db:"id" json:"id"`
+	WalletAddress   string       `db:"wallet_address" json:"wallet_address"`
+	EventType       string       `db:"event_type" json:"event_type"`
+	PointsAdded     int          `db:"points_added" json:"points_added"`
+	TotalPoints     int          `db:"total_points" json:"total_points"`
+	IsVerifier      bool         `db:"is_verifier" json:"is_verifier"`
+	USDCAmount      money.Amount `db:"usdc_amount" json:"usdc_amount"`
+	AmountRaw       money.Amount `db:"amount_raw" json:"amount_raw"`
+	TokenAddress    string       `db:"token_address" json:"token_address"`
+	TransactionHash string       `db:"transaction_hash" json:"transaction_hash,omitempty"`
+	LogIndex        *int         `db:"log_index" json:"log_index,omitempty"`
+	BlockNumber     int64        `db:"block_number" json:"block_number,omitempty"`
+	BlockTimestamp  *time.Time   `db:"block_timestamp" json:"block_timestamp,omitempty"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+}
+
+// TokenWinnings is a wallet's totals in a single reward token. Amounts are
+// in whole tokens.
+type TokenWinnings struct {
+	WalletAddress string       `db:"wallet_address" json:"wallet_address"`
+	TokenAddress  string       `db:"token_address" json:"token_address"`
+	AmountWon     money.Amount `db:"amount_won" json:"amount_won"`
+	Wins          int          `db:"wins" json:"wins"`
+	FeesEarned    money.Amount `db:"fees_earned" json:"fees_earned"`
+	Verifications int          `db:"verifications" json:"verifications"`
+	LastUpdated   time.Time    `db:"last_updated" json:"last_updated"`
+}
+
+// WindowedLeaderboardEntry ranks a wallet by the points it earned inside a
+// time window rather than by its all-time reputation score.
+type WindowedLeaderboardEntry struct {
+	WalletAddress      string       `db:"wallet_address" json:"wallet_address"`
+	Points             int          `db:"points" json:"points"`
+	TotalUSDCWon       money.Amount `db:"total_usdc_won" json:"total_usdc_won"`
+	ChallengesWon      int          `db:"challenges_won" json:"challenges_won"`
+	ChallengesVerified int          `db:"challenges_verified" json:"challenges_verified"`
+}
+
+// LeaderboardDrift describes a single field where the stored leaderboard row
+// disagrees with the projection replayed from reputation_events.
+type LeaderboardDrift struct {
+	WalletAddress string      `json:"wallet_address"`
+	Field         string      `json:"field"`
+	Projected     interface{} `json:"projected"`
+	Stored        interface{} `json:"stored"`
+}
+
+// EventGap flags an event whose on-chain total does not match the running
+// sum of points_added, which usually means an event is missing from the log.
+type EventGap struct {
+	WalletAddress   string `json:"wallet_address"`
+	EventID         int    `json:"event_id"`
+	TransactionHash string `json:"transaction_hash,omitempty"`
+	BlockNumber     int64  `json:"block_number"`
+	ReportedTotal   int    `json:"reported_total"`
+	ReplayedTotal   int    `json:"replayed_total"`
+}
+
+type ConsistencyReport struct {
+	WalletsChecked int                `json:"wallets_checked"`
+	EventsReplayed int                `json:"events_replayed"`
+	Drift          []LeaderboardDrift `json:"drift"`
+	Gaps           []EventGap         `json:"gaps"`
+	Consistent     bool               `json:"consistent"`
+}
+
+// ThresholdUpdate records a ReputationSystem ThresholdUpdated event.
+type ThresholdUpdate struct {
+	ID              int        `db:"id" json:"id"`
+	OldThreshold    int        `db:"old_threshold" json:"old_threshold"`
+	NewThreshold    int        `db:"new_threshold" json:"new_threshold"`
+	TotalUsers      int        `db:"total_users" json:"total_users"`
+	TransactionHash string     `db:"transaction_hash" json:"transaction_hash"`
+	LogIndex        int        `db:"log_index" json:"log_index"`
+	BlockNumber     int64      `db:"block_number" json:"block_number"`
+	BlockTimestamp  *time.Time `db:"block_timestamp" json:"block_timestamp,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+}
+
+// Challenge statuses, matching ChallengeEscrow.Status.
+const (
+	ChallengeOpen      = "open"
+	ChallengeActive    = "active"
+	ChallengeCompleted = "completed"
+	ChallengeCancelled = "cancelled"
+)
+
+// Challenge is the indexed state of a ChallengeEscrow challenge, keyed by
+// the address submissions use as challenge_address.
+type Challenge struct {
+	Address      string        `db:"address" json:"address"`
+	ChallengeID  *int64        `db:"challenge_id" json:"challenge_id,omitempty"`
+	Creator      string        `db:"creator" json:"creator"`
+	Verifier     *string       `db:"verifier" json:"verifier,omitempty"`
+	Status       string        `db:"status" json:"status"`
+	TokenAddress *string       `db:"token_address" json:"token_address,omitempty"`
+	RewardRaw    *money.Amount `db:"reward_raw" json:"reward_raw,omitempty"`
+	Deadline     *time.Time    `db:"deadline" json:"deadline,omitempty"`
+	CreatedAt    time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// Challenge event names accepted by the ingestion endpoint.
+const (
+	EventChallengeCreated   = "ChallengeCreated"
+	EventVerifierSelected   = "VerifierSelected"
+	EventRewardsDistributed = "RewardsDistributed"
+	EventChallengeCancelled = "ChallengeCancelled"
+)
+
+// ChallengeEvent is a ChallengeEscrow event reported by a client. Only the
+// fields of the named event are set.
+type ChallengeEvent struct {
+	ChallengeAddress string
+	Event            string
+	ChallengeID      *int64
+	Creator          string
+	Verifier         string
+	TokenAddress     string
+	RewardRaw        *money.Amount
+	Deadline         *time.Time
+}
+
+// Commitment is a solver's salted hash of a solution, registered before the
+// code itself is revealed.
+type Commitment struct {
+	ID               int        `db:"id" json:"id"`
+	ChallengeAddress string     `db:"challenge_address" json:"challenge_address"`
+	WalletAddress    string     `db:"wallet_address" json:"wallet_address"`
+	Commitment       string     `db:"commitment" json:"commitment"`
+	CommittedAt      time.Time  `db:"committed_at" json:"committed_at"`
+	RevealedAt       *time.Time `db:"revealed_at" json:"revealed_at,omitempty"`
+	SubmissionUID    *string    `db:"submission_uid" json:"submission_uid,omitempty"`
+}
+
+// SubmissionFingerprint is the winnowed fingerprint of a submission's
+// normalized solution code.
+type SubmissionFingerprint struct {
+	SubmissionUID    string     `db:"submission_uid" json:"submission_uid"`
+	ChallengeAddress string     `db:"challenge_address" json:"challenge_address"`
+	WalletAddress    string     `db:"wallet_address" json:"wallet_address"`
+	Fingerprint      Int64Array `db:"fingerprint" json:"-"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+}
+
+// SimilarityFlag marks a submission whose code resembles an earlier
+// submission to the same challenge from another wallet.
+type SimilarityFlag struct {
+	ID               int       `db:"id" json:"id"`
+	ChallengeAddress string    `db:"challenge_address" json:"challenge_address"`
+	SubmissionUID    string    `db:"submission_uid" json:"submission_uid"`
+	WalletAddress    string    `db:"wallet_address" json:"wallet_address"`
+	MatchedUID       string    `db:"matched_uid" json:"matched_uid"`
+	MatchedWallet    string    `db:"matched_wallet" json:"matched_wallet"`
+	Score            float64   `db:"score" json:"score"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+}
+
+// SubmissionVerification is the automatic Lean check of a submission:
+// its verdict, the compiler diagnostics and the axiom audit.
+type SubmissionVerification struct {
+	SubmissionUID string           `db:"submission_uid" json:"submission_uid"`
+	Verdict       string           `db:"verdict" json:"verdict"`
+	Diagnostics   lean.Diagnostics `db:"diagnostics" json:"diagnostics"`
+	Axioms        lean.AxiomAudit  `db:"axioms" json:"axioms"`
+	Output        string           `db:"output" json:"output,omitempty"`
+	CheckedAt     *time.Time       `db:"checked_at" json:"checked_at,omitempty"`
+	UpdatedAt     time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// Recommendations a verifier can record in a review.
+const (
+	RecommendApprove      = "approve"
+	RecommendReject       = "reject"
+	RecommendNeedsChanges = "needs_changes"
+)
+
+// VerifierReview is a verifier's notes on a submission. The approval fields
+// link it to the verifier's SolutionApproved event once that is indexed.
+type VerifierReview struct {
+	ID                      int        `db:"id" json:"id"`
+	SubmissionUID           string     `db:"submission_uid" json:"submission_uid"`
+	ChallengeAddress        string     `db:"challenge_address" json:"challenge_address"`
+	VerifierAddress         string     `db:"verifier_address" json:"verifier_address"`
+	Recommendation          string     `db:"recommendation" json:"recommendation"`
+	Notes                   string     `db:"notes" json:"notes"`
+	ApprovalTransactionHash *string    `db:"approval_transaction_hash" json:"approval_transaction_hash,omitempty"`
+	ApprovalLogIndex        *int       `db:"approval_log_index" json:"approval_log_index,omitempty"`
+	ApprovedAt              *time.Time `db:"approved_at" json:"approved_at,omitempty"`
+	CreatedAt               time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// Scheduled job statuses.
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// ScheduledJob is a durable unit of time-based work for a challenge. There
+// is at most one job of each kind per challenge.
+type ScheduledJob struct {
+	ID               int        `db:"id" json:"id"`
+	Kind             string     `db:"kind" json:"kind"`
+	ChallengeAddress string     `db:"challenge_address" json:"challenge_address"`
+	RunAt            time.Time  `db:"run_at" json:"run_at"`
+	Status           string     `db:"status" json:"status"`
+	Attempts         int        `db:"attempts" json:"attempts"`
+	LastError        *string    `db:"last_error" json:"last_error,omitempty"`
+	LockedUntil      *time.Time `db:"locked_until" json:"locked_until,omitempty"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// Notification is a challenge lifecycle message. There is at most one of
+// each kind per challenge.
+type Notification struct {
+	ID               int       `db:"id" json:"id"`
+	ChallengeAddress string    `db:"challenge_address" json:"challenge_address"`
+	Kind             string    `db:"kind" json:"kind"`
+	Message          string    `db:"message" json:"message"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+}
+
+// Webhook is a subscription to outgoing events. Secret signs deliveries and
+// is never serialized.
+type Webhook struct {
+	ID        int            `db:"id" json:"id"`
+	Owner     string         `db:"owner" json:"owner"`
+	URL       string         `db:"url" json:"url"`
+	Secret    string         `db:"secret" json:"-"`
+	Events    pq.StringArray `db:"events" json:"events"`
+	Active    bool           `db:"active" json:"active"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+}
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending    = "pending"
+	DeliveryDelivering = "delivering"
+	DeliveryDelivered  = "delivered"
+	DeliveryFailed     = "failed"
+)
+
+// WebhookDelivery is one event queued for one webhook, along with the
+// outcome of its latest attempt.
+type WebhookDelivery struct {
+	ID             int             `db:"id" json:"id"`
+	WebhookID      int             `db:"webhook_id" json:"webhook_id"`
+	EventID        string          `db:"event_id" json:"event_id"`
+	EventType      string          `db:"event_type" json:"event_type"`
+	Payload        json.RawMessage `db:"payload" json:"payload"`
+	Status         string          `db:"status" json:"status"`
+	Attempts       int             `db:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time       `db:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil    *time.Time      `db:"locked_until" json:"locked_until,omitempty"`
+	ResponseStatus *int            `db:"response_status" json:"response_status,omitempty"`
+	LastError      *string         `db:"last_error" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `db:"delivered_at" json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// RateBucket is the state of one rate limit token bucket. A zero UpdatedAt
+// marks a bucket that has not been used yet.
+type RateBucket struct {
+	Key       string    `db:"key" json:"key"`
+	Tokens    float64   `db:"tokens" json:"tokens"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,1374 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// replayOrder sorts reputation events into the order they happened on-chain.
+const replayOrder = `ORDER BY block_number, log_index NULLS FIRST, id`
+
+// PostgresStore implements the repositories on top of Postgres.
+type PostgresStore struct {
+	db     *sqlx.DB
+	config QueryConfig
+	// usdc is the USDC token address; only its amounts count towards the
+	// leaderboard's USDC columns.
+	usdc string
+}
+
+func NewPostgresStore(db *sqlx.DB, config QueryConfig, usdc string) *PostgresStore {
+	return &PostgresStore{db: db, config: config, usdc: usdc}
+}
+
+// Repositories returns the store's repositories for injection into handlers.
+func (s *PostgresStore) Repositories() *Repositories {
+	return &Repositories{
+		Submissions: s,
+		Leaderboard: s,
+		Reputation:  s,
+		Challenges:  s,
+		Commitments: s,
+		Similarity:  s,
+		Reviews:     s,
+		Jobs:        s,
+		Webhooks:    s,
+		RateLimits:  s,
+	}
+}
+
+func (s *PostgresStore) CreateSubmission(ctx context.Context, submission *Submission) error {
+	ctx, done := s.timed(ctx, "CreateSubmission")
+	defer done()
+
+	query := `
+		INSERT INTO submissions (uid, challenge_address, wallet_address, solution_code, solution_hash, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	return s.db.QueryRowContext(
+		ctx,
+		query,
+		submission.UID,
+		submission.ChallengeAddress,
+		submission.WalletAddress,
+		submission.SolutionCode,
+		submission.SolutionHash,
+		submission.Status,
+	).Scan(&submission.ID, &submission.CreatedAt, &submission.UpdatedAt)
+}
+
+func (s *PostgresStore) GetSubmissionByUID(ctx context.Context, uid string) (*Submission, error) {
+	ctx, done := s.timed(ctx, "GetSubmissionByUID")
+	defer done()
+
+	var submission Submission
+	query := `SELECT * FROM submissions WHERE uid = $1`
+	err := s.db.GetContext(ctx, &submission, query, uid)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &submission, err
+}
+
+func (s *PostgresStore) UpdateSubmissionStatus(ctx context.Context, uid string, status string, solutionHash string) error {
+	ctx, done := s.timed(ctx, "UpdateSubmissionStatus")
+	defer done()
+
+	query := `
+		UPDATE submissions
+		SET status = $2, solution_hash = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE uid = $1
+	`
+	_, err := s.db.ExecContext(ctx, query, uid, status, solutionHash)
+	return err
+}
+
+// submissionSummaryColumns is every submissions column except the
+// solution_code blob.
+const submissionSummaryColumns = `id, uid, challenge_address, wallet_address, solution_hash, status, created_at, updated_at`
+
+func (s *PostgresStore) ListSubmissions(ctx context.Context, query SubmissionQuery) ([]Submission, error) {
+	ctx, done := s.timed(ctx, "ListSubmissions")
+	defer done()
+
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if query.WalletAddress != "" {
+		conditions = append(conditions, "wallet_address = "+arg(query.WalletAddress))
+	}
+	if query.ChallengeAddress != "" {
+		conditions = append(conditions, "challenge_address = "+arg(query.ChallengeAddress))
+	}
+	if len(query.Statuses) > 0 {
+		conditions = append(conditions, "status = ANY("+arg(pq.Array(query.Statuses))+")")
+	}
+	if query.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= "+arg(*query.CreatedAfter))
+	}
+	if query.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < "+arg(*query.CreatedBefore))
+	}
+
+	direction, comparison := "DESC", "<"
+	if query.Sort == SortOldest {
+		direction, comparison = "ASC", ">"
+	}
+	if query.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) %s (%s, %s)", comparison, arg(query.After.CreatedAt), arg(query.After.ID)))
+	}
+
+	columns := submissionSummaryColumns
+	if query.IncludeCode {
+		columns = "*"
+	}
+
+	statement := "SELECT " + columns + " FROM submissions"
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	statement += fmt.Sprintf(" ORDER BY created_at %[1]s, id %[1]s LIMIT %s", direction, arg(query.Limit))
+
+	submissions := []Submission{}
+	err := s.db.SelectContext(ctx, &submissions, statement, args...)
+	return submissions, err
+}
+
+func (s *PostgresStore) MarkLateSubmissions(ctx context.Context, challengeAddress string, deadline time.Time) ([]Submission, error) {
+	ctx, done := s.timed(ctx, "MarkLateSubmissions")
+	defer done()
+
+	query := `
+		UPDATE submissions
+		SET status = 'late', updated_at = CURRENT_TIMESTAMP
+		WHERE challenge_address = $1 AND created_at > $2 AND status = 'pending'
+			AND uid NOT IN (SELECT submission_uid FROM commitments WHERE submission_uid IS NOT NULL)
+		RETURNING id, uid, challenge_address, wallet_address, solution_hash, status, created_at, updated_at
+	`
+	late := []Submission{}
+	err := s.db.SelectContext(ctx, &late, query, challengeAddress, deadline)
+	return late, err
+}
+
+func (s *PostgresStore) GetLeaderboard(ctx context.Context, limit int, offset int) ([]LeaderboardEntry, error) {
+	ctx, done := s.timed(ctx, "GetLeaderboard")
+	defer done()
+
+	var entries []LeaderboardEntry
+	query := `
+		SELECT * FROM leaderboard
+		ORDER BY reputation_score DESC, total_usdc_won DESC
+		LIMIT $1 OFFSET $2
+	`
+	err := s.db.SelectContext(ctx, &entries, query, limit, offset)
+	return entries, err
+}
+
+// GetWindowedLeaderboard places events in time by their block timestamp
+// when it was reported, and by ingestion time otherwise.
+func (s *PostgresStore) GetWindowedLeaderboard(ctx context.Context, start, end time.Time, role string, limit int, offset int) ([]WindowedLeaderboardEntry, error) {
+	ctx, done := s.timed(ctx, "GetWindowedLeaderboard")
+	defer done()
+
+	var entries []WindowedLeaderboardEntry
+	query := `
+		SELECT
+			wallet_address,
+			SUM(points_added) AS points,
+			COALESCE(SUM(usdc_amount) FILTER (WHERE NOT is_verifier AND LOWER(token_address) = LOWER($6)), 0) AS total_usdc_won,
+			COUNT(*) FILTER (WHERE NOT is_verifier) AS challenges_won,
+			COUNT(*) FILTER (WHERE is_verifier) AS challenges_verified
+		FROM reputation_events
+		WHERE COALESCE(block_timestamp, created_at) >= $1
+		AND COALESCE(block_timestamp, created_at) < $2
+		AND ($3 = '' OR is_verifier = ($3 = 'verifier'))
+		GROUP BY wallet_address
+		ORDER BY points DESC, total_usdc_won DESC, wallet_address
+		LIMIT $4 OFFSET $5
+	`
+	err := s.db.SelectContext(ctx, &entries, query, start, end, role, limit, offset, s.usdc)
+	return entries, err
+}
+
+func (s *PostgresStore) GetSolverLeaderboard(ctx context.Context, limit int, offset int) ([]SolverLeaderboardEntry, error) {
+	ctx, done := s.timed(ctx, "GetSolverLeaderboard")
+	defer done()
+
+	var entries []SolverLeaderboardEntry
+	query := `
+		SELECT
+			l.wallet_address,
+			l.reputation_score - l.verifier_points AS solver_points,
+			l.challenges_won AS wins,
+			l.total_usdc_won,
+			COALESCE(s.challenges_entered, 0) AS challenges_entered,
+			CASE WHEN s.challenges_entered > 0
+				THEN LEAST(l.challenges_won::float / s.challenges_entered, 1)
+			END AS win_rate
+		FROM leaderboard l
+		LEFT JOIN (
+			SELECT wallet_address, COUNT(DISTINCT challenge_address) AS challenges_entered
+			FROM submissions
+			GROUP BY wallet_address
+		) s ON s.wallet_address = l.wallet_address
+		WHERE l.challenges_won > 0
+		ORDER BY solver_points DESC, wins DESC, l.total_usdc_won DESC, l.wallet_address
+		LIMIT $1 OFFSET $2
+	`
+	err := s.db.SelectContext(ctx, &entries, query, limit, offset)
+	return entries, err
+}
+
+func (s *PostgresStore) GetVerifierLeaderboard(ctx context.Context, limit int, offset int) ([]VerifierLeaderboardEntry, error) {
+	ctx, done := s.timed(ctx, "GetVerifierLeaderboard")
+	defer done()
+
+	var entries []VerifierLeaderboardEntry
+	query := `
+		SELECT
+			l.wallet_address,
+			l.verifier_points,
+			l.challenges_verified AS verifications,
+			l.verifier_fees_earned AS fees_earned,
+			a.avg_latency AS avg_approval_latency_seconds
+		FROM leaderboard l
+		LEFT JOIN (
+			SELECT
+				sa.approver,
+				AVG(EXTRACT(EPOCH FROM (COALESCE(sa.block_timestamp, sa.created_at) - s.created_at)))::float AS avg_latency
+			FROM solution_approvals sa
+			JOIN submissions s ON s.uid = sa.submission_uid
+			WHERE sa.is_verifier
+			GROUP BY sa.approver
+		) a ON a.approver = l.wallet_address
+		WHERE l.challenges_verified > 0
+		ORDER BY l.verifier_points DESC, verifications DESC, l.wallet_address
+		LIMIT $1 OFFSET $2
+	`
+	err := s.db.SelectContext(ctx, &entries, query, limit, offset)
+	return entries, err
+}
+
+func (s *PostgresStore) GetUserStats(ctx context.Context, walletAddress string) (*LeaderboardEntry, error) {
+	ctx, done := s.timed(ctx, "GetUserStats")
+	defer done()
+
+	var entry LeaderboardEntry
+	query := `SELECT * FROM leaderboard WHERE wallet_address = $1`
+	err := s.db.GetContext(ctx, &entry, query, walletAddress)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &entry, err
+}
+
+func (s *PostgresStore) GetLeaderboardPosition(ctx context.Context, walletAddress string) (int, error) {
+	ctx, done := s.timed(ctx, "GetLeaderboardPosition")
+	defer done()
+
+	var position int
+	query := `
+		SELECT COUNT(*) + 1 as position
+		FROM leaderboard
+		WHERE reputation_score > (
+			SELECT reputation_score
+			FROM leaderboard
+			WHERE wallet_address = $1
+		)
+	`
+	err := s.db.GetContext(ctx, &position, query, walletAddress)
+	return position, err
+}
+
+func (s *PostgresStore) GetTokenWinnings(ctx context.Context, walletAddress string) ([]TokenWinnings, error) {
+	ctx, done := s.timed(ctx, "GetTokenWinnings")
+	defer done()
+
+	var winnings []TokenWinnings
+	query := `
+		SELECT * FROM winnings_by_token
+		WHERE wallet_address = $1
+		ORDER BY token_address
+	`
+	err := s.db.SelectContext(ctx, &winnings, query, walletAddress)
+	return winnings, err
+}
+
+func (s *PostgresStore) GetTopPoints(ctx context.Context, limit int) ([]int, error) {
+	ctx, done := s.timed(ctx, "GetTopPoints")
+	defer done()
+
+	var points []int
+	query := `
+		SELECT reputation_score FROM leaderboard
+		ORDER BY reputation_score DESC
+		LIMIT $1
+	`
+	err := s.db.SelectContext(ctx, &points, query, limit)
+	return points, err
+}
+
+func (s *PostgresStore) CountUsers(ctx context.Context, points int) (total int, below int, err error) {
+	ctx, done := s.timed(ctx, "CountUsers")
+	defer done()
+
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE reputation_score < $1)
+		FROM leaderboard
+	`
+	err = s.db.QueryRowContext(ctx, query, points).Scan(&total, &below)
+	return total, below, err
+}
+
+func (s *PostgresStore) RebuildLeaderboard(ctx context.Context) (int, error) {
+	ctx, done := s.timedMaintenance(ctx, "RebuildLeaderboard")
+	defer done()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE leaderboard IN EXCLUSIVE MODE`); err != nil {
+		return 0, err
+	}
+
+	var events []ReputationEvent
+	if err := tx.SelectContext(ctx, &events, `SELECT * FROM reputation_events `+replayOrder); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM leaderboard`); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM winnings_by_token`); err != nil {
+		return 0, err
+	}
+
+	for _, entry := range ReplayReputationEvents(events, s.usdc) {
+		if err := writeLeaderboardEntry(ctx, tx, &entry); err != nil {
+			return 0, err
+		}
+	}
+	for _, winnings := range ReplayTokenWinnings(events) {
+		if err := writeTokenWinnings(ctx, tx, &winnings); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), tx.Commit()
+}
+
+func (s *PostgresStore) CheckLeaderboardConsistency(ctx context.Context) (*ConsistencyReport, error) {
+	ctx, done := s.timedMaintenance(ctx, "CheckLeaderboardConsistency")
+	defer done()
+
+	var events []ReputationEvent
+	if err := s.db.SelectContext(ctx, &events, `SELECT * FROM reputation_events `+replayOrder); err != nil {
+		return nil, err
+	}
+
+	var stored []LeaderboardEntry
+	if err := s.db.SelectContext(ctx, &stored, `SELECT * FROM leaderboard`); err != nil {
+		return nil, err
+	}
+
+	var storedWinnings []TokenWinnings
+	if err := s.db.SelectContext(ctx, &storedWinnings, `SELECT * FROM winnings_by_token`); err != nil {
+		return nil, err
+	}
+
+	return BuildConsistencyReport(events, s.usdc, stored, storedWinnings), nil
+}
+
+func (s *PostgresStore) RecordReputationEvent(ctx context.Context, event *ReputationEvent) (bool, error) {
+	ctx, done := s.timed(ctx, "RecordReputationEvent")
+	defer done()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO reputation_events (wallet_address, event_type, points_added, total_points, is_verifier, usdc_amount, amount_raw, token_address, transaction_hash, log_index, block_number, block_timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (transaction_hash, log_index) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		event.WalletAddress,
+		event.EventType,
+		event.PointsAdded,
+		event.TotalPoints,
+		event.IsVerifier,
+		event.USDCAmount,
+		event.AmountRaw,
+		event.TokenAddress,
+		event.TransactionHash,
+		event.LogIndex,
+		event.BlockNumber,
+		event.BlockTimestamp,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.refreshLeaderboardEntry(ctx, tx, event.WalletAddress); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// refreshLeaderboardEntry recomputes a single wallet's leaderboard and
+// winnings rows from its events. A transaction-scoped advisory lock on the wallet serialises
+// concurrent refreshes so the last writer always sees every committed event.
+func (s *PostgresStore) refreshLeaderboardEntry(ctx context.Context, tx *sqlx.Tx, walletAddress string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, walletAddress); err != nil {
+		return err
+	}
+
+	var events []ReputationEvent
+	query := `SELECT * FROM reputation_events WHERE wallet_address = $1 ` + replayOrder
+	if err := tx.SelectContext(ctx, &events, query, walletAddress); err != nil {
+		return err
+	}
+
+	for _, entry := range ReplayReputationEvents(events, s.usdc) {
+		if err := writeLeaderboardEntry(ctx, tx, &entry); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM winnings_by_token WHERE wallet_address = $1`, walletAddress); err != nil {
+		return err
+	}
+	for _, winnings := range ReplayTokenWinnings(events) {
+		if err := writeTokenWinnings(ctx, tx, &winnings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeTokenWinnings(ctx context.Context, tx *sqlx.Tx, winnings *TokenWinnings) error {
+	query := `
+		INSERT INTO winnings_by_token (wallet_address, token_address, amount_won, wins, fees_earned, verifications)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		winnings.WalletAddress,
+		winnings.TokenAddress,
+		winnings.AmountWon,
+		winnings.Wins,
+		winnings.FeesEarned,
+		winnings.Verifications,
+	)
+	return err
+}
+
+func writeLeaderboardEntry(ctx context.Context, tx *sqlx.Tx, entry *LeaderboardEntry) error {
+	query := `
+		INSERT INTO leaderboard (wallet_address, reputation_score, total_usdc_won, challenges_won, challenges_verified, verifier_points, verifier_fees_earned)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (wallet_address)
+		DO UPDATE SET
+			reputation_score = EXCLUDED.reputation_score,
+			total_usdc_won = EXCLUDED.total_usdc_won,
+			challenges_won = EXCLUDED.challenges_won,
+			challenges_verified = EXCLUDED.challenges_verified,
+			verifier_points = EXCLUDED.verifier_points,
+			verifier_fees_earned = EXCLUDED.verifier_fees_earned,
+			last_updated = CURRENT_TIMESTAMP
+	`
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		entry.WalletAddress,
+		entry.ReputationScore,
+		entry.TotalUSDCWon,
+		entry.ChallengesWon,
+		entry.ChallengesVerified,
+		entry.VerifierPoints,
+		entry.VerifierFeesEarned,
+	)
+	return err
+}
+
+func (s *PostgresStore) GetPointsBefore(ctx context.Context, walletAddress string, blockNumber int64, logIndex int) (int, error) {
+	ctx, done := s.timed(ctx, "GetPointsBefore")
+	defer done()
+
+	var points int
+	query := `
+		SELECT COALESCE(SUM(points_added), 0)
+		FROM reputation_events
+		WHERE wallet_address = $1
+		AND (block_number < $2 OR (block_number = $2 AND COALESCE(log_index, -1) < $3))
+	`
+	err := s.db.GetContext(ctx, &points, query, walletAddress, blockNumber, logIndex)
+	return points, err
+}
+
+func (s *PostgresStore) GetRecentReputationEvents(ctx context.Context, limit int) ([]ReputationEvent, error) {
+	ctx, done := s.timed(ctx, "GetRecentReputationEvents")
+	defer done()
+
+	var events []ReputationEvent
+	query := `
+		SELECT * FROM reputation_events
+		ORDER BY created_at DESC
+		LIMIT $1
+	`
+	err := s.db.SelectContext(ctx, &events, query, limit)
+	return events, err
+}
+
+func (s *PostgresStore) CreateSolutionApproval(ctx context.Context, approval *SolutionApproval) (bool, error) {
+	ctx, done := s.timed(ctx, "CreateSolutionApproval")
+	defer done()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO solution_approvals (challenge_address, submission_id, submission_uid, approver, is_verifier, transaction_hash, log_index, block_number, block_timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (transaction_hash, log_index) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		approval.ChallengeAddress,
+		approval.SubmissionID,
+		approval.SubmissionUID,
+		approval.Approver,
+		approval.IsVerifier,
+		approval.TransactionHash,
+		approval.LogIndex,
+		approval.BlockNumber,
+		approval.BlockTimestamp,
+	).Scan(&approval.ID, &approval.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if approval.SubmissionUID != nil {
+		approvedAt := approval.CreatedAt
+		if approval.BlockTimestamp != nil {
+			approvedAt = *approval.BlockTimestamp
+		}
+
+		query := `
+			UPDATE verifier_reviews
+			SET approval_transaction_hash = $3, approval_log_index = $4, approved_at = $5
+			WHERE submission_uid = $1 AND verifier_address = LOWER($2)
+		`
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			*approval.SubmissionUID,
+			approval.Approver,
+			approval.TransactionHash,
+			approval.LogIndex,
+			approvedAt,
+		)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+func (s *PostgresStore) CreateThresholdUpdate(ctx context.Context, update *ThresholdUpdate) (bool, error) {
+	ctx, done := s.timed(ctx, "CreateThresholdUpdate")
+	defer done()
+
+	query := `
+		INSERT INTO threshold_updates (old_threshold, new_threshold, total_users, transaction_hash, log_index, block_number, block_timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (transaction_hash, log_index) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := s.db.QueryRowContext(
+		ctx,
+		query,
+		update.OldThreshold,
+		update.NewThreshold,
+		update.TotalUsers,
+		update.TransactionHash,
+		update.LogIndex,
+		update.BlockNumber,
+		update.BlockTimestamp,
+	).Scan(&update.ID, &update.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *PostgresStore) GetThresholdHistory(ctx context.Context, limit int) ([]ThresholdUpdate, error) {
+	ctx, done := s.timed(ctx, "GetThresholdHistory")
+	defer done()
+
+	var updates []ThresholdUpdate
+	query := `
+		SELECT * FROM threshold_updates
+		ORDER BY block_number DESC, log_index DESC
+		LIMIT $1
+	`
+	err := s.db.SelectContext(ctx, &updates, query, limit)
+	return updates, err
+}
+
+func (s *PostgresStore) GetChallenge(ctx context.Context, address string) (*Challenge, error) {
+	ctx, done := s.timed(ctx, "GetChallenge")
+	defer done()
+
+	var challenge Challenge
+	err := s.db.GetContext(ctx, &challenge, `SELECT * FROM challenges WHERE address = $1`, address)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &challenge, err
+}
+
+func (s *PostgresStore) RecordChallengeEvent(ctx context.Context, event ChallengeEvent) (*Challenge, bool, error) {
+	ctx, done := s.timed(ctx, "RecordChallengeEvent")
+	defer done()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// Serialise events for the same challenge, including the first one,
+	// when there is no row to lock yet.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, event.ChallengeAddress); err != nil {
+		return nil, false, err
+	}
+
+	var current *Challenge
+	var existing Challenge
+	err = tx.GetContext(ctx, &existing, `SELECT * FROM challenges WHERE address = $1`, event.ChallengeAddress)
+	switch {
+	case err == nil:
+		current = &existing
+	case err != sql.ErrNoRows:
+		return nil, false, err
+	}
+
+	next, changed := ApplyChallengeEvent(current, event)
+	if !changed {
+		return current, false, nil
+	}
+
+	query := `
+		INSERT INTO challenges (address, challenge_id, creator, verifier, status, token_address, reward_raw, deadline)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (address)
+		DO UPDATE SET
+			challenge_id = EXCLUDED.challenge_id,
+			creator = EXCLUDED.creator,
+			verifier = EXCLUDED.verifier,
+			status = EXCLUDED.status,
+			token_address = EXCLUDED.token_address,
+			reward_raw = EXCLUDED.reward_raw,
+			deadline = EXCLUDED.deadline,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING created_at, updated_at
+	`
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		next.Address,
+		next.ChallengeID,
+		next.Creator,
+		next.Verifier,
+		next.Status,
+		next.TokenAddress,
+		next.RewardRaw,
+		next.Deadline,
+	).Scan(&next.CreatedAt, &next.UpdatedAt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &next, true, tx.Commit()
+}
+
+func (s *PostgresStore) CreateCommitment(ctx context.Context, commitment *Commitment) (bool, error) {
+	ctx, done := s.timed(ctx, "CreateCommitment")
+	defer done()
+
+	query := `
+		INSERT INTO commitments (challenge_address, wallet_address, commitment)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (commitment) DO NOTHING
+		RETURNING id, committed_at
+	`
+	err := s.db.QueryRowContext(
+		ctx,
+		query,
+		commitment.ChallengeAddress,
+		commitment.WalletAddress,
+		commitment.Commitment,
+	).Scan(&commitment.ID, &commitment.CommittedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *PostgresStore) GetCommitment(ctx context.Context, hash string) (*Commitment, error) {
+	ctx, done := s.timed(ctx, "GetCommitment")
+	defer done()
+
+	var commitment Commitment
+	err := s.db.GetContext(ctx, &commitment, `SELECT * FROM commitments WHERE commitment = $1`, hash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &commitment, err
+}
+
+func (s *PostgresStore) RevealCommitment(ctx context.Context, hash string, submission *Submission) (bool, error) {
+	ctx, done := s.timed(ctx, "RevealCommitment")
+	defer done()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE commitments
+		SET revealed_at = CURRENT_TIMESTAMP
+		WHERE commitment = $1 AND revealed_at IS NULL
+	`, hash)
+	if err != nil {
+		return false, err
+	}
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO submissions (uid, challenge_address, wallet_address, solution_code, solution_hash, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		submission.UID,
+		submission.ChallengeAddress,
+		submission.WalletAddress,
+		submission.SolutionCode,
+		submission.SolutionHash,
+		submission.Status,
+	).Scan(&submission.ID, &submission.CreatedAt, &submission.UpdatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return false, ErrSubmissionExists
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE commitments SET submission_uid = $2 WHERE commitment = $1`, hash, submission.UID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (s *PostgresStore) ListCommitments(ctx context.Context, challengeAddress string) ([]Commitment, error) {
+	ctx, done := s.timed(ctx, "ListCommitments")
+	defer done()
+
+	commitments := []Commitment{}
+	query := `
+		SELECT * FROM commitments
+		WHERE challenge_address = $1
+		ORDER BY committed_at, id
+	`
+	err := s.db.SelectContext(ctx, &commitments, query, challengeAddress)
+	return commitments, err
+}
+
+func (s *PostgresStore) SaveFingerprint(ctx context.Context, fingerprint *SubmissionFingerprint, flags []SimilarityFlag) error {
+	ctx, done := s.timed(ctx, "SaveFingerprint")
+	defer done()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO submission_fingerprints (submission_uid, challenge_address, wallet_address, fingerprint)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (submission_uid) DO UPDATE SET fingerprint = EXCLUDED.fingerprint
+		RETURNING created_at
+	`
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		fingerprint.SubmissionUID,
+		fingerprint.ChallengeAddress,
+		fingerprint.WalletAddress,
+		fingerprint.Fingerprint,
+	).Scan(&fingerprint.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM similarity_flags WHERE submission_uid = $1`, fingerprint.SubmissionUID); err != nil {
+		return err
+	}
+
+	for i := range flags {
+		flag := &flags[i]
+		query := `
+			INSERT INTO similarity_flags (challenge_address, submission_uid, wallet_address, matched_uid, matched_wallet, score)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at
+		`
+		err := tx.QueryRowContext(
+			ctx,
+			query,
+			flag.ChallengeAddress,
+			flag.SubmissionUID,
+			flag.WalletAddress,
+			flag.MatchedUID,
+			flag.MatchedWallet,
+			flag.Score,
+		).Scan(&flag.ID, &flag.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *PostgresStore) ListFingerprints(ctx context.Context, challengeAddress string) ([]SubmissionFingerprint, error) {
+	ctx, done := s.timed(ctx, "ListFingerprints")
+	defer done()
+
+	fingerprints := []SubmissionFingerprint{}
+	query := `
+		SELECT f.* FROM submission_fingerprints f
+		JOIN submissions s ON s.uid = f.submission_uid
+		WHERE f.challenge_address = $1
+		ORDER BY s.created_at, s.id
+	`
+	err := s.db.SelectContext(ctx, &fingerprints, query, challengeAddress)
+	return fingerprints, err
+}
+
+func (s *PostgresStore) ListSimilarityFlags(ctx context.Context, challengeAddress string) ([]SimilarityFlag, error) {
+	ctx, done := s.timed(ctx, "ListSimilarityFlags")
+	defer done()
+
+	flags := []SimilarityFlag{}
+	query := `
+		SELECT * FROM similarity_flags
+		WHERE challenge_address = $1
+		ORDER BY score DESC, id
+	`
+	err := s.db.SelectContext(ctx, &flags, query, challengeAddress)
+	return flags, err
+}
+
+func (s *PostgresStore) ListChallengesByVerifier(ctx context.Context, verifier string, status string) ([]Challenge, error) {
+	ctx, done := s.timed(ctx, "ListChallengesByVerifier")
+	defer done()
+
+	challenges := []Challenge{}
+	query := `
+		SELECT * FROM challenges
+		WHERE verifier = LOWER($1) AND status = $2
+		ORDER BY created_at, address
+	`
+	err := s.db.SelectContext(ctx, &challenges, query, verifier, status)
+	return challenges, err
+}
+
+func (s *PostgresStore) SaveVerification(ctx context.Context, verification *SubmissionVerification) error {
+	ctx, done := s.timed(ctx, "SaveVerification")
+	defer done()
+
+	query := `
+		INSERT INTO submission_verifications (submission_uid, verdict, diagnostics, axioms, output, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (submission_uid) DO UPDATE SET
+			verdict = EXCLUDED.verdict,
+			diagnostics = EXCLUDED.diagnostics,
+			axioms = EXCLUDED.axioms,
+			output = EXCLUDED.output,
+			checked_at = EXCLUDED.checked_at,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+	return s.db.QueryRowContext(
+		ctx,
+		query,
+		verification.SubmissionUID,
+		verification.Verdict,
+		verification.Diagnostics,
+		verification.Axioms,
+		verification.Output,
+		verification.CheckedAt,
+	).Scan(&verification.UpdatedAt)
+}
+
+func (s *PostgresStore) ListVerifications(ctx context.Context, challengeAddress string) ([]SubmissionVerification, error) {
+	ctx, done := s.timed(ctx, "ListVerifications")
+	defer done()
+
+	verifications := []SubmissionVerification{}
+	query := `
+		SELECT v.* FROM submission_verifications v
+		JOIN submissions s ON s.uid = v.submission_uid
+		WHERE s.challenge_address = $1
+	`
+	err := s.db.SelectContext(ctx, &verifications, query, challengeAddress)
+	return verifications, err
+}
+
+func (s *PostgresStore) SaveReview(ctx context.Context, review *VerifierReview) error {
+	ctx, done := s.timed(ctx, "SaveReview")
+	defer done()
+
+	query := `
+		INSERT INTO verifier_reviews (submission_uid, challenge_address, verifier_address, recommendation, notes)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (submission_uid, verifier_address) DO UPDATE SET
+			recommendation = EXCLUDED.recommendation,
+			notes = EXCLUDED.notes,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING *
+	`
+	return s.db.QueryRowxContext(
+		ctx,
+		query,
+		review.SubmissionUID,
+		review.ChallengeAddress,
+		review.VerifierAddress,
+		review.Recommendation,
+		review.Notes,
+	).StructScan(review)
+}
+
+func (s *PostgresStore) ListReviews(ctx context.Context, verifier string) ([]VerifierReview, error) {
+	ctx, done := s.timed(ctx, "ListReviews")
+	defer done()
+
+	reviews := []VerifierReview{}
+	query := `
+		SELECT * FROM verifier_reviews
+		WHERE verifier_address = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	err := s.db.SelectContext(ctx, &reviews, query, verifier)
+	return reviews, err
+}
+
+func (s *PostgresStore) ListChallengesWithDeadlines(ctx context.Context, after time.Time) ([]Challenge, error) {
+	ctx, done := s.timed(ctx, "ListChallengesWithDeadlines")
+	defer done()
+
+	challenges := []Challenge{}
+	query := `
+		SELECT * FROM challenges
+		WHERE status IN ('open', 'active') AND deadline >= $1
+		ORDER BY deadline, address
+	`
+	err := s.db.SelectContext(ctx, &challenges, query, after)
+	return challenges, err
+}
+
+func (s *PostgresStore) ListChallenges(ctx context.Context, status string, limit, offset int) ([]Challenge, error) {
+	ctx, done := s.timed(ctx, "ListChallenges")
+	defer done()
+
+	challenges := []Challenge{}
+	query := `
+		SELECT * FROM challenges
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC, address
+		LIMIT $2 OFFSET $3
+	`
+	err := s.db.SelectContext(ctx, &challenges, query, status, limit, offset)
+	return challenges, err
+}
+
+func (s *PostgresStore) ScheduleJob(ctx context.Context, job *ScheduledJob) (bool, error) {
+	ctx, done := s.timed(ctx, "ScheduleJob")
+	defer done()
+
+	query := `
+		INSERT INTO scheduled_jobs (kind, challenge_address, run_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (kind, challenge_address) DO UPDATE
+		SET run_at = EXCLUDED.run_at, updated_at = CURRENT_TIMESTAMP
+		WHERE scheduled_jobs.status = 'pending'
+		RETURNING id, status, created_at, updated_at
+	`
+	err := s.db.QueryRowContext(ctx, query, job.Kind, job.ChallengeAddress, job.RunAt).
+		Scan(&job.ID, &job.Status, &job.CreatedAt, &job.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *PostgresStore) ClaimDueJobs(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]ScheduledJob, error) {
+	ctx, done := s.timed(ctx, "ClaimDueJobs")
+	defer done()
+
+	// SKIP LOCKED lets several replicas poll without claiming the same job.
+	jobs := []ScheduledJob{}
+	query := `
+		UPDATE scheduled_jobs
+		SET status = 'running', locked_until = $2, attempts = attempts + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM scheduled_jobs
+			WHERE (status = 'pending' AND run_at <= $1) OR (status = 'running' AND locked_until < $1)
+			ORDER BY run_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+	err := s.db.SelectContext(ctx, &jobs, query, now, now.Add(lease), limit)
+	return jobs, err
+}
+
+func (s *PostgresStore) CompleteJob(ctx context.Context, id int) error {
+	ctx, done := s.timed(ctx, "CompleteJob")
+	defer done()
+
+	query := `
+		UPDATE scheduled_jobs
+		SET status = 'done', locked_until = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	_, err := s.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (s *PostgresStore) RetryJob(ctx context.Context, id int, runAt *time.Time, lastError string) error {
+	ctx, done := s.timed(ctx, "RetryJob")
+	defer done()
+
+	query := `
+		UPDATE scheduled_jobs
+		SET status = CASE WHEN $2::timestamp IS NULL THEN 'failed' ELSE 'pending' END,
+			run_at = COALESCE($2::timestamp, run_at),
+			last_error = $3,
+			locked_until = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	_, err := s.db.ExecContext(ctx, query, id, runAt, lastError)
+	return err
+}
+
+func (s *PostgresStore) ListJobs(ctx context.Context, challengeAddress string) ([]ScheduledJob, error) {
+	ctx, done := s.timed(ctx, "ListJobs")
+	defer done()
+
+	jobs := []ScheduledJob{}
+	query := `
+		SELECT * FROM scheduled_jobs
+		WHERE challenge_address = $1
+		ORDER BY run_at, id
+	`
+	err := s.db.SelectContext(ctx, &jobs, query, challengeAddress)
+	return jobs, err
+}
+
+func (s *PostgresStore) CreateNotification(ctx context.Context, notification *Notification) (bool, error) {
+	ctx, done := s.timed(ctx, "CreateNotification")
+	defer done()
+
+	query := `
+		INSERT INTO notifications (challenge_address, kind, message)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (challenge_address, kind) DO NOTHING
+		RETURNING id, created_at
+	`
+	err := s.db.QueryRowContext(ctx, query, notification.ChallengeAddress, notification.Kind, notification.Message).
+		Scan(&notification.ID, &notification.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *PostgresStore) ListNotifications(ctx context.Context, challengeAddress string, limit int) ([]Notification, error) {
+	ctx, done := s.timed(ctx, "ListNotifications")
+	defer done()
+
+	notifications := []Notification{}
+	query := `
+		SELECT * FROM notifications
+		WHERE $1 = '' OR challenge_address = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+	err := s.db.SelectContext(ctx, &notifications, query, challengeAddress, limit)
+	return notifications, err
+}
+
+func (s *PostgresStore) CreateWebhook(ctx context.Context, webhook *Webhook) error {
+	ctx, done := s.timed(ctx, "CreateWebhook")
+	defer done()
+
+	query := `
+		INSERT INTO webhooks (owner, url, secret, events)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, active, created_at
+	`
+	return s.db.QueryRowContext(ctx, query, webhook.Owner, webhook.URL, webhook.Secret, webhook.Events).
+		Scan(&webhook.ID, &webhook.Active, &webhook.CreatedAt)
+}
+
+func (s *PostgresStore) GetWebhook(ctx context.Context, id int) (*Webhook, error) {
+	ctx, done := s.timed(ctx, "GetWebhook")
+	defer done()
+
+	var webhook Webhook
+	err := s.db.GetContext(ctx, &webhook, `SELECT * FROM webhooks WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &webhook, err
+}
+
+func (s *PostgresStore) ListWebhooks(ctx context.Context, owner string) ([]Webhook, error) {
+	ctx, done := s.timed(ctx, "ListWebhooks")
+	defer done()
+
+	webhooks := []Webhook{}
+	err := s.db.SelectContext(ctx, &webhooks, `SELECT * FROM webhooks WHERE owner = $1 ORDER BY id`, owner)
+	return webhooks, err
+}
+
+func (s *PostgresStore) DeactivateWebhook(ctx context.Context, id int) error {
+	ctx, done := s.timed(ctx, "DeactivateWebhook")
+	defer done()
+
+	_, err := s.db.ExecContext(ctx, `UPDATE webhooks SET active = FALSE WHERE id = $1`, id)
+	return err
+}
+
+func (s *PostgresStore) EnqueueDeliveries(ctx context.Context, eventID, eventType, owner string, payload []byte, now time.Time) (int, error) {
+	ctx, done := s.timed(ctx, "EnqueueDeliveries")
+	defer done()
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, next_attempt_at)
+		SELECT id, $1, $2, $3::jsonb, $4
+		FROM webhooks
+		WHERE active AND $2 = ANY(events) AND ($5 = '' OR owner = $5)
+	`
+	result, err := s.db.ExecContext(ctx, query, eventID, eventType, string(payload), now, owner)
+	if err != nil {
+		return 0, err
+	}
+	queued, err := result.RowsAffected()
+	return int(queued), err
+}
+
+func (s *PostgresStore) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	ctx, done := s.timed(ctx, "ClaimDueDeliveries")
+	defer done()
+
+	deliveries := []WebhookDelivery{}
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivering', locked_until = $2, attempts = attempts + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE (status = 'pending' AND next_attempt_at <= $1) OR (status = 'delivering' AND locked_until < $1)
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+	err := s.db.SelectContext(ctx, &deliveries, query, now, now.Add(lease), limit)
+	return deliveries, err
+}
+
+func (s *PostgresStore) CompleteDelivery(ctx context.Context, id int, responseStatus int, deliveredAt time.Time) error {
+	ctx, done := s.timed(ctx, "CompleteDelivery")
+	defer done()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', response_status = $2, delivered_at = $3, last_error = NULL,
+			locked_until = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	_, err := s.db.ExecContext(ctx, query, id, responseStatus, deliveredAt)
+	return err
+}
+
+func (s *PostgresStore) RetryDelivery(ctx context.Context, id int, nextAttempt *time.Time, responseStatus *int, lastError string) error {
+	ctx, done := s.timed(ctx, "RetryDelivery")
+	defer done()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN $2::timestamp IS NULL THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = COALESCE($2::timestamp, next_attempt_at),
+			response_status = $3,
+			last_error = $4,
+			locked_until = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	_, err := s.db.ExecContext(ctx, query, id, nextAttempt, responseStatus, lastError)
+	return err
+}
+
+func (s *PostgresStore) GetDelivery(ctx context.Context, id int) (*WebhookDelivery, error) {
+	ctx, done := s.timed(ctx, "GetDelivery")
+	defer done()
+
+	var delivery WebhookDelivery
+	err := s.db.GetContext(ctx, &delivery, `SELECT * FROM webhook_deliveries WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &delivery, err
+}
+
+func (s *PostgresStore) ListDeliveries(ctx context.Context, webhookID int, limit int) ([]WebhookDelivery, error) {
+	ctx, done := s.timed(ctx, "ListDeliveries")
+	defer done()
+
+	deliveries := []WebhookDelivery{}
+	query := `
+		SELECT * FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+	err := s.db.SelectContext(ctx, &deliveries, query, webhookID, limit)
+	return deliveries, err
+}
+
+func (s *PostgresStore) Redeliver(ctx context.Context, id int, now time.Time) error {
+	ctx, done := s.timed(ctx, "Redeliver")
+	defer done()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = $2, locked_until = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	_, err := s.db.ExecContext(ctx, query, id, now)
+	return err
+}
+
+func (s *PostgresStore) UpdateRateBuckets(ctx context.Context, keys []string, update func(buckets []*RateBucket)) error {
+	ctx, done := s.timed(ctx, "UpdateRateBuckets")
+	defer done()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Rows are locked in key order so that requests sharing buckets cannot
+	// deadlock. Two replicas creating the same bucket at once both start
+	// from a full bucket; the later write wins. That can grant one extra
+	// burst per new key, which is cheaper than serializing every first
+	// request.
+	var existing []RateBucket
+	err = tx.SelectContext(ctx, &existing, `SELECT * FROM rate_buckets WHERE key = ANY($1) ORDER BY key FOR UPDATE`, pq.Array(keys))
+	if err != nil {
+		return err
+	}
+	found := make(map[string]RateBucket, len(existing))
+	for _, bucket := range existing {
+		found[bucket.Key] = bucket
+	}
+	buckets := make([]*RateBucket, len(keys))
+	for i, key := range keys {
+		bucket, ok := found[key]
+		if !ok {
+			bucket.Key = key
+		}
+		buckets[i] = &bucket
+	}
+
+	update(buckets)
+
+	query := `
+		INSERT INTO rate_buckets (key, tokens, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET tokens = EXCLUDED.tokens, updated_at = EXCLUDED.updated_at
+	`
+	for _, bucket := range buckets {
+		if _, err := tx.ExecContext(ctx, query, bucket.Key, bucket.Tokens, bucket.UpdatedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) DeleteIdleRateBuckets(ctx context.Context, before time.Time) (int, error) {
+	ctx, done := s.timed(ctx, "DeleteIdleRateBuckets")
+	defer done()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM rate_buckets WHERE updated_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
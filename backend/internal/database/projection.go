@@ -0,0 +1,243 @@
+package database
+
+import (
+	"sort"
+	"strings"
+)
+
+// The leaderboard table is a projection of reputation_events. Repositories
+// only ever write it by replaying the event log through the functions in
+// this file, so duplicate or out-of-order event submissions cannot leave it
+// in a different state than a fresh rebuild would.
+
+// ReplayReputationEvents folds events into leaderboard rows. Events must
+// already be in block order; the result is sorted by wallet address. Only
+// amounts paid in usdc, the USDC token address, count towards the USDC
+// columns; other tokens are kept in the per-token winnings alone.
+func ReplayReputationEvents(events []ReputationEvent, usdc string) []LeaderboardEntry {
+	entries, _, _ := replay(events, usdc)
+	return entries
+}
+
+// ReplayTokenWinnings folds events into per-token winnings, sorted by wallet
+// and then token address.
+func ReplayTokenWinnings(events []ReputationEvent) []TokenWinnings {
+	_, winnings, _ := replay(events, "")
+	return winnings
+}
+
+type walletToken struct {
+	wallet string
+	token  string
+}
+
+// IsUSDC reports whether an event's token is the USDC deployment usdc.
+func IsUSDC(tokenAddress, usdc string) bool {
+	return strings.EqualFold(tokenAddress, usdc)
+}
+
+func replay(events []ReputationEvent, usdc string) ([]LeaderboardEntry, []TokenWinnings, []EventGap) {
+	byWallet := make(map[string]*LeaderboardEntry)
+	byToken := make(map[walletToken]*TokenWinnings)
+	var gaps []EventGap
+
+	for _, event := range events {
+		entry, exists := byWallet[event.WalletAddress]
+		if !exists {
+			entry = &LeaderboardEntry{WalletAddress: event.WalletAddress}
+			byWallet[event.WalletAddress] = entry
+		}
+
+		key := walletToken{event.WalletAddress, event.TokenAddress}
+		winnings, exists := byToken[key]
+		if !exists {
+			winnings = &TokenWinnings{WalletAddress: event.WalletAddress, TokenAddress: event.TokenAddress}
+			byToken[key] = winnings
+		}
+
+		entry.ReputationScore += event.PointsAdded
+		isUSDC := IsUSDC(event.TokenAddress, usdc)
+		if event.IsVerifier {
+			entry.ChallengesVerified++
+			entry.VerifierPoints += event.PointsAdded
+			if isUSDC {
+				entry.VerifierFeesEarned = entry.VerifierFeesEarned.Add(event.USDCAmount)
+			}
+			winnings.Verifications++
+			winnings.FeesEarned = winnings.FeesEarned.Add(event.USDCAmount)
+		} else {
+			entry.ChallengesWon++
+			if isUSDC {
+				entry.TotalUSDCWon = entry.TotalUSDCWon.Add(event.USDCAmount)
+			}
+			winnings.Wins++
+			winnings.AmountWon = winnings.AmountWon.Add(event.USDCAmount)
+		}
+
+		if event.TotalPoints != entry.ReputationScore {
+			gaps = append(gaps, EventGap{
+				WalletAddress:   event.WalletAddress,
+				EventID:         event.ID,
+				TransactionHash: event.TransactionHash,
+				BlockNumber:     event.BlockNumber,
+				ReportedTotal:   event.TotalPoints,
+				ReplayedTotal:   entry.ReputationScore,
+			})
+		}
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(byWallet))
+	for _, entry := range byWallet {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].WalletAddress < entries[j].WalletAddress
+	})
+
+	winnings := make([]TokenWinnings, 0, len(byToken))
+	for _, row := range byToken {
+		winnings = append(winnings, *row)
+	}
+	sort.Slice(winnings, func(i, j int) bool {
+		if winnings[i].WalletAddress != winnings[j].WalletAddress {
+			return winnings[i].WalletAddress < winnings[j].WalletAddress
+		}
+		return winnings[i].TokenAddress < winnings[j].TokenAddress
+	})
+
+	return entries, winnings, gaps
+}
+
+// BuildConsistencyReport compares the projections replayed from events
+// (which must be in block order) with the stored leaderboard and per-token
+// winnings rows, and reports events whose reported total does not match the
+// running sum.
+func BuildConsistencyReport(events []ReputationEvent, usdc string, stored []LeaderboardEntry, storedWinnings []TokenWinnings) *ConsistencyReport {
+	projected, projectedWinnings, gaps := replay(events, usdc)
+
+	storedByWallet := make(map[string]LeaderboardEntry, len(stored))
+	for _, entry := range stored {
+		storedByWallet[entry.WalletAddress] = entry
+	}
+
+	report := &ConsistencyReport{
+		EventsReplayed: len(events),
+		Drift:          []LeaderboardDrift{},
+		Gaps:           gaps,
+	}
+	if report.Gaps == nil {
+		report.Gaps = []EventGap{}
+	}
+
+	for _, want := range projected {
+		report.WalletsChecked++
+		got, exists := storedByWallet[want.WalletAddress]
+		if !exists {
+			report.Drift = append(report.Drift, LeaderboardDrift{
+				WalletAddress: want.WalletAddress,
+				Field:         "row",
+				Projected:     "present",
+				Stored:        "missing",
+			})
+			continue
+		}
+		delete(storedByWallet, want.WalletAddress)
+		report.Drift = append(report.Drift, compareEntries(want, got)...)
+	}
+
+	for wallet := range storedByWallet {
+		report.WalletsChecked++
+		report.Drift = append(report.Drift, LeaderboardDrift{
+			WalletAddress: wallet,
+			Field:         "row",
+			Projected:     "missing",
+			Stored:        "present",
+		})
+	}
+
+	report.Drift = append(report.Drift, compareWinnings(projectedWinnings, storedWinnings)...)
+
+	report.Consistent = len(report.Drift) == 0 && len(report.Gaps) == 0
+	return report
+}
+
+func compareEntries(want, got LeaderboardEntry) []LeaderboardDrift {
+	var drift []LeaderboardDrift
+	check := func(field string, projected, stored interface{}) {
+		if projected != stored {
+			drift = append(drift, LeaderboardDrift{
+				WalletAddress: want.WalletAddress,
+				Field:         field,
+				Projected:     projected,
+				Stored:        stored,
+			})
+		}
+	}
+
+	check("reputation_score", want.ReputationScore, got.ReputationScore)
+	check("total_usdc_won", want.TotalUSDCWon.String(), got.TotalUSDCWon.String())
+	check("challenges_won", want.ChallengesWon, got.ChallengesWon)
+	check("challenges_verified", want.ChallengesVerified, got.ChallengesVerified)
+	check("verifier_points", want.VerifierPoints, got.VerifierPoints)
+	check("verifier_fees_earned", want.VerifierFeesEarned.String(), got.VerifierFeesEarned.String())
+
+	return drift
+}
+
+// compareWinnings reports per-token rows that are missing on either side or
+// whose totals differ. Fields are named "winnings.<token>.<column>".
+func compareWinnings(projected, stored []TokenWinnings) []LeaderboardDrift {
+	storedByKey := make(map[walletToken]TokenWinnings, len(stored))
+	for _, row := range stored {
+		storedByKey[walletToken{row.WalletAddress, row.TokenAddress}] = row
+	}
+
+	var drift []LeaderboardDrift
+	for _, want := range projected {
+		key := walletToken{want.WalletAddress, want.TokenAddress}
+		prefix := "winnings." + want.TokenAddress + "."
+
+		got, exists := storedByKey[key]
+		if !exists {
+			drift = append(drift, LeaderboardDrift{
+				WalletAddress: want.WalletAddress,
+				Field:         prefix + "row",
+				Projected:     "present",
+				Stored:        "missing",
+			})
+			continue
+		}
+		delete(storedByKey, key)
+
+		fields := []struct {
+			name              string
+			projected, stored interface{}
+		}{
+			{"amount_won", want.AmountWon.String(), got.AmountWon.String()},
+			{"wins", want.Wins, got.Wins},
+			{"fees_earned", want.FeesEarned.String(), got.FeesEarned.String()},
+			{"verifications", want.Verifications, got.Verifications},
+		}
+		for _, field := range fields {
+			if field.projected != field.stored {
+				drift = append(drift, LeaderboardDrift{
+					WalletAddress: want.WalletAddress,
+					Field:         prefix + field.name,
+					Projected:     field.projected,
+					Stored:        field.stored,
+				})
+			}
+		}
+	}
+
+	for key := range storedByKey {
+		drift = append(drift, LeaderboardDrift{
+			WalletAddress: key.wallet,
+			Field:         "winnings." + key.token + ".row",
+			Projected:     "missing",
+			Stored:        "present",
+		})
+	}
+
+	return drift
+}
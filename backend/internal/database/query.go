@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// QueryConfig bounds how long repository calls may run and when they are
+// logged as slow.
+type QueryConfig struct {
+	// Timeout is the deadline for a single repository call.
+	Timeout time.Duration
+	// MaintenanceTimeout replaces Timeout for full-table work such as
+	// rebuilding the leaderboard.
+	MaintenanceTimeout time.Duration
+	// SlowThreshold logs calls that take at least this long. Zero disables
+	// slow query logging.
+	SlowThreshold time.Duration
+}
+
+func DefaultQueryConfig() QueryConfig {
+	return QueryConfig{
+		Timeout:            5 * time.Second,
+		MaintenanceTimeout: 2 * time.Minute,
+		SlowThreshold:      500 * time.Millisecond,
+	}
+}
+
+// LoadQueryConfig reads DB_QUERY_TIMEOUT, DB_MAINTENANCE_TIMEOUT and
+// DB_SLOW_QUERY_THRESHOLD as Go durations (e.g. "3s", "250ms"), falling back
+// to DefaultQueryConfig for unset variables.
+func LoadQueryConfig() (QueryConfig, error) {
+	config := DefaultQueryConfig()
+
+	settings := []struct {
+		env   string
+		value *time.Duration
+	}{
+		{"DB_QUERY_TIMEOUT", &config.Timeout},
+		{"DB_MAINTENANCE_TIMEOUT", &config.MaintenanceTimeout},
+		{"DB_SLOW_QUERY_THRESHOLD", &config.SlowThreshold},
+	}
+
+	for _, setting := range settings {
+		raw := os.Getenv(setting.env)
+		if raw == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(raw)
+		if err != nil || duration < 0 {
+			return QueryConfig{}, fmt.Errorf("%s must be a non-negative duration, got %q", setting.env, raw)
+		}
+		*setting.value = duration
+	}
+
+	if config.Timeout == 0 || config.MaintenanceTimeout == 0 {
+		return QueryConfig{}, fmt.Errorf("query timeouts must be greater than zero")
+	}
+
+	return config, nil
+}
+
+// timed starts a named repository call. The returned context carries the
+// call's deadline, so lib/pq cancels the statement on the server once it
+// expires or the HTTP client disconnects. done must be called when the call
+// returns; it logs slow and timed-out calls with their name and duration.
+func (s *PostgresStore) timed(ctx context.Context, name string) (context.Context, func()) {
+	return s.startQuery(ctx, name, s.config.Timeout)
+}
+
+// timedMaintenance is timed with the longer maintenance deadline.
+func (s *PostgresStore) timedMaintenance(ctx context.Context, name string) (context.Context, func()) {
+	return s.startQuery(ctx, name, s.config.MaintenanceTimeout)
+}
+
+func (s *PostgresStore) startQuery(ctx context.Context, name string, timeout time.Duration) (context.Context, func()) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	start := time.Now()
+
+	return ctx, func() {
+		elapsed := time.Since(start)
+		timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+		cancel()
+
+		switch {
+		case timedOut:
+			log.Printf("query %s timed out after %s", name, elapsed)
+		case s.config.SlowThreshold > 0 && elapsed >= s.config.SlowThreshold:
+			log.Printf("slow query %s took %s", name, elapsed)
+		}
+	}
+}
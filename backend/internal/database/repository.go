@@ -1,188 +1,217 @@
 package database
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"time"
 )
 
-func CreateSubmission(submission *Submission) error {
-	query := `
-		INSERT INTO submissions (uid, challenge_address, wallet_address, solution_code, solution_hash, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at, updated_at
-	`
-	
-	err := DB.QueryRow(
-		query,
-		submission.UID,
-		submission.ChallengeAddress,
-		submission.WalletAddress,
-		submission.SolutionCode,
-		submission.SolutionHash,
-		submission.Status,
-	).Scan(&submission.ID, &submission.CreatedAt, &submission.UpdatedAt)
-	
-	return err
-}
+// ErrSubmissionExists is returned when a submission's UID is already taken.
+var ErrSubmissionExists = errors.New("submission uid already exists")
 
-func GetSubmissionByUID(uid string) (*Submission, error) {
-	var submission Submission
-	query := `SELECT * FROM submissions WHERE uid = $1`
-	err := DB.Get(&submission, query, uid)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	return &submission, err
+// SubmissionRepository stores solution submissions.
+type SubmissionRepository interface {
+	CreateSubmission(ctx context.Context, submission *Submission) error
+	// GetSubmissionByUID returns nil, nil when no submission has the UID.
+	GetSubmissionByUID(ctx context.Context, uid string) (*Submission, error)
+	UpdateSubmissionStatus(ctx context.Context, uid string, status string, solutionHash string) error
+	// ListSubmissions returns up to query.Limit submissions matching query,
+	// in query.Sort order, starting after query.After.
+	ListSubmissions(ctx context.Context, query SubmissionQuery) ([]Submission, error)
+	// MarkLateSubmissions sets pending submissions to a challenge created
+	// after the deadline to "late" and returns the ones that changed, without
+	// their solution code. Revealed commitments are exempt, since they were
+	// committed in time.
+	MarkLateSubmissions(ctx context.Context, challengeAddress string, deadline time.Time) ([]Submission, error)
 }
 
-func UpdateSubmissionStatus(uid string, status string, solutionHash string) error {
-	query := `
-		UPDATE submissions 
-		SET status = $2, solution_hash = $3, updated_at = CURRENT_TIMESTAMP
-		WHERE uid = $1
-	`
-	_, err := DB.Exec(query, uid, status, solutionHash)
-	return err
+// LeaderboardRepository reads the leaderboard projection and maintains it
+// from the reputation event log.
+type LeaderboardRepository interface {
+	GetLeaderboard(ctx context.Context, limit int, offset int) ([]LeaderboardEntry, error)
+	// GetWindowedLeaderboard ranks wallets by points earned from events in
+	// [start, end). role narrows the ranking to points earned as RoleSolver
+	// or RoleVerifier; an empty role counts both.
+	GetWindowedLeaderboard(ctx context.Context, start, end time.Time, role string, limit int, offset int) ([]WindowedLeaderboardEntry, error)
+	GetSolverLeaderboard(ctx context.Context, limit int, offset int) ([]SolverLeaderboardEntry, error)
+	GetVerifierLeaderboard(ctx context.Context, limit int, offset int) ([]VerifierLeaderboardEntry, error)
+	// GetUserStats returns nil, nil for wallets without reputation.
+	GetUserStats(ctx context.Context, walletAddress string) (*LeaderboardEntry, error)
+	GetLeaderboardPosition(ctx context.Context, walletAddress string) (int, error)
+	// GetTokenWinnings returns a wallet's totals per reward token, ordered
+	// by token address.
+	GetTokenWinnings(ctx context.Context, walletAddress string) ([]TokenWinnings, error)
+	// GetTopPoints returns the highest reputation scores in descending order,
+	// the indexed equivalent of ReputationSystem's topPerformers list.
+	GetTopPoints(ctx context.Context, limit int) ([]int, error)
+	// CountUsers returns the number of wallets with reputation and how many
+	// of them have strictly fewer points than the given score.
+	CountUsers(ctx context.Context, points int) (total int, below int, err error)
+	// RebuildLeaderboard discards the leaderboard and per-token winnings and
+	// replays every event in block order. It returns the number of events
+	// replayed.
+	RebuildLeaderboard(ctx context.Context) (int, error)
+	// CheckLeaderboardConsistency replays the event log without writing and
+	// reports every difference from the stored leaderboard.
+	CheckLeaderboardConsistency(ctx context.Context) (*ConsistencyReport, error)
 }
 
-func GetLeaderboard(limit int, offset int) ([]LeaderboardEntry, error) {
-	var entries []LeaderboardEntry
-	query := `
-		SELECT * FROM leaderboard 
-		ORDER BY reputation_score DESC, total_usdc_won DESC
-		LIMIT $1 OFFSET $2
-	`
-	err := DB.Select(&entries, query, limit, offset)
-	return entries, err
+// ReputationRepository records on-chain reputation activity.
+type ReputationRepository interface {
+	// RecordReputationEvent appends an event to the log and refreshes the
+	// wallet's leaderboard and winnings rows in the same transaction. Events are keyed by
+	// (transaction_hash, log_index); recording one that already exists is a
+	// no-op and reports inserted == false.
+	RecordReputationEvent(ctx context.Context, event *ReputationEvent) (inserted bool, err error)
+	// GetPointsBefore sums the points a wallet earned from events that
+	// precede the given chain position in block order.
+	GetPointsBefore(ctx context.Context, walletAddress string, blockNumber int64, logIndex int) (int, error)
+	GetRecentReputationEvents(ctx context.Context, limit int) ([]ReputationEvent, error)
+	// CreateSolutionApproval records a SolutionApproved event, ignoring
+	// events that were already recorded. A new approval is linked to the
+	// approver's review of the submission, if there is one.
+	CreateSolutionApproval(ctx context.Context, approval *SolutionApproval) (inserted bool, err error)
+	CreateThresholdUpdate(ctx context.Context, update *ThresholdUpdate) (inserted bool, err error)
+	// GetThresholdHistory returns ThresholdUpdated events, newest first.
+	GetThresholdHistory(ctx context.Context, limit int) ([]ThresholdUpdate, error)
 }
 
-func UpsertLeaderboardEntry(entry *LeaderboardEntry) error {
-	query := `
-		INSERT INTO leaderboard (wallet_address, reputation_score, total_usdc_won, challenges_won, challenges_verified)
-		VALUES ($1, $2, $3, $4, $5)
-		ON CONFLICT (wallet_address) 
-		DO UPDATE SET 
-			reputation_score = $2,
-			total_usdc_won = leaderboard.total_usdc_won + $3,
-			challenges_won = leaderboard.challenges_won + $4,
-			challenges_verified = leaderboard.challenges_verified + $5,
-			last_updated = CURRENT_TIMESTAMP
-		RETURNING id, last_updated
-	`
-	
-	err := DB.QueryRow(
-		query,
-		entry.WalletAddress,
-		entry.ReputationScore,
-		entry.TotalUSDCWon,
-		entry.ChallengesWon,
-		entry.ChallengesVerified,
-	).Scan(&entry.ID, &entry.LastUpdated)
-	
-	return err
+// ChallengeRepository stores challenge state indexed from ChallengeEscrow.
+type ChallengeRepository interface {
+	// GetChallenge returns nil, nil for challenges that have not been indexed.
+	GetChallenge(ctx context.Context, address string) (*Challenge, error)
+	// RecordChallengeEvent applies an event with ApplyChallengeEvent and
+	// returns the resulting state and whether it changed.
+	RecordChallengeEvent(ctx context.Context, event ChallengeEvent) (*Challenge, bool, error)
+	// ListChallengesByVerifier returns the challenges in the given status
+	// whose selected verifier is the wallet, oldest first.
+	ListChallengesByVerifier(ctx context.Context, verifier string, status string) ([]Challenge, error)
+	// ListChallengesWithDeadlines returns open and active challenges with a
+	// deadline at or after the given time, soonest first.
+	ListChallengesWithDeadlines(ctx context.Context, after time.Time) ([]Challenge, error)
+	// ListChallenges returns challenges newest first, only those in status
+	// when it is non-empty.
+	ListChallenges(ctx context.Context, status string, limit, offset int) ([]Challenge, error)
 }
 
-func CreateReputationEvent(event *ReputationEvent) error {
-	query := `
-		INSERT INTO reputation_events (wallet_address, event_type, points_added, total_points, is_verifier, transaction_hash, block_number)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, created_at
-	`
-	
-	err := DB.QueryRow(
-		query,
-		event.WalletAddress,
-		event.EventType,
-		event.PointsAdded,
-		event.TotalPoints,
-		event.IsVerifier,
-		event.TransactionHash,
-		event.BlockNumber,
-	).Scan(&event.ID, &event.CreatedAt)
-	
-	return err
+// CommitmentRepository stores commit-reveal commitments.
+type CommitmentRepository interface {
+	// CreateCommitment stores a new commitment and sets its ID and
+	// CommittedAt. It reports inserted == false when the commitment hash
+	// is already registered.
+	CreateCommitment(ctx context.Context, commitment *Commitment) (inserted bool, err error)
+	// GetCommitment returns nil, nil for unknown commitment hashes.
+	GetCommitment(ctx context.Context, hash string) (*Commitment, error)
+	// RevealCommitment creates the revealed submission and links it to the
+	// commitment in one transaction. It reports revealed == false when the
+	// commitment was revealed concurrently, and returns ErrSubmissionExists
+	// when the submission's UID is taken.
+	RevealCommitment(ctx context.Context, hash string, submission *Submission) (revealed bool, err error)
+	// ListCommitments returns a challenge's commitments in priority order,
+	// earliest first.
+	ListCommitments(ctx context.Context, challengeAddress string) ([]Commitment, error)
 }
 
-func GetUserStats(walletAddress string) (*LeaderboardEntry, error) {
-	var entry LeaderboardEntry
-	query := `SELECT * FROM leaderboard WHERE wallet_address = $1`
-	err := DB.Get(&entry, query, walletAddress)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	return &entry, err
+// SimilarityRepository stores submission fingerprints and the
+// near-duplicate flags derived from them.
+type SimilarityRepository interface {
+	// SaveFingerprint stores or replaces a submission's fingerprint and
+	// replaces its flags in one transaction.
+	SaveFingerprint(ctx context.Context, fingerprint *SubmissionFingerprint, flags []SimilarityFlag) error
+	// ListFingerprints returns a challenge's fingerprints in submission
+	// order, oldest first.
+	ListFingerprints(ctx context.Context, challengeAddress string) ([]SubmissionFingerprint, error)
+	// ListSimilarityFlags returns a challenge's flags, highest score first.
+	ListSimilarityFlags(ctx context.Context, challengeAddress string) ([]SimilarityFlag, error)
 }
 
-func GetSubmissionsByWallet(walletAddress string, limit int) ([]Submission, error) {
-	var submissions []Submission
-	query := `
-		SELECT * FROM submissions 
-		WHERE wallet_address = $1
-		ORDER BY created_at DESC
-		LIMIT $2
-	`
-	err := DB.Select(&submissions, query, walletAddress, limit)
-	return submissions, err
+// ReviewRepository stores the automatic checks and verifier reviews behind
+// the verifier workbench.
+type ReviewRepository interface {
+	// SaveVerification stores or replaces a submission's automatic check.
+	SaveVerification(ctx context.Context, verification *SubmissionVerification) error
+	// ListVerifications returns the checks of a challenge's submissions.
+	ListVerifications(ctx context.Context, challengeAddress string) ([]SubmissionVerification, error)
+	// SaveReview creates or updates the verifier's review of a submission,
+	// keeping any linked approval.
+	SaveReview(ctx context.Context, review *VerifierReview) error
+	// ListReviews returns a verifier's reviews, newest first.
+	ListReviews(ctx context.Context, verifier string) ([]VerifierReview, error)
 }
 
-func GetSubmissionsByChallenge(challengeAddress string) ([]Submission, error) {
-	var submissions []Submission
-	query := `
-		SELECT * FROM submissions 
-		WHERE challenge_address = $1
-		ORDER BY created_at DESC
-	`
-	err := DB.Select(&submissions, query, challengeAddress)
-	return submissions, err
+// JobRepository stores scheduled jobs and the notifications they emit.
+// Times are passed in by the caller so that a fake clock drives them too.
+type JobRepository interface {
+	// ScheduleJob stores a new job, setting its ID, or moves the challenge's
+	// pending job of that kind to the new run time. It reports
+	// scheduled == false when that job has already been claimed or run.
+	ScheduleJob(ctx context.Context, job *ScheduledJob) (scheduled bool, err error)
+	// ClaimDueJobs marks up to limit jobs due at now as running until
+	// now+lease and returns them. Running jobs whose lease has expired are
+	// claimed again.
+	ClaimDueJobs(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]ScheduledJob, error)
+	CompleteJob(ctx context.Context, id int) error
+	// RetryJob records a failed attempt and runs the job again at runAt, or
+	// marks it failed when runAt is nil.
+	RetryJob(ctx context.Context, id int, runAt *time.Time, lastError string) error
+	// ListJobs returns a challenge's jobs in run order.
+	ListJobs(ctx context.Context, challengeAddress string) ([]ScheduledJob, error)
+	// CreateNotification stores a notification unless the challenge already
+	// has one of that kind.
+	CreateNotification(ctx context.Context, notification *Notification) (inserted bool, err error)
+	// ListNotifications returns notifications, newest first, optionally for
+	// a single challenge.
+	ListNotifications(ctx context.Context, challengeAddress string, limit int) ([]Notification, error)
 }
 
-func GetTopPerformers(limit int) ([]LeaderboardEntry, error) {
-	if limit > 100 {
-		limit = 100
-	}
-	return GetLeaderboard(limit, 0)
+// WebhookRepository stores webhook subscriptions and their delivery outbox.
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *Webhook) error
+	GetWebhook(ctx context.Context, id int) (*Webhook, error)
+	// ListWebhooks returns the owner's webhooks, including inactive ones.
+	ListWebhooks(ctx context.Context, owner string) ([]Webhook, error)
+	// DeactivateWebhook stops new deliveries to a webhook. Its log is kept.
+	DeactivateWebhook(ctx context.Context, id int) error
+	// EnqueueDeliveries queues the event for every active webhook subscribed
+	// to its type and returns how many deliveries were queued. A non-empty
+	// owner limits it to that wallet's webhooks.
+	EnqueueDeliveries(ctx context.Context, eventID, eventType, owner string, payload []byte, now time.Time) (int, error)
+	// ClaimDueDeliveries marks up to limit deliveries due at now as
+	// delivering until now+lease and returns them. Deliveries whose lease has
+	// expired are claimed again.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error)
+	CompleteDelivery(ctx context.Context, id int, responseStatus int, deliveredAt time.Time) error
+	// RetryDelivery records a failed attempt and tries again at nextAttempt,
+	// or marks the delivery failed when nextAttempt is nil.
+	RetryDelivery(ctx context.Context, id int, nextAttempt *time.Time, responseStatus *int, lastError string) error
+	GetDelivery(ctx context.Context, id int) (*WebhookDelivery, error)
+	// ListDeliveries returns a webhook's deliveries, newest first.
+	ListDeliveries(ctx context.Context, webhookID int, limit int) ([]WebhookDelivery, error)
+	// Redeliver queues a delivery again at now with a fresh attempt budget,
+	// whatever its status.
+	Redeliver(ctx context.Context, id int, now time.Time) error
 }
 
-func UpdateLeaderboardFromEvent(walletAddress string, pointsAdded int, totalPoints int, isWinner bool, usdcAmount float64) error {
-	var challengesWon, challengesVerified int
-	if isWinner {
-		challengesWon = 1
-	} else {
-		challengesVerified = 1
-	}
-	
-	entry := &LeaderboardEntry{
-		WalletAddress:      walletAddress,
-		ReputationScore:    totalPoints,
-		TotalUSDCWon:       usdcAmount,
-		ChallengesWon:      challengesWon,
-		ChallengesVerified: challengesVerified,
-	}
-	
-	return UpsertLeaderboardEntry(entry)
+// RateLimitRepository stores rate limit token buckets.
+type RateLimitRepository interface {
+	// UpdateRateBuckets calls update with the bucket for each key, in the
+	// order of keys, while holding them all exclusively, then stores the
+	// results.
+	UpdateRateBuckets(ctx context.Context, keys []string, update func(buckets []*RateBucket)) error
+	// DeleteIdleRateBuckets removes buckets last used before the given time.
+	DeleteIdleRateBuckets(ctx context.Context, before time.Time) (int, error)
 }
 
-func GetRecentReputationEvents(limit int) ([]ReputationEvent, error) {
-	var events []ReputationEvent
-	query := `
-		SELECT * FROM reputation_events 
-		ORDER BY created_at DESC
-		LIMIT $1
-	`
-	err := DB.Select(&events, query, limit)
-	return events, err
+// Repositories bundles the repositories handlers depend on.
+type Repositories struct {
+	Submissions SubmissionRepository
+	Leaderboard LeaderboardRepository
+	Reputation  ReputationRepository
+	Challenges  ChallengeRepository
+	Commitments CommitmentRepository
+	Similarity  SimilarityRepository
+	Reviews     ReviewRepository
+	Jobs        JobRepository
+	Webhooks    WebhookRepository
+	RateLimits  RateLimitRepository
 }
-
-func GetLeaderboardPosition(walletAddress string) (int, error) {
-	var position int
-	query := `
-		SELECT COUNT(*) + 1 as position
-		FROM leaderboard
-		WHERE reputation_score > (
-			SELECT reputation_score 
-			FROM leaderboard 
-			WHERE wallet_address = $1
-		)
-	`
-	err := DB.Get(&position, query, walletAddress)
-	return position, err
-}
\ No newline at end of file
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Submission list sort orders.
+const (
+	SortNewest = "newest"
+	SortOldest = "oldest"
+)
+
+// SubmissionQuery selects one page of submissions. Empty fields do not
+// filter. Pages are keyed on (created_at, id), so rows inserted while a
+// client is paging never shift the pages it has not read yet.
+type SubmissionQuery struct {
+	WalletAddress    string
+	ChallengeAddress string
+	Statuses         []string
+	CreatedAfter     *time.Time // inclusive
+	CreatedBefore    *time.Time // exclusive
+	Sort             string     // SortNewest (default) or SortOldest
+	After            *SubmissionCursor
+	Limit            int
+	// IncludeCode loads solution_code, which is otherwise left empty.
+	IncludeCode bool
+}
+
+// SubmissionCursor marks the last row of a page. It is bound to the sort
+// order it was issued for.
+type SubmissionCursor struct {
+	Sort      string
+	CreatedAt time.Time
+	ID        int
+}
+
+// CursorAfter returns the cursor that continues a listing after submission.
+func CursorAfter(submission Submission, sort string) SubmissionCursor {
+	return SubmissionCursor{Sort: sort, CreatedAt: submission.CreatedAt, ID: submission.ID}
+}
+
+// Encode renders the cursor as an opaque URL-safe token.
+func (c SubmissionCursor) Encode() string {
+	raw := fmt.Sprintf("%s:%d:%d", c.Sort, c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeSubmissionCursor parses a token produced by Encode.
+func DecodeSubmissionCursor(token string) (*SubmissionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || (parts[0] != SortNewest && parts[0] != SortOldest) {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &SubmissionCursor{Sort: parts[0], CreatedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
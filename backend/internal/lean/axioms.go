@@ -0,0 +1,179 @@
+package lean
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// StandardAxioms are the axioms of Lean's core logic. A proof that depends
+// only on these is as trustworthy as Lean itself.
+var StandardAxioms = []string{"propext", "Classical.choice", "Quot.sound"}
+
+// SorryAxiom is what sorry and admit elaborate to.
+const SorryAxiom = "sorryAx"
+
+// Files of the project a submission is audited in. The submission is built
+// as its own module; the audit file imports it and prints the axioms of each
+// theorem, so the reports come from a file the submission does not control.
+const (
+	SubmissionModule = "Submission"
+	SubmissionFile   = SubmissionModule + ".lean"
+	AuditModule      = "AxiomAudit"
+	AuditFile        = AuditModule + ".lean"
+)
+
+// AxiomAudit records the axioms each theorem in a proof depends on.
+type AxiomAudit struct {
+	// Declarations maps each audited theorem to its axioms.
+	Declarations map[string][]string `json:"declarations"`
+	// Nonstandard lists axioms outside StandardAxioms used anywhere,
+	// including sorryAx and Lean.ofReduceBool (native_decide).
+	Nonstandard []string `json:"nonstandard"`
+	UsesSorry   bool     `json:"uses_sorry"`
+	// Missing lists theorems without exactly one report from the audit
+	// file, usually because they failed to elaborate.
+	Missing []string `json:"missing,omitempty"`
+}
+
+var (
+	declaration = regexp.MustCompile(`^\s*(?:@\[[^\]]*\]\s*)?((?:(?:private|protected|noncomputable)\s+)*)(?:theorem|lemma)\s+([^\s(:{\[⦃]+)`)
+	scopeOpen   = regexp.MustCompile(`^\s*(namespace|section)\b\s*(\S*)`)
+	scopeEnd    = regexp.MustCompile(`^\s*end\b`)
+
+	dependsOn     = regexp.MustCompile(`(?s)^'([^']+)' depends on axioms: \[([^\]]*)\]$`)
+	dependsOnNone = regexp.MustCompile(`^'([^']+)' does not depend on any axioms$`)
+)
+
+// Theorems returns the fully qualified names of the public theorems and
+// lemmas declared in code, tracking namespace blocks. Private theorems cannot
+// be named from the audit file; the axioms of those a public theorem uses
+// show up in its report.
+func Theorems(code string) []string {
+	var scopes []string
+	var names []string
+
+	for _, line := range strings.Split(code, "\n") {
+		if match := scopeOpen.FindStringSubmatch(line); match != nil {
+			if match[1] == "namespace" {
+				scopes = append(scopes, match[2])
+			} else {
+				scopes = append(scopes, "")
+			}
+			continue
+		}
+		if scopeEnd.MatchString(line) {
+			if len(scopes) > 0 {
+				scopes = scopes[:len(scopes)-1]
+			}
+			continue
+		}
+
+		match := declaration.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if strings.Contains(match[1], "private") {
+			continue
+		}
+		name := match[2]
+		if rooted, ok := strings.CutPrefix(name, "_root_."); ok {
+			names = append(names, rooted)
+			continue
+		}
+		parts := slices.DeleteFunc(slices.Clone(scopes), func(scope string) bool { return scope == "" })
+		names = append(names, strings.Join(append(parts, name), "."))
+	}
+	return names
+}
+
+// auditFirstLine is the line of the audit file's first #print axioms.
+const auditFirstLine = 2
+
+// AuditProject returns the files of a project that builds code as
+// SubmissionFile and, if it declares theorems, an AuditFile with one
+// #print axioms line per theorem. It also returns the project's entry
+// module and the theorems asked about.
+func AuditProject(code string) (map[string]string, string, []string) {
+	files := map[string]string{SubmissionFile: code}
+	theorems := Theorems(code)
+	if len(theorems) == 0 {
+		return files, SubmissionModule, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("import " + SubmissionModule + "\n")
+	for _, name := range theorems {
+		b.WriteString("#print axioms " + name + "\n")
+	}
+	files[AuditFile] = b.String()
+	return files, AuditModule, theorems
+}
+
+// AuditAxioms reads the #print axioms reports among the audit file's
+// diagnostics. A theorem's report only counts if it is the one message on
+// the theorem's own line and names that theorem; a second message there
+// means something else claimed to be its report, so it counts as missing.
+func AuditAxioms(diagnostics Diagnostics, theorems []string) AxiomAudit {
+	audit := AxiomAudit{Declarations: make(map[string][]string), Nonstandard: []string{}}
+
+	reports := make(map[int][]string)
+	for _, diagnostic := range diagnostics {
+		if diagnostic.File == AuditFile && diagnostic.Severity == SeverityInfo {
+			reports[diagnostic.Line] = append(reports[diagnostic.Line], diagnostic.Message)
+		}
+	}
+
+	for i, name := range theorems {
+		messages := reports[auditFirstLine+i]
+		axioms, ok := []string(nil), false
+		if len(messages) == 1 {
+			axioms, ok = parseReport(messages[0], name)
+		}
+		if !ok {
+			audit.Missing = append(audit.Missing, name)
+			continue
+		}
+		audit.Declarations[name] = axioms
+
+		for _, axiom := range axioms {
+			if axiom == SorryAxiom {
+				audit.UsesSorry = true
+			}
+			if !slices.Contains(StandardAxioms, axiom) && !slices.Contains(audit.Nonstandard, axiom) {
+				audit.Nonstandard = append(audit.Nonstandard, axiom)
+			}
+		}
+	}
+	slices.Sort(audit.Nonstandard)
+	return audit
+}
+
+// parseReport reads a #print axioms message and reports whether it is
+// about the named theorem.
+func parseReport(message, name string) ([]string, bool) {
+	if match := dependsOnNone.FindStringSubmatch(message); match != nil {
+		return []string{}, match[1] == name
+	}
+	match := dependsOn.FindStringSubmatch(message)
+	if match == nil || match[1] != name {
+		return nil, false
+	}
+	axioms := []string{}
+	for _, axiom := range strings.Split(match[2], ",") {
+		if axiom = strings.TrimSpace(axiom); axiom != "" {
+			axioms = append(axioms, axiom)
+		}
+	}
+	return axioms, true
+}
+
+func (a AxiomAudit) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+func (a *AxiomAudit) Scan(src interface{}) error {
+	return scanJSON(src, a)
+}
@@ -0,0 +1,46 @@
+package lean
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// disallowed are the commands a submission may not use, each pattern
+// capturing the command's name. Running code could print a fake axiom
+// report and #exit hides what follows it; redefining commands would reach
+// into the audit file, since it imports the submission.
+var disallowed = []struct {
+	pattern *regexp.Regexp
+	reason  string
+}{
+	{regexp.MustCompile(`(#eval)\b`), "runs code"},
+	{regexp.MustCompile(`(#exit)\b`), "stops checking the rest of the file"},
+	{regexp.MustCompile(`\b(run_cmd|run_elab|run_meta)\b`), "runs code"},
+	{regexp.MustCompile(`\b(macro_rules|elab_rules)\b`), "can redefine commands"},
+	{regexp.MustCompile(`\b(syntax|macro|elab)\b[^\n]*:\s*command\b`), "can redefine commands"},
+	{regexp.MustCompile(`@\[[^\]]*\b(command_elab|macro)\b`), "can redefine commands"},
+}
+
+// CheckCommands returns an error diagnostic for each line of code that uses
+// a command submissions may not use. Comments are not told apart from code,
+// so a mention in a comment is rejected too.
+func CheckCommands(code string) Diagnostics {
+	diagnostics := Diagnostics{}
+	for i, line := range strings.Split(code, "\n") {
+		for _, rule := range disallowed {
+			if loc := rule.pattern.FindStringSubmatchIndex(line); loc != nil {
+				diagnostics = append(diagnostics, Diagnostic{
+					File:     SubmissionFile,
+					Line:     i + 1,
+					Column:   utf8.RuneCountInString(line[:loc[0]]),
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("%s is not allowed in submissions: it %s", line[loc[2]:loc[3]], rule.reason),
+				})
+				break
+			}
+		}
+	}
+	return diagnostics
+}
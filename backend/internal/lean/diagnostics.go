@@ -0,0 +1,128 @@
+// Package lean interprets Lean 4 compiler output: it splits it into
+// positioned diagnostics, audits the axioms each theorem depends on and
+// turns the result into a verdict for reviewers.
+package lean
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Diagnostic severities as printed by Lean.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Diagnostic is one message from the Lean frontend. Line and Column are
+// 1-based and 0-based respectively, as Lean prints them; messages printed
+// without a position have both set to zero.
+type Diagnostic struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Diagnostics is stored as a JSON array.
+type Diagnostics []Diagnostic
+
+var messageHeader = regexp.MustCompile(`^(.+?):(\d+):(\d+): (error|warning|info): ?(.*)$`)
+
+// ParseDiagnostics splits Lean output into diagnostics. Lines that do not
+// start a new message continue the previous one; leading lines outside any
+// message become a position-less info diagnostic.
+func ParseDiagnostics(output string) Diagnostics {
+	diagnostics := Diagnostics{}
+	var current *Diagnostic
+
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if match := messageHeader.FindStringSubmatch(line); match != nil {
+			lineNumber, _ := strconv.Atoi(match[2])
+			column, _ := strconv.Atoi(match[3])
+			diagnostics = append(diagnostics, Diagnostic{
+				File:     match[1],
+				Line:     lineNumber,
+				Column:   column,
+				Severity: match[4],
+				Message:  match[5],
+			})
+			current = &diagnostics[len(diagnostics)-1]
+			continue
+		}
+
+		if current == nil {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			diagnostics = append(diagnostics, Diagnostic{Severity: SeverityInfo, Message: line})
+			current = &diagnostics[len(diagnostics)-1]
+			continue
+		}
+		current.Message += "\n" + line
+	}
+
+	for i := range diagnostics {
+		diagnostics[i].Message = strings.TrimRight(diagnostics[i].Message, "\n ")
+	}
+	return diagnostics
+}
+
+// Count returns how many diagnostics have the given severity.
+func (d Diagnostics) Count(severity string) int {
+	count := 0
+	for _, diagnostic := range d {
+		if diagnostic.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// UsesSorry reports whether Lean warned that a declaration uses sorry.
+func (d Diagnostics) UsesSorry() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityWarning && strings.HasPrefix(diagnostic.Message, "declaration uses 'sorry'") {
+			return true
+		}
+	}
+	return false
+}
+
+// String prints the diagnostics the way Lean does.
+func (d Diagnostics) String() string {
+	lines := make([]string, 0, len(d))
+	for _, diagnostic := range d {
+		lines = append(lines, fmt.Sprintf("%s:%d:%d: %s: %s", diagnostic.File, diagnostic.Line, diagnostic.Column, diagnostic.Severity, diagnostic.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (d Diagnostics) Value() (driver.Value, error) {
+	if d == nil {
+		d = Diagnostics{}
+	}
+	return json.Marshal(d)
+}
+
+func (d *Diagnostics) Scan(src interface{}) error {
+	return scanJSON(src, d)
+}
+
+func scanJSON(src interface{}, target interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, target)
+	case string:
+		return json.Unmarshal([]byte(value), target)
+	case nil:
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T as JSON", src)
+}
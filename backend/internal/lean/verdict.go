@@ -0,0 +1,47 @@
+package lean
+
+// Verdicts of the automatic check shown to verifiers.
+const (
+	// VerdictPending means the check has not finished yet.
+	VerdictPending = "pending"
+	// VerdictPassed means Lean accepted the proof with only standard axioms.
+	VerdictPassed = "passed"
+	// VerdictUnsound means Lean accepted the file but it uses sorry, or a
+	// theorem depends on a non-standard axiom or has no axiom report to
+	// show it does not.
+	VerdictUnsound = "unsound"
+	VerdictFailed  = "failed"
+	VerdictTimeout = "timeout"
+	// VerdictError means the runner could not be reached.
+	VerdictError = "error"
+)
+
+// Runner statuses reported by the lean-runner service.
+const (
+	RunnerSuccess = "success"
+	RunnerError   = "error"
+	RunnerTimeout = "timeout"
+)
+
+// Evaluate derives a verdict from the runner status, the diagnostics and
+// the axiom audit.
+func Evaluate(status string, diagnostics Diagnostics, audit AxiomAudit) string {
+	switch status {
+	case RunnerTimeout:
+		return VerdictTimeout
+	case RunnerSuccess:
+	default:
+		return VerdictFailed
+	}
+
+	if diagnostics.Count(SeverityError) > 0 {
+		return VerdictFailed
+	}
+	// A theorem without a report cannot be vouched for; something in the
+	// file kept its #print axioms from running. Lean's own sorry warning
+	// also covers declarations the audit does not name.
+	if audit.UsesSorry || len(audit.Nonstandard) > 0 || len(audit.Missing) > 0 || diagnostics.UsesSorry() {
+		return VerdictUnsound
+	}
+	return VerdictPassed
+}
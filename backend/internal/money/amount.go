@@ -0,0 +1,207 @@
+// Package money represents token amounts exactly. Amounts are integers of
+// base units with a decimal scale, so they never pass through float64 on
+// their way between the chain, the API and Postgres NUMERIC columns.
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Amount is an arbitrary-precision decimal. The zero value is 0.
+type Amount struct {
+	units *big.Int // never mutated once set
+	scale int      // number of digits after the decimal point
+}
+
+var ten = big.NewInt(10)
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(ten, big.NewInt(int64(n)), nil)
+}
+
+// FromUnits builds an amount from integer base units of a token with the
+// given number of decimals, e.g. FromUnits(1_500_000, 6) is 1.5 USDC.
+func FromUnits(units *big.Int, decimals int) Amount {
+	return Amount{units: new(big.Int).Set(units), scale: decimals}
+}
+
+// Parse reads a plain decimal string such as "150", "0.000001" or "-2.5".
+// Exponents, thousands separators and surrounding spaces are rejected.
+func Parse(s string) (Amount, error) {
+	digits := strings.TrimPrefix(s, "-")
+	negative := len(digits) != len(s)
+
+	whole, fraction, hasPoint := strings.Cut(digits, ".")
+	if whole == "" || (hasPoint && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return Amount{}, fmt.Errorf("invalid decimal amount %q", s)
+	}
+
+	units, _ := new(big.Int).SetString(whole+fraction, 10)
+	if negative {
+		units.Neg(units)
+	}
+	return Amount{units: units, scale: len(fraction)}, nil
+}
+
+// MustParse is Parse for constants; it panics on malformed input.
+func MustParse(s string) Amount {
+	amount, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (a Amount) unitsOrZero() *big.Int {
+	if a.units == nil {
+		return new(big.Int)
+	}
+	return a.units
+}
+
+// rescale returns a's units at a scale of at least a.scale.
+func (a Amount) rescale(scale int) *big.Int {
+	units := a.unitsOrZero()
+	if scale <= a.scale {
+		return units
+	}
+	return new(big.Int).Mul(units, pow10(scale-a.scale))
+}
+
+func (a Amount) Add(b Amount) Amount {
+	scale := max(a.scale, b.scale)
+	return Amount{units: new(big.Int).Add(a.rescale(scale), b.rescale(scale)), scale: scale}
+}
+
+// Mul returns the exact product, e.g. a token amount times its USD price.
+func (a Amount) Mul(b Amount) Amount {
+	return Amount{units: new(big.Int).Mul(a.unitsOrZero(), b.unitsOrZero()), scale: a.scale + b.scale}
+}
+
+// Cmp returns -1, 0 or +1 as a is less than, equal to or greater than b.
+func (a Amount) Cmp(b Amount) int {
+	scale := max(a.scale, b.scale)
+	return a.rescale(scale).Cmp(b.rescale(scale))
+}
+
+func (a Amount) Sign() int {
+	return a.unitsOrZero().Sign()
+}
+
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// Units converts the amount to base units of a token with the given number
+// of decimals. It fails rather than round when the amount is more precise
+// than the token allows.
+func (a Amount) Units(decimals int) (*big.Int, error) {
+	if decimals >= a.scale {
+		return a.rescale(decimals), nil
+	}
+
+	units, remainder := new(big.Int).QuoRem(a.unitsOrZero(), pow10(a.scale-decimals), new(big.Int))
+	if remainder.Sign() != 0 {
+		return nil, fmt.Errorf("amount %s has more than %d decimal places", a, decimals)
+	}
+	return units, nil
+}
+
+// String formats the amount without trailing fractional zeros, so equal
+// amounts always print the same way ("1.5", not "1.500000").
+func (a Amount) String() string {
+	units := a.unitsOrZero()
+	digits := new(big.Int).Abs(units).String()
+	sign := ""
+	if units.Sign() < 0 {
+		sign = "-"
+	}
+
+	if a.scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= a.scale {
+		digits = strings.Repeat("0", a.scale-len(digits)+1) + digits
+	}
+
+	whole, fraction := digits[:len(digits)-a.scale], strings.TrimRight(digits[len(digits)-a.scale:], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+// MarshalJSON encodes the amount as a JSON string so clients never parse it
+// into a float.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON accepts a decimal string or, for older clients, a bare JSON
+// number, which is read from its literal text rather than through float64.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*a = Amount{}
+		return nil
+	}
+
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+
+	amount, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Scan reads NUMERIC columns, which lib/pq returns as text.
+func (a *Amount) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*a = Amount{}
+		return nil
+	case []byte:
+		return a.scanText(string(value))
+	case string:
+		return a.scanText(value)
+	case int64:
+		*a = Amount{units: big.NewInt(value)}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into money.Amount", src)
+	}
+}
+
+func (a *Amount) scanText(text string) error {
+	amount, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Value stores the amount as its decimal text.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
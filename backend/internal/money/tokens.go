@@ -0,0 +1,114 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// USDCDecimals is the number of decimals of USDC on every supported chain.
+const USDCDecimals = 6
+
+// maxDecimals keeps 10^decimals within a uint256, the widest ERC-20 amount.
+const maxDecimals = 77
+
+// Token describes an ERC-20 that challenges can be funded with.
+type Token struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// TokenRegistry knows the symbol and decimals of reward tokens, keyed by
+// lowercase address. Challenge.token can be any ERC-20, so amounts reported
+// in base units are scaled by the decimals of the token they were paid in.
+type TokenRegistry struct {
+	tokens map[string]Token
+	usdc   string
+}
+
+// DefaultTokens lists the USDC deployments from the contract deploy scripts.
+var DefaultTokens = []Token{
+	{Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: USDCDecimals}, // Ethereum
+	{Address: "0x94a9d9ac8a22534e3faca9f4e7f2e2cf85d5e4c8", Symbol: "USDC", Decimals: USDCDecimals}, // Sepolia
+	{Address: "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913", Symbol: "USDC", Decimals: USDCDecimals}, // Base
+	{Address: "0x036cbd53842c5426634e7929541ec2318f3dcf7e", Symbol: "USDC", Decimals: USDCDecimals}, // Base Sepolia
+	{Address: "0x2791bca1f2de4661ed88a30c99a7a9449aa84174", Symbol: "USDC", Decimals: USDCDecimals}, // Polygon
+}
+
+// NewTokenRegistry builds a registry from tokens. usdcAddress names the USDC
+// deployment that events without a token address were paid in.
+func NewTokenRegistry(tokens []Token, usdcAddress string) (*TokenRegistry, error) {
+	registry := &TokenRegistry{
+		tokens: make(map[string]Token, len(tokens)),
+		usdc:   strings.ToLower(usdcAddress),
+	}
+
+	for _, token := range tokens {
+		if token.Address == "" || token.Symbol == "" {
+			return nil, fmt.Errorf("token %q: address and symbol are required", token.Address)
+		}
+		if token.Decimals < 0 || token.Decimals > maxDecimals {
+			return nil, fmt.Errorf("token %s: decimals must be between 0 and %d", token.Address, maxDecimals)
+		}
+		token.Address = strings.ToLower(token.Address)
+		registry.tokens[token.Address] = token
+	}
+
+	if _, exists := registry.tokens[registry.usdc]; !exists {
+		return nil, fmt.Errorf("USDC address %s is not a registered token", usdcAddress)
+	}
+
+	return registry, nil
+}
+
+// LoadTokenRegistry starts from DefaultTokens and adds or replaces entries
+// from the TOKENS environment variable, a JSON array of tokens. USDC_ADDRESS
+// selects the USDC deployment, defaulting to Ethereum mainnet as in
+// Deploy.s.sol.
+func LoadTokenRegistry() (*TokenRegistry, error) {
+	tokens := append([]Token(nil), DefaultTokens...)
+
+	if raw := os.Getenv("TOKENS"); raw != "" {
+		var extra []Token
+		if err := json.Unmarshal([]byte(raw), &extra); err != nil {
+			return nil, fmt.Errorf("invalid TOKENS: %w", err)
+		}
+		tokens = append(tokens, extra...)
+	}
+
+	usdcAddress := os.Getenv("USDC_ADDRESS")
+	if usdcAddress == "" {
+		usdcAddress = DefaultTokens[0].Address
+	}
+
+	return NewTokenRegistry(tokens, usdcAddress)
+}
+
+// Lookup finds a token by address, ignoring case.
+func (r *TokenRegistry) Lookup(address string) (Token, bool) {
+	token, exists := r.tokens[strings.ToLower(address)]
+	return token, exists
+}
+
+// USDC returns the configured USDC deployment.
+func (r *TokenRegistry) USDC() Token {
+	return r.tokens[r.usdc]
+}
+
+// Tokens lists every registered token ordered by symbol, then address.
+func (r *TokenRegistry) Tokens() []Token {
+	tokens := make([]Token, 0, len(r.tokens))
+	for _, token := range r.tokens {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].Symbol != tokens[j].Symbol {
+			return tokens[i].Symbol < tokens[j].Symbol
+		}
+		return tokens[i].Address < tokens[j].Address
+	})
+	return tokens
+}
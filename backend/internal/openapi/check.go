@@ -0,0 +1,300 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Route is a registered route, with the path in gin syntax such as
+// /api/submissions/:uid.
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Models maps schema names to the Go types serialized under them. Response
+// types are checked against json tags: a property is required exactly
+// when its field has no omitempty. Request types are checked against
+// binding tags: a property is required exactly when binding requires it.
+type Models struct {
+	Responses map[string]reflect.Type
+	Requests  map[string]reflect.Type
+}
+
+// Check compares the document with the server's routes and types and
+// returns an error listing every difference.
+func (d *Document) Check(routes []Route, models Models) error {
+	problems := d.CheckRoutes(routes)
+	problems = append(problems, d.CheckModels(models)...)
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// CheckRoutes reports routes the document does not describe and operations
+// no route serves.
+func (d *Document) CheckRoutes(routes []Route) []string {
+	registered := make(map[Route]bool)
+	for _, route := range routes {
+		if route.Method == "HEAD" || route.Method == "OPTIONS" {
+			continue
+		}
+		registered[Route{Method: route.Method, Path: templatePath(route.Path)}] = true
+	}
+
+	documented := make(map[Route]bool)
+	for path, item := range d.Paths {
+		for method := range item {
+			documented[Route{Method: strings.ToUpper(method), Path: path}] = true
+		}
+	}
+
+	var problems []string
+	for route := range registered {
+		if !documented[route] {
+			problems = append(problems, fmt.Sprintf("route %s %s is not in the spec", route.Method, route.Path))
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			problems = append(problems, fmt.Sprintf("spec operation %s %s has no route", route.Method, route.Path))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// templatePath turns gin's :name and *name segments into {name}.
+func templatePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// CheckModels compares each named schema with its Go type, and every
+// schema those reference through struct fields with the field's type.
+func (d *Document) CheckModels(models Models) []string {
+	c := &typeChecker{doc: d, seen: make(map[string]bool)}
+	for _, name := range sortedNames(models.Responses) {
+		c.check(name, models.Responses[name], false)
+	}
+	for _, name := range sortedNames(models.Requests) {
+		c.check(name, models.Requests[name], true)
+	}
+	return c.problems
+}
+
+func sortedNames(types map[string]reflect.Type) []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type typeChecker struct {
+	doc      *Document
+	seen     map[string]bool
+	problems []string
+}
+
+func (c *typeChecker) fail(format string, args ...interface{}) {
+	c.problems = append(c.problems, fmt.Sprintf(format, args...))
+}
+
+func (c *typeChecker) check(name string, t reflect.Type, request bool) {
+	key := fmt.Sprintf("%s %v %t", name, t, request)
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+
+	schema, ok := c.doc.Components.Schemas[name]
+	if !ok {
+		c.fail("schema %s is not in the spec", name)
+		return
+	}
+	t = deref(t)
+	if t.Kind() != reflect.Struct {
+		c.fail("schema %s maps to %v, which is not a struct", name, t)
+		return
+	}
+
+	fields := jsonFields(t)
+	for prop := range schema.Properties {
+		if _, ok := fields[prop]; !ok {
+			c.fail("schema %s has property %s, which %v does not have", name, prop, t)
+		}
+	}
+	for _, fieldName := range sortedFieldNames(fields) {
+		field := fields[fieldName]
+		prop, ok := schema.Properties[fieldName]
+		if !ok {
+			c.fail("schema %s lacks property %s of %v", name, fieldName, t)
+			continue
+		}
+
+		required := !field.omitEmpty
+		if request {
+			required = field.bindingRequired
+		}
+		if required != schema.IsRequired(fieldName) {
+			c.fail("schema %s: property %s should be required=%t to match %v", name, fieldName, required, t)
+		}
+		if !request && field.typ.Kind() == reflect.Ptr && !field.omitEmpty && !prop.Nullable() {
+			c.fail("schema %s: property %s can be null in %v but is not nullable", name, fieldName, t)
+		}
+		c.checkValue(name+"."+fieldName, prop, field.typ, request)
+	}
+}
+
+// checkValue compares a property schema with a field type: the JSON kinds
+// must agree, and referenced schemas are checked against the struct type.
+func (c *typeChecker) checkValue(where string, prop *Schema, t reflect.Type, request bool) {
+	prop = prop.NonNull()
+	t = deref(t)
+
+	if prop.Ref != "" {
+		name := RefName(prop.Ref)
+		target, ok := c.doc.Components.Schemas[name]
+		if !ok {
+			c.fail("%s refers to missing schema %s", where, name)
+			return
+		}
+		if t.Kind() == reflect.Struct && jsonKind(t) == "object" {
+			c.check(name, t, request)
+			return
+		}
+		prop = target.NonNull()
+	}
+
+	want := jsonKind(t)
+	got := prop.Type()
+	if want == "" || got == "" {
+		return
+	}
+	// Every integer is a number, but not the other way round.
+	if want != got && !(got == "number" && want == "integer") {
+		c.fail("%s is %s in the spec but Go type %v encodes as %s", where, got, t, want)
+		return
+	}
+
+	if want == "array" && prop.Items != nil && t.Kind() != reflect.String {
+		c.checkValue(where+"[]", prop.Items, t.Elem(), request)
+	}
+}
+
+type jsonField struct {
+	typ             reflect.Type
+	omitEmpty       bool
+	bindingRequired bool
+}
+
+// jsonFields lists the members encoding/json emits for a struct, flattening
+// embedded structs the way it does.
+func jsonFields(t reflect.Type) map[string]jsonField {
+	fields := make(map[string]jsonField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && deref(field.Type).Kind() == reflect.Struct {
+			for embeddedName, embedded := range jsonFields(deref(field.Type)) {
+				if _, shadowed := fields[embeddedName]; !shadowed {
+					fields[embeddedName] = embedded
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = jsonField{
+			typ:             field.Type,
+			omitEmpty:       strings.Contains(","+options+",", ",omitempty,"),
+			bindingRequired: strings.HasPrefix(field.Tag.Get("binding"), "required"),
+		}
+	}
+	return fields
+}
+
+func sortedFieldNames(fields map[string]jsonField) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// jsonKind is the JSON type a value of t encodes as, or "" when it cannot
+// be told from the type. Types with their own MarshalJSON are judged by
+// encoding their zero value.
+func jsonKind(t reflect.Type) string {
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		encoded, err := json.Marshal(reflect.New(t).Interface())
+		if err != nil || len(encoded) == 0 {
+			return ""
+		}
+		switch encoded[0] {
+		case '"':
+			return "string"
+		case '[':
+			return "array"
+		case '{':
+			return "object"
+		case 't', 'f':
+			return "boolean"
+		case 'n':
+			return ""
+		}
+		return "number"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		return "array"
+	case reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+// Package openapi holds the OpenAPI 3.1 description of the HTTP API and
+// checks it against the routes and response types the server actually
+// has. The document is maintained by hand; the Go client in package client
+// is generated from it.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+// JSON returns the document as served at /api/openapi.json.
+func JSON() []byte {
+	return spec
+}
+
+// Document is the subset of an OpenAPI document the checks and the client
+// generator read.
+type Document struct {
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Operation is one method on a path.
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description"`
+	Parameters  []Parameter          `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Content map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON Schema as OpenAPI 3.1 uses it. Types holds the type
+// keyword, which may be a single name or a list such as ["string", "null"].
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Types                TypeList           `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Enum                 []interface{}      `json:"enum"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	OneOf                []*Schema          `json:"oneOf"`
+}
+
+// TypeList is the type keyword of a schema.
+type TypeList []string
+
+func (t *TypeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = TypeList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Load parses the embedded document.
+func Load() (*Document, error) {
+	return Parse(spec)
+}
+
+// Parse parses an OpenAPI document.
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse OpenAPI document: %w", err)
+	}
+	return &doc, nil
+}
+
+// RefName returns the schema name a local $ref points at.
+func RefName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// Nullable reports whether the schema allows null, either in its type list
+// or as a oneOf branch.
+func (s *Schema) Nullable() bool {
+	for _, t := range s.Types {
+		if t == "null" {
+			return true
+		}
+	}
+	for _, branch := range s.OneOf {
+		if len(branch.Types) == 1 && branch.Types[0] == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// NonNull returns the schema without its null alternative: the single
+// non-null type, or the one non-null oneOf branch.
+func (s *Schema) NonNull() *Schema {
+	if len(s.OneOf) == 2 && s.Nullable() {
+		for _, branch := range s.OneOf {
+			if !(len(branch.Types) == 1 && branch.Types[0] == "null") {
+				return branch
+			}
+		}
+	}
+	if len(s.Types) < 2 {
+		return s
+	}
+	copied := *s
+	copied.Types = nil
+	for _, t := range s.Types {
+		if t != "null" {
+			copied.Types = append(copied.Types, t)
+		}
+	}
+	return &copied
+}
+
+// Type returns the schema's single non-null type, or "" when it has none
+// or several.
+func (s *Schema) Type() string {
+	types := s.NonNull().Types
+	if len(types) != 1 {
+		return ""
+	}
+	return types[0]
+}
+
+// IsRequired reports whether name is a required property.
+func (s *Schema) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Methods lists a path's methods in a stable order.
+func Methods(item map[string]*Operation) []string {
+	methods := make([]string, 0, len(item))
+	for method := range item {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
@@ -0,0 +1,60 @@
+// Package pricing converts token amounts to USD for display. Prices never
+// feed into reputation, which the contracts compute from raw amounts.
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cyrup/backend/internal/money"
+)
+
+// PriceSource quotes the USD price of one whole token. ok is false when the
+// source has no price for the token.
+type PriceSource interface {
+	USDPrice(ctx context.Context, tokenAddress string) (price money.Amount, ok bool, err error)
+}
+
+// StaticPrices is a fixed price list keyed by lowercase token address.
+type StaticPrices map[string]money.Amount
+
+func (p StaticPrices) USDPrice(ctx context.Context, tokenAddress string) (money.Amount, bool, error) {
+	price, ok := p[strings.ToLower(tokenAddress)]
+	return price, ok, nil
+}
+
+// LoadStaticPrices reads a JSON object of token address to USD price, with
+// prices written as decimal strings: {"0xa0b8...": "1.00"}.
+func LoadStaticPrices(path string) (StaticPrices, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]money.Amount
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid price file %s: %w", path, err)
+	}
+
+	prices := make(StaticPrices, len(raw))
+	for address, price := range raw {
+		if price.Sign() < 0 {
+			return nil, fmt.Errorf("invalid price file %s: negative price for %s", path, address)
+		}
+		prices[strings.ToLower(address)] = price
+	}
+	return prices, nil
+}
+
+// LoadPriceSource returns the price source configured by PRICE_FILE, or nil
+// when USD conversion is disabled.
+func LoadPriceSource() (PriceSource, error) {
+	path := os.Getenv("PRICE_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	return LoadStaticPrices(path)
+}
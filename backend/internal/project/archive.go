@@ -0,0 +1,132 @@
+package project
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// FromArchive unpacks a tar, tar.gz or zip archive and checks its files
+// like New. Only .lean files are taken, so an archive of a whole Lake
+// workspace works: directories, other files, anything under a dot
+// directory such as .lake and the root lakefile.lean are skipped.
+func FromArchive(data []byte, entry string, limits Limits) (*Project, error) {
+	if len(data) > limits.MaxBytes {
+		return nil, &LimitError{Limit: limits.MaxBytes, Detail: fmt.Sprintf("Archive is %d bytes; the limit is %d", len(data), limits.MaxBytes)}
+	}
+
+	u := &unpacker{files: make(map[string]string), limits: limits}
+	var err error
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		err = u.zip(data)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		var gz *gzip.Reader
+		if gz, err = gzip.NewReader(bytes.NewReader(data)); err == nil {
+			err = u.tar(gz)
+		}
+	default:
+		err = u.tar(bytes.NewReader(data))
+	}
+	if err != nil {
+		var limit *LimitError
+		if errors.As(err, &limit) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("archive could not be read: %w", err)
+	}
+	return New(u.files, entry, limits)
+}
+
+// unpacker collects an archive's files, enforcing the limits as it goes so
+// that a small archive cannot expand without bound.
+type unpacker struct {
+	files  map[string]string
+	limits Limits
+	total  int
+}
+
+func (u *unpacker) tar(r io.Reader) error {
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := u.add(header.Name, archive); err != nil {
+			return err
+		}
+	}
+}
+
+func (u *unpacker) zip(data []byte) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, file := range archive.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = u.add(file.Name, content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *unpacker) add(name string, r io.Reader) error {
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+	if skipped(name) {
+		return nil
+	}
+	if _, duplicate := u.files[name]; duplicate {
+		return fmt.Errorf("archive has %s twice", name)
+	}
+	if len(u.files) == u.limits.MaxFiles {
+		return &LimitError{Limit: u.limits.MaxFiles, Detail: fmt.Sprintf("Project has more than %d files", u.limits.MaxFiles)}
+	}
+
+	remaining := u.limits.MaxBytes - u.total
+	content, err := io.ReadAll(io.LimitReader(r, int64(remaining)+1))
+	if err != nil {
+		return err
+	}
+	if len(content) > remaining {
+		return &LimitError{Limit: u.limits.MaxBytes, Detail: fmt.Sprintf("Project is more than %d bytes unpacked", u.limits.MaxBytes)}
+	}
+	u.total += len(content)
+	u.files[name] = string(content)
+	return nil
+}
+
+// skipped reports whether an archive member is left out of the project.
+func skipped(name string) bool {
+	if !strings.HasSuffix(name, ".lean") || name == "lakefile.lean" {
+		return true
+	}
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+// Package project checks multi-file Lean projects sent for verification and
+// unpacks them from tar, tar.gz or zip archives. A project is a set of .lean
+// files keyed by their path from the workspace root, where A/B.lean holds
+// module A.B, plus the entry module the build starts from. The lean runner
+// supplies the lakefile, so projects cannot bring their own.
+package project
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultMaxFiles = 32
+	DefaultMaxBytes = 256 << 10
+)
+
+// Limits caps the size of a project.
+type Limits struct {
+	// MaxFiles caps the number of .lean files.
+	MaxFiles int
+	// MaxBytes caps the total size of the files, and of an archive before
+	// it is unpacked.
+	MaxBytes int
+}
+
+// LoadLimits reads MAX_PROJECT_FILES and MAX_PROJECT_BYTES.
+func LoadLimits() (Limits, error) {
+	limits := Limits{MaxFiles: DefaultMaxFiles, MaxBytes: DefaultMaxBytes}
+
+	if raw := os.Getenv("MAX_PROJECT_FILES"); raw != "" {
+		files, err := strconv.Atoi(raw)
+		if err != nil || files <= 0 {
+			return limits, fmt.Errorf("invalid MAX_PROJECT_FILES %q", raw)
+		}
+		limits.MaxFiles = files
+	}
+	if raw := os.Getenv("MAX_PROJECT_BYTES"); raw != "" {
+		bytes, err := strconv.Atoi(raw)
+		if err != nil || bytes <= 0 {
+			return limits, fmt.Errorf("invalid MAX_PROJECT_BYTES %q", raw)
+		}
+		limits.MaxBytes = bytes
+	}
+	return limits, nil
+}
+
+// Project is a checked set of Lean files.
+type Project struct {
+	Files map[string]string
+	Entry string
+}
+
+// LimitError is returned for a project over one of its limits.
+type LimitError struct {
+	Limit  int
+	Detail string
+}
+
+func (e *LimitError) Error() string {
+	return e.Detail
+}
+
+// New checks files and entry against limits. The entry may be left empty
+// when there is only one file.
+func New(files map[string]string, entry string, limits Limits) (*Project, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("project has no files")
+	}
+	if len(files) > limits.MaxFiles {
+		return nil, &LimitError{Limit: limits.MaxFiles, Detail: fmt.Sprintf("Project has %d files; the limit is %d", len(files), limits.MaxFiles)}
+	}
+
+	total := 0
+	modules := make(map[string]bool, len(files))
+	for p, content := range files {
+		module, err := ModuleName(p)
+		if err != nil {
+			return nil, err
+		}
+		modules[module] = true
+		total += len(content)
+	}
+	if total > limits.MaxBytes {
+		return nil, &LimitError{Limit: limits.MaxBytes, Detail: fmt.Sprintf("Project is %d bytes; the limit is %d", total, limits.MaxBytes)}
+	}
+
+	if entry == "" {
+		if len(files) > 1 {
+			return nil, fmt.Errorf("entry is required for a project with more than one file")
+		}
+		for p := range files {
+			entry, _ = ModuleName(p)
+		}
+	}
+	if !modules[entry] {
+		return nil, fmt.Errorf("entry module %s is not in the project; expected one of %s", entry, strings.Join(sortedModules(modules), ", "))
+	}
+	return &Project{Files: files, Entry: entry}, nil
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ModuleName returns the module a file path holds: A/B.lean holds A.B. The
+// path must be clean and relative, and each of its parts an identifier.
+func ModuleName(p string) (string, error) {
+	if p == "" || strings.Contains(p, `\`) || path.IsAbs(p) || path.Clean(p) != p || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("file path %q must be clean and relative", p)
+	}
+	if !strings.HasSuffix(p, ".lean") {
+		return "", fmt.Errorf("file %q is not a .lean file", p)
+	}
+	if p == "lakefile.lean" {
+		return "", fmt.Errorf("lakefile.lean is provided by the lean runner")
+	}
+
+	parts := strings.Split(strings.TrimSuffix(p, ".lean"), "/")
+	for _, part := range parts {
+		if !identifier.MatchString(part) {
+			return "", fmt.Errorf("file path %q does not name a module: %q is not an identifier", p, part)
+		}
+	}
+	return strings.Join(parts, "."), nil
+}
+
+func sortedModules(modules map[string]bool) []string {
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
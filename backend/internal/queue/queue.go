@@ -0,0 +1,180 @@
+// Package queue runs lean runner jobs on a fixed pool of workers. Jobs wait
+// in two lanes: interactive jobs, such as single verifications and
+// submission checks, and batch jobs, grouped by batch. When both lanes have
+// work the workers alternate between them, and batch jobs are taken
+// round-robin across batches, so a large batch neither starves interactive
+// requests nor holds up batches submitted after it.
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+)
+
+const (
+	DefaultWorkers       = 4
+	DefaultMaxBatchItems = 100
+)
+
+// Config is the queue configuration read from the environment.
+type Config struct {
+	// Workers is how many jobs run on the lean runner at once.
+	Workers int
+	// MaxBatchItems caps the items in one batch request.
+	MaxBatchItems int
+}
+
+// LoadConfig reads VERIFY_WORKERS and VERIFY_BATCH_MAX_ITEMS.
+func LoadConfig() (Config, error) {
+	config := Config{Workers: DefaultWorkers, MaxBatchItems: DefaultMaxBatchItems}
+
+	if raw := os.Getenv("VERIFY_WORKERS"); raw != "" {
+		workers, err := strconv.Atoi(raw)
+		if err != nil || workers <= 0 {
+			return config, fmt.Errorf("invalid VERIFY_WORKERS %q", raw)
+		}
+		config.Workers = workers
+	}
+	if raw := os.Getenv("VERIFY_BATCH_MAX_ITEMS"); raw != "" {
+		items, err := strconv.Atoi(raw)
+		if err != nil || items <= 0 {
+			return config, fmt.Errorf("invalid VERIFY_BATCH_MAX_ITEMS %q", raw)
+		}
+		config.MaxBatchItems = items
+	}
+	return config, nil
+}
+
+// Queue holds jobs until a worker is free. Jobs submitted before Run are
+// kept and start once it is called.
+type Queue struct {
+	workers int
+
+	mu          sync.Mutex
+	wake        *sync.Cond
+	interactive []func()
+	groups      map[string][]func()
+	// ring lists the groups with pending jobs in the order they are served.
+	ring      []string
+	batchTurn bool
+	closed    bool
+}
+
+func New(workers int) *Queue {
+	q := &Queue{workers: workers, groups: make(map[string][]func())}
+	q.wake = sync.NewCond(&q.mu)
+	return q
+}
+
+// Submit queues a job in the interactive lane.
+func (q *Queue) Submit(job func()) {
+	q.mu.Lock()
+	q.interactive = append(q.interactive, job)
+	q.mu.Unlock()
+	q.wake.Signal()
+}
+
+// SubmitBatch queues jobs in the batch lane under group, which takes turns
+// with the other groups one job at a time.
+func (q *Queue) SubmitBatch(group string, jobs ...func()) {
+	if len(jobs) == 0 {
+		return
+	}
+
+	q.mu.Lock()
+	if _, pending := q.groups[group]; !pending {
+		q.ring = append(q.ring, group)
+	}
+	q.groups[group] = append(q.groups[group], jobs...)
+	q.mu.Unlock()
+	q.wake.Broadcast()
+}
+
+// Pending returns how many jobs are waiting in each lane.
+func (q *Queue) Pending() (interactive, batch int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, jobs := range q.groups {
+		batch += len(jobs)
+	}
+	return len(q.interactive), batch
+}
+
+// Run starts the workers and blocks until ctx is done and running jobs have
+// finished. Jobs still waiting are dropped.
+func (q *Queue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, ok := q.next()
+				if !ok {
+					return
+				}
+				run(job)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.wake.Broadcast()
+	wg.Wait()
+}
+
+// next waits for a job, alternating lanes while both have work.
+func (q *Queue) next() (func(), bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		if q.closed {
+			return nil, false
+		}
+
+		hasInteractive, hasBatch := len(q.interactive) > 0, len(q.ring) > 0
+		switch {
+		case hasInteractive && (!hasBatch || !q.batchTurn):
+			job := q.interactive[0]
+			q.interactive[0] = nil
+			q.interactive = q.interactive[1:]
+			q.batchTurn = true
+			return job, true
+		case hasBatch:
+			group := q.ring[0]
+			jobs := q.groups[group]
+			job := jobs[0]
+			q.ring = q.ring[1:]
+			if len(jobs) == 1 {
+				delete(q.groups, group)
+			} else {
+				q.groups[group] = jobs[1:]
+				q.ring = append(q.ring, group)
+			}
+			q.batchTurn = false
+			return job, true
+		}
+
+		q.wake.Wait()
+	}
+}
+
+// run keeps a panicking job from taking its worker down with it.
+func run(job func()) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("Warning: queued job panicked: %v\n%s", recovered, debug.Stack())
+		}
+	}()
+	job()
+}
@@ -0,0 +1,243 @@
+// Package ratelimit implements token-bucket rate limits. Buckets are kept
+// in a database.RateLimitRepository, so with Postgres every API replica
+// draws from the same buckets.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+)
+
+// Budgets, each with its own buckets.
+const (
+	// BudgetVerify covers the endpoints that run code on the lean runner.
+	BudgetVerify = "verify"
+	// BudgetRead covers the read endpoints.
+	BudgetRead = "read"
+	// BudgetBatch covers batch verification, charged per item.
+	BudgetBatch = "batch"
+)
+
+const (
+	defaultVerify       = "10/1m"
+	defaultRead         = "300/1m"
+	defaultBatch        = "100/10m"
+	DefaultMaxCodeBytes = 64 << 10
+)
+
+// Limit allows Burst requests at once, refilled evenly over Per. The zero
+// Limit is unlimited.
+type Limit struct {
+	Burst int
+	Per   time.Duration
+}
+
+// ParseLimit reads a limit written as "<requests>/<duration>", e.g. "10/1m",
+// or "off" for no limit.
+func ParseLimit(raw string) (Limit, error) {
+	if raw == "off" {
+		return Limit{}, nil
+	}
+
+	count, period, found := strings.Cut(raw, "/")
+	burst, err := strconv.Atoi(count)
+	if !found || err != nil || burst <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected e.g. 10/1m", raw)
+	}
+	per, err := time.ParseDuration(period)
+	if err != nil || per <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected e.g. 10/1m", raw)
+	}
+	return Limit{Burst: burst, Per: per}, nil
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Burst == 0
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, l.Per)
+}
+
+// interval is the time it takes to refill one token.
+func (l Limit) interval() time.Duration {
+	return l.Per / time.Duration(l.Burst)
+}
+
+// Decision is the outcome of taking a token.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until enough tokens are available again. Zero
+	// when they already are.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full.
+	Reset time.Duration
+}
+
+// take refills the bucket for the time since it was last used and takes
+// cost tokens if there are that many.
+func take(bucket *database.RateBucket, limit Limit, cost int, now time.Time) Decision {
+	burst := float64(limit.Burst)
+	tokens := burst
+	if !bucket.UpdatedAt.IsZero() {
+		elapsed := now.Sub(bucket.UpdatedAt)
+		tokens = math.Min(burst, bucket.Tokens+elapsed.Seconds()/limit.interval().Seconds())
+	}
+
+	decision := Decision{Limit: limit.Burst}
+	if tokens >= float64(cost) {
+		tokens -= float64(cost)
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = time.Duration((float64(cost) - tokens) * float64(limit.interval()))
+	}
+	decision.Remaining = int(tokens)
+	decision.Reset = time.Duration((burst - tokens) * float64(limit.interval()))
+
+	bucket.Tokens = tokens
+	bucket.UpdatedAt = now
+	return decision
+}
+
+// Config is the limiter configuration read from the environment.
+type Config struct {
+	Verify       Limit
+	Read         Limit
+	Batch        Limit
+	MaxCodeBytes int
+}
+
+// LoadConfig reads RATE_LIMIT_VERIFY, RATE_LIMIT_READ, RATE_LIMIT_BATCH and
+// MAX_CODE_BYTES.
+func LoadConfig() (Config, error) {
+	var config Config
+	var err error
+
+	if config.Verify, err = ParseLimit(envOr("RATE_LIMIT_VERIFY", defaultVerify)); err != nil {
+		return config, fmt.Errorf("RATE_LIMIT_VERIFY: %w", err)
+	}
+	if config.Read, err = ParseLimit(envOr("RATE_LIMIT_READ", defaultRead)); err != nil {
+		return config, fmt.Errorf("RATE_LIMIT_READ: %w", err)
+	}
+	if config.Batch, err = ParseLimit(envOr("RATE_LIMIT_BATCH", defaultBatch)); err != nil {
+		return config, fmt.Errorf("RATE_LIMIT_BATCH: %w", err)
+	}
+
+	config.MaxCodeBytes = DefaultMaxCodeBytes
+	if raw := os.Getenv("MAX_CODE_BYTES"); raw != "" {
+		if config.MaxCodeBytes, err = strconv.Atoi(raw); err != nil || config.MaxCodeBytes <= 0 {
+			return config, fmt.Errorf("invalid MAX_CODE_BYTES %q", raw)
+		}
+	}
+
+	return config, nil
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Limiter applies the configured budgets.
+type Limiter struct {
+	buckets database.RateLimitRepository
+	limits  map[string]Limit
+}
+
+func New(buckets database.RateLimitRepository, config Config) *Limiter {
+	return &Limiter{
+		buckets: buckets,
+		limits: map[string]Limit{
+			BudgetVerify: config.Verify,
+			BudgetRead:   config.Read,
+			BudgetBatch:  config.Batch,
+		},
+	}
+}
+
+// Allow takes cost tokens from the budget's bucket for each key, e.g. the
+// caller's IP and wallet. The request is allowed only if every bucket has
+// enough, and only then are tokens taken from any of them, so a request one
+// bucket refuses costs the others nothing. The returned decision is the most
+// restrictive one. ok is false when the budget is unlimited.
+func (l *Limiter) Allow(ctx context.Context, budget string, cost int, keys ...string) (decision Decision, ok bool, err error) {
+	limit := l.limits[budget]
+	if limit.Unlimited() {
+		return Decision{Allowed: true}, false, nil
+	}
+
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = budget + ":" + key
+	}
+
+	now := time.Now().UTC()
+	err = l.buckets.UpdateRateBuckets(ctx, names, func(buckets []*database.RateBucket) {
+		taken := make([]database.RateBucket, len(buckets))
+		for i, bucket := range buckets {
+			taken[i] = *bucket
+			if d := take(&taken[i], limit, cost, now); i == 0 || stricter(d, decision) {
+				decision = d
+			}
+		}
+		if decision.Allowed {
+			for i, bucket := range buckets {
+				*bucket = taken[i]
+			}
+		}
+	})
+	if err != nil {
+		return Decision{}, false, err
+	}
+	return decision, true, nil
+}
+
+func stricter(a, b Decision) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+// Run prunes idle buckets every interval until ctx is cancelled. A bucket
+// untouched for its budget's whole refill period is full, so dropping it
+// changes nothing.
+func (l *Limiter) Run(ctx context.Context, interval time.Duration) {
+	var idle time.Duration
+	for _, limit := range l.limits {
+		idle = max(idle, limit.Per)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := l.buckets.DeleteIdleRateBuckets(ctx, time.Now().UTC().Add(-idle)); err != nil {
+			log.Printf("Warning: failed to prune rate limit buckets: %v", err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package reputation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+)
+
+// Tier mirrors ReputationSystem.Tier. Amounts up to and including MaxAmount
+// (in USDC base units) earn the tier's points. A MaxAmount of zero marks the
+// open-ended top tier, standing in for type(uint96).max on-chain.
+type Tier struct {
+	MaxAmount      uint64 `json:"max_amount"`
+	WinnerPoints   int    `json:"winner_points"`
+	VerifierPoints int    `json:"verifier_points"`
+}
+
+// TierTable is an ordered list of tiers, lowest boundary first.
+type TierTable []Tier
+
+// DefaultTiers matches the tiers hard-coded in ReputationSystem.calculatePoints.
+var DefaultTiers = TierTable{
+	{MaxAmount: 100e6, WinnerPoints: 10, VerifierPoints: 2},
+	{MaxAmount: 500e6, WinnerPoints: 50, VerifierPoints: 10},
+	{MaxAmount: 1000e6, WinnerPoints: 100, VerifierPoints: 20},
+	{MaxAmount: 0, WinnerPoints: 200, VerifierPoints: 40},
+}
+
+// LoadTiers returns the tier table from the REPUTATION_TIERS environment
+// variable (a JSON array of tiers), or DefaultTiers when it is unset. The
+// override exists for test deployments whose ReputationSystem was built
+// with different constants.
+func LoadTiers() (TierTable, error) {
+	raw := os.Getenv("REPUTATION_TIERS")
+	if raw == "" {
+		return DefaultTiers, nil
+	}
+
+	var tiers TierTable
+	if err := json.Unmarshal([]byte(raw), &tiers); err != nil {
+		return nil, fmt.Errorf("invalid REPUTATION_TIERS: %w", err)
+	}
+	if err := tiers.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid REPUTATION_TIERS: %w", err)
+	}
+
+	return tiers, nil
+}
+
+// Validate checks that boundaries are strictly increasing and that only the
+// last tier is open-ended.
+func (t TierTable) Validate() error {
+	if len(t) == 0 {
+		return fmt.Errorf("at least one tier is required")
+	}
+
+	var previous uint64
+	for i, tier := range t {
+		last := i == len(t)-1
+		if tier.MaxAmount == 0 && !last {
+			return fmt.Errorf("tier %d: only the last tier may be open-ended", i)
+		}
+		if tier.MaxAmount != 0 && last {
+			return fmt.Errorf("tier %d: the last tier must be open-ended (max_amount 0)", i)
+		}
+		if !last && tier.MaxAmount <= previous {
+			return fmt.Errorf("tier %d: max_amount must be greater than the previous tier", i)
+		}
+		if tier.WinnerPoints < 0 || tier.VerifierPoints < 0 {
+			return fmt.Errorf("tier %d: points must not be negative", i)
+		}
+		previous = tier.MaxAmount
+	}
+
+	return nil
+}
+
+// CalculatePoints is the Go counterpart of ReputationSystem.calculatePoints.
+// usdcAmount is the raw amount passed to updateReputation, which the
+// contract compares against 6-decimal USDC boundaries whatever the token.
+func (t TierTable) CalculatePoints(usdcAmount uint64, isVerifier bool) int {
+	for _, tier := range t {
+		if tier.MaxAmount == 0 || usdcAmount <= tier.MaxAmount {
+			if isVerifier {
+				return tier.VerifierPoints
+			}
+			return tier.WinnerPoints
+		}
+	}
+	return 0
+}
+
+// TierAmount converts an on-chain uint256 amount to the uint64 that
+// CalculatePoints takes. Anything wider than 64 bits is already deep in the
+// open-ended top tier, so it saturates rather than wraps.
+func TierAmount(units *big.Int) uint64 {
+	if units.Sign() <= 0 {
+		return 0
+	}
+	if !units.IsUint64() {
+		return math.MaxUint64
+	}
+	return units.Uint64()
+}
@@ -0,0 +1,94 @@
+package reputation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Season is a named competition period. Standings inside a season are
+// computed from the events it covers, so starting a new season resets the
+// rankings without touching all-time totals.
+type Season struct {
+	ID    string    `json:"id"`
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Window is a half-open time range [Start, End) used to rank points earned
+// within it.
+type Window struct {
+	Label string    `json:"label"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+const maxRollingWindowDays = 366
+
+// LoadSeasons reads season definitions from the JSON file named by
+// SEASONS_FILE, falling back to inline JSON in SEASONS. No seasons are
+// configured when both are unset.
+func LoadSeasons() ([]Season, error) {
+	raw := []byte(os.Getenv("SEASONS"))
+	if path := os.Getenv("SEASONS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SEASONS_FILE: %w", err)
+		}
+		raw = data
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var seasons []Season
+	if err := json.Unmarshal(raw, &seasons); err != nil {
+		return nil, fmt.Errorf("invalid season definitions: %w", err)
+	}
+
+	seen := make(map[string]bool, len(seasons))
+	for _, season := range seasons {
+		if season.ID == "" {
+			return nil, fmt.Errorf("season is missing an id")
+		}
+		if seen[season.ID] {
+			return nil, fmt.Errorf("duplicate season id %q", season.ID)
+		}
+		if !season.End.After(season.Start) {
+			return nil, fmt.Errorf("season %q must end after it starts", season.ID)
+		}
+		seen[season.ID] = true
+	}
+
+	return seasons, nil
+}
+
+// ParseWindow interprets the leaderboard window query parameter: a rolling
+// window such as "7d" or "30d" ending at now, or "season:<id>".
+func ParseWindow(value string, seasons []Season, now time.Time) (Window, error) {
+	if id, ok := strings.CutPrefix(value, "season:"); ok {
+		for _, season := range seasons {
+			if season.ID == id {
+				return Window{Label: value, Start: season.Start, End: season.End}, nil
+			}
+		}
+		return Window{}, fmt.Errorf("unknown season %q", id)
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 && n <= maxRollingWindowDays {
+			return Window{
+				Label: value,
+				Start: now.AddDate(0, 0, -n),
+				End:   now,
+			}, nil
+		}
+	}
+
+	return Window{}, fmt.Errorf("window must be <days>d (1-%d) or season:<id>", maxRollingWindowDays)
+}
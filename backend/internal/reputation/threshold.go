@@ -0,0 +1,64 @@
+package reputation
+
+// Constants mirrored from ReputationSystem.sol.
+const (
+	MaxLeaderboardSize = 100
+	TopPercentage      = 10
+)
+
+// Top10Threshold reproduces ReputationSystem._updateThreshold. topPoints
+// holds the point totals of the top performers in descending order; only
+// the first MaxLeaderboardSize entries are consulted, as on-chain. Where the
+// contract leaves its threshold alone, with no users or no top performers
+// to read it from, previous is returned.
+func Top10Threshold(topPoints []int, totalUsers, previous int) int {
+	if totalUsers == 0 {
+		return previous
+	}
+	if len(topPoints) > MaxLeaderboardSize {
+		topPoints = topPoints[:MaxLeaderboardSize]
+	}
+
+	if totalUsers <= 100/TopPercentage {
+		if len(topPoints) > 0 {
+			return topPoints[0]
+		}
+		return 1
+	}
+
+	position := totalUsers * TopPercentage / 100
+	if position < len(topPoints) {
+		return topPoints[position]
+	}
+	if len(topPoints) > 0 {
+		return topPoints[len(topPoints)-1]
+	}
+	return previous
+}
+
+// Qualification describes how a wallet compares to the verifier threshold.
+type Qualification struct {
+	WalletAddress     string  `json:"wallet_address"`
+	Points            int     `json:"points"`
+	Threshold         int     `json:"threshold"`
+	ThresholdSource   string  `json:"threshold_source"`
+	ComputedThreshold int     `json:"computed_threshold"`
+	Gap               int     `json:"gap"`
+	Qualified         bool    `json:"qualified"`
+	Percentile        float64 `json:"percentile"`
+	TotalUsers        int     `json:"total_users"`
+}
+
+// Qualify mirrors ReputationSystem.isQualifiedVerifier, which accepts any
+// wallet whose points are at least the threshold. Percentile is the share
+// of users with strictly fewer points than the wallet.
+func Qualify(points, threshold, usersBelow, totalUsers int) (qualified bool, gap int, percentile float64) {
+	qualified = points >= threshold
+	if !qualified {
+		gap = threshold - points
+	}
+	if totalUsers > 0 {
+		percentile = float64(usersBelow) * 100 / float64(totalUsers)
+	}
+	return qualified, gap, percentile
+}
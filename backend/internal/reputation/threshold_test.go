@@ -0,0 +1,39 @@
+package reputation
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestTop10ThresholdParity runs the threshold cases of the shared parity
+// fixture.
+func TestTop10ThresholdParity(t *testing.T) {
+	raw, err := os.ReadFile("../../test/points_parity.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture struct {
+		Thresholds []struct {
+			Name       string `json:"name"`
+			TopPoints  []int  `json:"top_points"`
+			TotalUsers int    `json:"total_users"`
+			Previous   int    `json:"previous"`
+			Threshold  int    `json:"threshold"`
+		} `json:"thresholds"`
+	}
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		t.Fatal(err)
+	}
+	if len(fixture.Thresholds) == 0 {
+		t.Fatal("fixture has no threshold cases")
+	}
+
+	for _, tc := range fixture.Thresholds {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := Top10Threshold(tc.TopPoints, tc.TotalUsers, tc.Previous); got != tc.Threshold {
+				t.Errorf("Top10Threshold(%v, %d, %d) = %d, want %d", tc.TopPoints, tc.TotalUsers, tc.Previous, got, tc.Threshold)
+			}
+		})
+	}
+}
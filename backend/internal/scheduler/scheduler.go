@@ -0,0 +1,236 @@
+// Package scheduler acts on challenge deadlines. Indexed challenges get
+// durable jobs in scheduled_jobs; a polling loop claims due jobs, marks late
+// submissions and records lifecycle notifications.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/webhook"
+)
+
+// Job kinds, one of each per challenge with a deadline.
+const (
+	JobDeadlineReminder  = "deadline_reminder"
+	JobDeadlinePassed    = "deadline_passed"
+	JobEmergencyWithdraw = "emergency_withdraw"
+)
+
+// Notification kinds.
+const (
+	NotifyDeadlineApproaching = "deadline_approaching"
+	NotifyDeadlinePassed      = "deadline_passed_no_winner"
+	NotifyEmergencyWithdraw   = "emergency_withdraw_available"
+)
+
+const (
+	// ReminderLead is how long before the deadline the reminder fires.
+	ReminderLead = 24 * time.Hour
+	// GracePeriod mirrors ChallengeEscrow.GRACE_PERIOD: the creator can call
+	// emergencyWithdraw this long after the deadline.
+	GracePeriod = 30 * 24 * time.Hour
+
+	// DefaultInterval is how often due jobs are polled for.
+	DefaultInterval = 30 * time.Second
+
+	lease       = 5 * time.Minute
+	batchSize   = 50
+	maxAttempts = 8
+)
+
+// Scheduler runs the challenge lifecycle jobs.
+type Scheduler struct {
+	jobs        database.JobRepository
+	challenges  database.ChallengeRepository
+	submissions database.SubmissionRepository
+	publisher   *webhook.Publisher
+	clock       Clock
+}
+
+func New(repos *database.Repositories, publisher *webhook.Publisher, clock Clock) *Scheduler {
+	return &Scheduler{
+		jobs:        repos.Jobs,
+		challenges:  repos.Challenges,
+		submissions: repos.Submissions,
+		publisher:   publisher,
+		clock:       clock,
+	}
+}
+
+// LoadInterval reads the polling interval from SCHEDULER_INTERVAL, falling
+// back to DefaultInterval.
+func LoadInterval() (time.Duration, error) {
+	raw := os.Getenv("SCHEDULER_INTERVAL")
+	if raw == "" {
+		return DefaultInterval, nil
+	}
+
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid SCHEDULER_INTERVAL %q", raw)
+	}
+	return interval, nil
+}
+
+// ScheduleChallenge creates the challenge's jobs if it has a deadline.
+// Scheduling is idempotent; jobs that have not run yet follow a changed
+// deadline.
+func (s *Scheduler) ScheduleChallenge(ctx context.Context, challenge *database.Challenge) error {
+	if challenge.Deadline == nil {
+		return nil
+	}
+
+	deadline := *challenge.Deadline
+	jobs := []database.ScheduledJob{
+		{Kind: JobDeadlineReminder, RunAt: deadline.Add(-ReminderLead)},
+		{Kind: JobDeadlinePassed, RunAt: deadline},
+		{Kind: JobEmergencyWithdraw, RunAt: deadline.Add(GracePeriod)},
+	}
+	for i := range jobs {
+		jobs[i].ChallengeAddress = challenge.Address
+		if _, err := s.jobs.ScheduleJob(ctx, &jobs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync schedules every open or active challenge whose deadline has not
+// passed, covering challenges indexed before the scheduler existed.
+func (s *Scheduler) Sync(ctx context.Context) error {
+	challenges, err := s.challenges.ListChallengesWithDeadlines(ctx, s.clock.Now().Add(-GracePeriod))
+	if err != nil {
+		return err
+	}
+	for i := range challenges {
+		if err := s.ScheduleChallenge(ctx, &challenges[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run polls for due jobs every interval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunDue(ctx); err != nil {
+			log.Printf("Warning: scheduler poll failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue claims and runs every job due at the clock's current time and
+// returns how many ran. Failed jobs are retried with a growing delay.
+func (s *Scheduler) RunDue(ctx context.Context) (int, error) {
+	ran := 0
+	for {
+		jobs, err := s.jobs.ClaimDueJobs(ctx, s.clock.Now(), lease, batchSize)
+		if err != nil {
+			return ran, err
+		}
+
+		for _, job := range jobs {
+			ran++
+			if err := s.run(ctx, job); err != nil {
+				s.retry(ctx, job, err)
+				continue
+			}
+			if err := s.jobs.CompleteJob(ctx, job.ID); err != nil {
+				return ran, err
+			}
+		}
+
+		if len(jobs) < batchSize {
+			return ran, nil
+		}
+	}
+}
+
+func (s *Scheduler) retry(ctx context.Context, job database.ScheduledJob, cause error) {
+	var runAt *time.Time
+	if job.Attempts < maxAttempts {
+		next := s.clock.Now().Add(time.Duration(job.Attempts*job.Attempts) * time.Minute)
+		runAt = &next
+	}
+
+	log.Printf("Warning: %s job for challenge %s failed (attempt %d): %v", job.Kind, job.ChallengeAddress, job.Attempts, cause)
+	if err := s.jobs.RetryJob(ctx, job.ID, runAt, cause.Error()); err != nil {
+		log.Printf("Warning: failed to reschedule job %d: %v", job.ID, err)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job database.ScheduledJob) error {
+	challenge, err := s.challenges.GetChallenge(ctx, job.ChallengeAddress)
+	if err != nil {
+		return err
+	}
+	if challenge == nil || challenge.Deadline == nil {
+		return nil
+	}
+	closed := challenge.Status == database.ChallengeCompleted || challenge.Status == database.ChallengeCancelled
+
+	switch job.Kind {
+	case JobDeadlineReminder:
+		// A reminder that comes due after the deadline has nothing to say.
+		if closed || !s.clock.Now().Before(*challenge.Deadline) {
+			return nil
+		}
+		return s.notify(ctx, challenge, NotifyDeadlineApproaching,
+			fmt.Sprintf("Submissions close at %s", challenge.Deadline.UTC().Format(time.RFC3339)))
+
+	case JobDeadlinePassed:
+		late, err := s.submissions.MarkLateSubmissions(ctx, challenge.Address, *challenge.Deadline)
+		if err != nil {
+			return err
+		}
+		if len(late) > 0 {
+			log.Printf("Marked %d submissions to %s as late", len(late), challenge.Address)
+		}
+		for i := range late {
+			// The status change is already stored, so a failed publish is
+			// logged rather than retried with the job.
+			if _, err := s.publisher.Publish(ctx, webhook.EventSubmissionStatusChanged, webhook.SubmissionStatusChanged(&late[i])); err != nil {
+				log.Printf("Warning: failed to queue %s webhooks: %v", webhook.EventSubmissionStatusChanged, err)
+			}
+		}
+		if closed {
+			return nil
+		}
+		return s.notify(ctx, challenge, NotifyDeadlinePassed, "The deadline passed without a winner")
+
+	case JobEmergencyWithdraw:
+		if closed {
+			return nil
+		}
+		return s.notify(ctx, challenge, NotifyEmergencyWithdraw, "The creator can now call emergencyWithdraw to recover the reward")
+	}
+
+	return fmt.Errorf("unknown job kind %q", job.Kind)
+}
+
+func (s *Scheduler) notify(ctx context.Context, challenge *database.Challenge, kind, message string) error {
+	notification := &database.Notification{
+		ChallengeAddress: challenge.Address,
+		Kind:             kind,
+		Message:          message,
+	}
+	inserted, err := s.jobs.CreateNotification(ctx, notification)
+	if err == nil && inserted {
+		log.Printf("Challenge %s: %s", challenge.Address, message)
+	}
+	return err
+}
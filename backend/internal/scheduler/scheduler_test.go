@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+	"github.com/cyrup/backend/internal/database/memory"
+	"github.com/cyrup/backend/internal/webhook"
+)
+
+const challengeAddress = "0x00000000000000000000000000000000000c4a11"
+
+// TestLifecycle steps a challenge through the reminder, the deadline and
+// the end of the grace period, moving time only through the FakeClock.
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewStore("").Repositories()
+
+	hook := &database.Webhook{
+		Owner:  "0x0000000000000000000000000000000000000b0b",
+		URL:    "https://example.com/hook",
+		Secret: "secret",
+		Events: []string{webhook.EventSubmissionStatusChanged},
+		Active: true,
+	}
+	if err := repos.Webhooks.CreateWebhook(ctx, hook); err != nil {
+		t.Fatal(err)
+	}
+
+	// The store stamps submissions with the wall clock, so the deadline is
+	// the on-time submission's creation time and the late one comes after.
+	onTime := submit(t, repos, "00000000-0000-4000-8000-000000000001")
+	deadline := onTime.CreatedAt
+	late := submit(t, repos, "00000000-0000-4000-8000-000000000002")
+	if !late.CreatedAt.After(deadline) {
+		t.Fatal("both submissions were stamped with the same time")
+	}
+
+	clock := NewFakeClock(deadline.Add(-ReminderLead - time.Hour))
+	sched := New(repos, webhook.NewPublisher(repos.Webhooks), clock)
+
+	challenge, _, err := repos.Challenges.RecordChallengeEvent(ctx, database.ChallengeEvent{
+		ChallengeAddress: challengeAddress,
+		Event:            database.EventChallengeCreated,
+		Creator:          "0x0000000000000000000000000000000000c4ea7e",
+		Deadline:         &deadline,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.ScheduleChallenge(ctx, challenge); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name          string
+		at            time.Time
+		ran           int
+		late          string
+		notifications []string
+		events        []string
+	}{
+		{"before the reminder", deadline.Add(-ReminderLead - time.Hour), 0, database.SubmissionPending,
+			[]string{}, []string{}},
+		{"reminder", deadline.Add(-ReminderLead), 1, database.SubmissionPending,
+			[]string{NotifyDeadlineApproaching}, []string{}},
+		{"deadline", deadline, 1, database.SubmissionLate,
+			[]string{NotifyDeadlineApproaching, NotifyDeadlinePassed}, []string{late.UID}},
+		{"grace period", deadline.Add(GracePeriod), 1, database.SubmissionLate,
+			[]string{NotifyDeadlineApproaching, NotifyDeadlinePassed, NotifyEmergencyWithdraw}, []string{late.UID}},
+		{"afterwards", deadline.Add(GracePeriod + time.Hour), 0, database.SubmissionLate,
+			[]string{NotifyDeadlineApproaching, NotifyDeadlinePassed, NotifyEmergencyWithdraw}, []string{late.UID}},
+	}
+
+	for _, step := range steps {
+		clock.Set(step.at)
+		ran, err := sched.RunDue(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if ran != step.ran {
+			t.Errorf("%s: ran %d jobs, want %d", step.name, ran, step.ran)
+		}
+		if got := status(t, repos, onTime.UID); got != database.SubmissionPending {
+			t.Errorf("%s: on-time submission is %s, want %s", step.name, got, database.SubmissionPending)
+		}
+		if got := status(t, repos, late.UID); got != step.late {
+			t.Errorf("%s: late submission is %s, want %s", step.name, got, step.late)
+		}
+		if got := notifications(t, repos); !slices.Equal(got, step.notifications) {
+			t.Errorf("%s: notifications %v, want %v", step.name, got, step.notifications)
+		}
+		if got := statusEvents(t, repos, hook.ID); !slices.Equal(got, step.events) {
+			t.Errorf("%s: status events for %v, want %v", step.name, got, step.events)
+		}
+	}
+}
+
+func submit(t *testing.T, repos *database.Repositories, uid string) *database.Submission {
+	t.Helper()
+	submission := &database.Submission{
+		UID:              uid,
+		ChallengeAddress: challengeAddress,
+		WalletAddress:    "0x00000000000000000000000000000000005e1f00",
+		SolutionCode:     "theorem t : True := trivial",
+		Status:           database.SubmissionPending,
+	}
+	if err := repos.Submissions.CreateSubmission(context.Background(), submission); err != nil {
+		t.Fatal(err)
+	}
+	return submission
+}
+
+func status(t *testing.T, repos *database.Repositories, uid string) string {
+	t.Helper()
+	submission, err := repos.Submissions.GetSubmissionByUID(context.Background(), uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return submission.Status
+}
+
+// notifications lists the challenge's notification kinds, oldest first.
+func notifications(t *testing.T, repos *database.Repositories) []string {
+	t.Helper()
+	list, err := repos.Jobs.ListNotifications(context.Background(), challengeAddress, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := []string{}
+	for i := len(list) - 1; i >= 0; i-- {
+		kinds = append(kinds, list[i].Kind)
+	}
+	return kinds
+}
+
+// statusEvents lists the submissions of the webhook's late status events.
+func statusEvents(t *testing.T, repos *database.Repositories, webhookID int) []string {
+	t.Helper()
+	deliveries, err := repos.Webhooks.ListDeliveries(context.Background(), webhookID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uids := []string{}
+	for _, delivery := range deliveries {
+		var event struct {
+			Data struct {
+				UID    string `json:"uid"`
+				Status string `json:"status"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(delivery.Payload, &event); err != nil {
+			t.Fatal(err)
+		}
+		if delivery.EventType == webhook.EventSubmissionStatusChanged && event.Data.Status == database.SubmissionLate {
+			uids = append(uids, event.Data.UID)
+		}
+	}
+	return uids
+}
@@ -0,0 +1,122 @@
+package similarity
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"strconv"
+)
+
+const (
+	// KGram is the number of normalized tokens hashed together.
+	KGram = 5
+	// Window is the winnowing window, in k-grams. Any match of at least
+	// KGram+Window-1 tokens is guaranteed to share a fingerprint.
+	Window = 4
+)
+
+// DefaultThreshold is the similarity at or above which submissions are
+// flagged.
+const DefaultThreshold = 0.8
+
+// Fingerprint is a sorted set of winnowed k-gram hashes.
+type Fingerprint []int64
+
+// FingerprintSource normalizes a Lean source and winnows the k-gram hashes
+// of its proof bodies. Every submission to a challenge proves the same
+// statement, so statements are left out; otherwise they alone would make
+// independent proofs look alike.
+func FingerprintSource(source string) Fingerprint {
+	return Winnow(ProofBodies(Normalize(source)))
+}
+
+// Winnow selects the minimum hash in every window of Window consecutive
+// k-gram hashes (the rightmost on ties). Streams shorter than KGram are
+// hashed whole so that tiny proofs still have a fingerprint.
+func Winnow(tokens []string) Fingerprint {
+	if len(tokens) == 0 {
+		return Fingerprint{}
+	}
+	if len(tokens) < KGram {
+		return Fingerprint{hashTokens(tokens)}
+	}
+
+	hashes := make([]int64, len(tokens)-KGram+1)
+	for i := range hashes {
+		hashes[i] = hashTokens(tokens[i : i+KGram])
+	}
+
+	selected := make(map[int64]bool)
+	last := -1
+	for start := 0; ; start++ {
+		end := min(start+Window, len(hashes))
+		smallest := start
+		for i := start; i < end; i++ {
+			if hashes[i] <= hashes[smallest] {
+				smallest = i
+			}
+		}
+		if smallest != last {
+			selected[hashes[smallest]] = true
+			last = smallest
+		}
+		if end == len(hashes) {
+			break
+		}
+	}
+
+	fingerprint := make(Fingerprint, 0, len(selected))
+	for hash := range selected {
+		fingerprint = append(fingerprint, hash)
+	}
+	slices.Sort(fingerprint)
+	return fingerprint
+}
+
+func hashTokens(tokens []string) int64 {
+	hash := fnv.New64a()
+	for _, tok := range tokens {
+		hash.Write([]byte(tok))
+		hash.Write([]byte{0})
+	}
+	return int64(hash.Sum64())
+}
+
+// Similarity is the Jaccard index of two fingerprints: 1 for identical
+// normalized proofs, 0 when they share nothing.
+func Similarity(a, b Fingerprint) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			shared++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// LoadThreshold reads SIMILARITY_THRESHOLD, a number in (0, 1], falling
+// back to DefaultThreshold.
+func LoadThreshold() (float64, error) {
+	raw := os.Getenv("SIMILARITY_THRESHOLD")
+	if raw == "" {
+		return DefaultThreshold, nil
+	}
+
+	threshold, err := strconv.ParseFloat(raw, 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		return 0, fmt.Errorf("invalid SIMILARITY_THRESHOLD %q: must be a number in (0, 1]", raw)
+	}
+	return threshold, nil
+}
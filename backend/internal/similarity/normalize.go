@@ -0,0 +1,317 @@
+// Package similarity detects near-duplicate Lean proofs. Sources are
+// normalized so that comments, layout and the names of locally bound
+// identifiers do not matter, then fingerprinted by winnowing k-grams of the
+// normalized tokens.
+package similarity
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// token is one lexical unit of Lean source and the line it starts on.
+type token struct {
+	text  string
+	ident bool
+	line  int
+}
+
+// keywords are never treated as local names, even where a binder could
+// appear.
+var keywords = map[string]bool{
+	"theorem": true, "lemma": true, "def": true, "abbrev": true, "example": true,
+	"instance": true, "structure": true, "inductive": true, "class": true,
+	"where": true, "extends": true, "deriving": true, "namespace": true,
+	"section": true, "end": true, "open": true, "import": true, "variable": true,
+	"universe": true, "noncomputable": true, "private": true, "protected": true,
+	"partial": true, "mutual": true, "set_option": true, "in": true,
+	"fun": true, "λ": true, "let": true, "have": true, "show": true, "from": true,
+	"by": true, "do": true, "then": true, "else": true, "if": true, "match": true,
+	"with": true, "at": true, "using": true, "only": true, "calc": true,
+	"intro": true, "intros": true, "rintro": true, "obtain": true, "rcases": true,
+	"cases": true, "induction": true, "generalize": true, "set": true,
+	"exact": true, "apply": true, "refine": true, "rw": true, "simp": true,
+	"rfl": true, "Type": true, "Prop": true, "Sort": true, "sorry": true,
+	"∀": true, "∃": true, "Π": true, "Σ": true,
+}
+
+// binderKeywords introduce names on the rest of their line, up to the
+// first ":", ":=", "=>", "↦" or top-level ",".
+var binderKeywords = map[string]bool{
+	"fun": true, "λ": true, "∀": true, "∃": true, "Π": true, "Σ": true,
+	"intro": true, "intros": true, "rintro": true, "let": true, "have": true,
+	"obtain": true, "set": true, "generalize": true, "with": true,
+}
+
+// declarationKeywords are followed by the name they declare.
+var declarationKeywords = map[string]bool{
+	"theorem": true, "lemma": true, "def": true, "abbrev": true, "instance": true,
+	"structure": true, "inductive": true, "class": true,
+}
+
+// operators are the multi-character symbols kept as single tokens, longest
+// first.
+var operators = []string{"<;>", ":=", "=>", "<-", "->", "<=", ">=", "!=", "==", "&&", "||", "++", "::", ".."}
+
+// Normalize returns the normalized token stream of a Lean source: comments
+// and whitespace are dropped and every locally bound name (declaration
+// names, binders, intro and pattern variables) is replaced by a canonical
+// name in order of first appearance.
+func Normalize(source string) []string {
+	tokens := tokenize(source)
+	bound := boundNames(tokens)
+
+	canonical := make(map[string]string, len(bound))
+	normalized := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		text := tok.text
+		if tok.ident {
+			// Rename the head of dotted projections like h.left too.
+			head, rest, _ := strings.Cut(text, ".")
+			if bound[head] {
+				name, ok := canonical[head]
+				if !ok {
+					name = fmt.Sprintf("#%d", len(canonical)+1)
+					canonical[head] = name
+				}
+				text = name
+				if rest != "" {
+					text += "." + rest
+				}
+			}
+		}
+		normalized = append(normalized, text)
+	}
+	return normalized
+}
+
+// ProofBodies drops the header of every declaration in a normalized token
+// stream: from the keyword up to and including the ":=" that starts its
+// body, or the "|" or "where" that starts its equations. What is left is
+// the proofs and definitions themselves. A stream without declarations is
+// returned whole.
+func ProofBodies(tokens []string) []string {
+	bodies := make([]string, 0, len(tokens))
+	found := false
+	for i := 0; i < len(tokens); i++ {
+		if !headerKeywords[tokens[i]] {
+			bodies = append(bodies, tokens[i])
+			continue
+		}
+
+		found = true
+		depth := 0
+		for i++; i < len(tokens); i++ {
+			switch tokens[i] {
+			case "(", "{", "⦃", "[", "⟨":
+				depth++
+			case ")", "}", "⦄", "]", "⟩":
+				depth--
+			}
+			if depth <= 0 && (tokens[i] == ":=" || tokens[i] == "|" || tokens[i] == "where") {
+				break
+			}
+		}
+	}
+	if !found {
+		return tokens
+	}
+	return bodies
+}
+
+// headerKeywords start a declaration whose header ProofBodies drops.
+var headerKeywords = map[string]bool{
+	"theorem": true, "lemma": true, "def": true, "abbrev": true, "example": true,
+	"instance": true,
+}
+
+// boundNames finds the names the source binds itself. It is a heuristic
+// over tokens rather than a parse, erring towards leaving library names
+// alone.
+func boundNames(tokens []token) map[string]bool {
+	bound := make(map[string]bool)
+	bind := func(tok token) {
+		if tok.ident && !keywords[tok.text] && tok.text != "_" && !strings.Contains(tok.text, ".") {
+			bound[tok.text] = true
+		}
+	}
+
+	for i, tok := range tokens {
+		switch {
+		case declarationKeywords[tok.text]:
+			if i+1 < len(tokens) {
+				bind(tokens[i+1])
+			}
+
+		case tok.text == "(" || tok.text == "{" || tok.text == "⦃" || tok.text == "[":
+			// Explicit binders: idents followed by a colon.
+			j := i + 1
+			for j < len(tokens) && tokens[j].ident && !keywords[tokens[j].text] {
+				j++
+			}
+			if j > i+1 && j < len(tokens) && tokens[j].text == ":" {
+				for _, name := range tokens[i+1 : j] {
+					bind(name)
+				}
+			}
+
+		case binderKeywords[tok.text]:
+			bindUntilStop(tokens, i+1, tok.line, bind)
+
+		case tok.text == "|" && (i == 0 || tokens[i-1].line != tok.line || tokens[i-1].text == "with"):
+			// Match and induction arms: "| ctor a b =>" binds a and b.
+			j := i + 1
+			for j < len(tokens) && !tokens[j].ident && tokens[j].text != "=>" {
+				j++
+			}
+			if j < len(tokens) && tokens[j].ident {
+				bindUntilStop(tokens, j+1, tok.line, bind)
+			}
+		}
+	}
+	return bound
+}
+
+func bindUntilStop(tokens []token, from int, line int, bind func(token)) {
+	depth := 0
+	for j := from; j < len(tokens) && tokens[j].line == line; j++ {
+		switch tokens[j].text {
+		case ":", ":=", "=>", "↦", "|":
+			return
+		case "⟨", "(":
+			depth++
+		case "⟩", ")":
+			depth--
+		case ",":
+			if depth <= 0 {
+				return
+			}
+		}
+		if tokens[j].ident {
+			if keywords[tokens[j].text] {
+				return
+			}
+			bind(tokens[j])
+		}
+	}
+}
+
+func tokenize(source string) []token {
+	runes := []rune(source)
+	var tokens []token
+	line := 1
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '-':
+			// Block comments nest.
+			depth := 0
+			for i < len(runes) {
+				if runes[i] == '/' && i+1 < len(runes) && runes[i+1] == '-' {
+					depth++
+					i += 2
+					continue
+				}
+				if runes[i] == '-' && i+1 < len(runes) && runes[i+1] == '/' {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+					continue
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+
+		case r == '"':
+			start, startLine := i, line
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' {
+					i++
+				} else if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i++
+			if i > len(runes) {
+				i = len(runes)
+			}
+			tokens = append(tokens, token{text: string(runes[start:i]), line: startLine})
+
+		case r == '«':
+			start := i
+			for i < len(runes) && runes[i] != '»' {
+				i++
+			}
+			if i < len(runes) {
+				i++
+			}
+			tokens = append(tokens, token{text: string(runes[start:i]), ident: true, line: line})
+
+		case isIdentStart(r):
+			start := i
+			for i < len(runes) && (isIdentRest(runes[i]) || (runes[i] == '.' && i+1 < len(runes) && isIdentRest(runes[i+1]))) {
+				i++
+			}
+			text := string(runes[start:i])
+			tokens = append(tokens, token{text: text, ident: !isSymbolKeyword(text), line: line})
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])) {
+				i++
+			}
+			tokens = append(tokens, token{text: string(runes[start:i]), line: line})
+
+		default:
+			text := string(r)
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:min(i+len(op), len(runes))]), op) {
+					text = op
+					break
+				}
+			}
+			tokens = append(tokens, token{text: text, line: line})
+			i += len([]rune(text))
+		}
+	}
+	return tokens
+}
+
+// isSymbolKeyword reports whether a letter-like keyword such as λ or Π is
+// really notation rather than a name.
+func isSymbolKeyword(text string) bool {
+	switch text {
+	case "λ", "Π", "Σ":
+		return true
+	}
+	return false
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentRest(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '\'' || r == '!' || r == '?' ||
+		unicode.Is(unicode.No, r) || ('₀' <= r && r <= '₉')
+}
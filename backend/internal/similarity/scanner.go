@@ -0,0 +1,115 @@
+package similarity
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/cyrup/backend/internal/database"
+)
+
+// Scanner fingerprints submissions and flags those that resemble earlier
+// submissions to the same challenge from other wallets.
+type Scanner struct {
+	submissions database.SubmissionRepository
+	similarity  database.SimilarityRepository
+	threshold   float64
+}
+
+func NewScanner(submissions database.SubmissionRepository, similarity database.SimilarityRepository, threshold float64) *Scanner {
+	return &Scanner{submissions: submissions, similarity: similarity, threshold: threshold}
+}
+
+// Threshold is the similarity at or above which submissions are flagged.
+func (s *Scanner) Threshold() float64 {
+	return s.threshold
+}
+
+// Scan fingerprints a stored submission, compares it with the submissions
+// fingerprinted before it and records the resulting flags.
+func (s *Scanner) Scan(ctx context.Context, submission *database.Submission) ([]database.SimilarityFlag, error) {
+	earlier, err := s.similarity.ListFingerprints(ctx, submission.ChallengeAddress)
+	if err != nil {
+		return nil, err
+	}
+	for i, fingerprint := range earlier {
+		if fingerprint.SubmissionUID == submission.UID {
+			earlier = earlier[:i]
+			break
+		}
+	}
+
+	_, flags, err := s.save(ctx, submission, earlier)
+	return flags, err
+}
+
+// Rescan fingerprints every submission to a challenge again, oldest first,
+// and replaces their flags. It returns the number of submissions scanned.
+func (s *Scanner) Rescan(ctx context.Context, challengeAddress string) (int, error) {
+	query := database.SubmissionQuery{
+		ChallengeAddress: challengeAddress,
+		Sort:             database.SortOldest,
+		IncludeCode:      true,
+		Limit:            100,
+	}
+
+	var earlier []database.SubmissionFingerprint
+	for {
+		submissions, err := s.submissions.ListSubmissions(ctx, query)
+		if err != nil {
+			return len(earlier), err
+		}
+
+		for i := range submissions {
+			fingerprint, _, err := s.save(ctx, &submissions[i], earlier)
+			if err != nil {
+				return len(earlier), err
+			}
+			earlier = append(earlier, *fingerprint)
+		}
+
+		if len(submissions) < query.Limit {
+			return len(earlier), nil
+		}
+		cursor := database.CursorAfter(submissions[len(submissions)-1], query.Sort)
+		query.After = &cursor
+	}
+}
+
+func (s *Scanner) save(ctx context.Context, submission *database.Submission, earlier []database.SubmissionFingerprint) (*database.SubmissionFingerprint, []database.SimilarityFlag, error) {
+	fingerprint := &database.SubmissionFingerprint{
+		SubmissionUID:    submission.UID,
+		ChallengeAddress: submission.ChallengeAddress,
+		WalletAddress:    submission.WalletAddress,
+		Fingerprint:      database.Int64Array(FingerprintSource(submission.SolutionCode)),
+	}
+
+	flags := []database.SimilarityFlag{}
+	for _, other := range earlier {
+		// Resubmitting your own proof is not plagiarism.
+		if strings.EqualFold(other.WalletAddress, submission.WalletAddress) {
+			continue
+		}
+
+		score := Similarity(Fingerprint(fingerprint.Fingerprint), Fingerprint(other.Fingerprint))
+		if score < s.threshold {
+			continue
+		}
+		flags = append(flags, database.SimilarityFlag{
+			ChallengeAddress: submission.ChallengeAddress,
+			SubmissionUID:    submission.UID,
+			WalletAddress:    submission.WalletAddress,
+			MatchedUID:       other.SubmissionUID,
+			MatchedWallet:    other.WalletAddress,
+			Score:            score,
+		})
+	}
+	sort.SliceStable(flags, func(i, j int) bool {
+		return flags[i].Score > flags[j].Score
+	})
+
+	if err := s.similarity.SaveFingerprint(ctx, fingerprint, flags); err != nil {
+		return nil, nil, err
+	}
+	return fingerprint, flags, nil
+}
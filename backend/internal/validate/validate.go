@@ -0,0 +1,83 @@
+// Package validate checks the identifiers clients send: wallet, challenge
+// and token addresses, submission UIDs and solution hashes. Addresses are
+// stored lowercase; Checksum gives the EIP-55 form for display.
+package validate
+
+import (
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/cyrup/backend/internal/auth"
+)
+
+var (
+	ErrAddress      = errors.New("must be a 0x-prefixed 20-byte hex address")
+	ErrChecksum     = errors.New("has an invalid EIP-55 checksum")
+	ErrUID          = errors.New("must be 1-64 letters, digits, '-' or '_'")
+	ErrSolutionHash = errors.New("must be a 0x-prefixed 32-byte hash or an IPFS CID")
+)
+
+var (
+	uidPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+	// CIDv0 is base58btc starting with Qm; CIDv1 is commonly base32 with a
+	// leading b.
+	cidPattern = regexp.MustCompile(`^(Qm[1-9A-HJ-NP-Za-km-z]{44}|b[a-z2-7]{58,})$`)
+)
+
+// Address checks that s is an address and returns it in lowercase. All
+// lowercase and all uppercase hex are accepted as is; mixed case must be a
+// valid EIP-55 checksum, which catches most typos.
+func Address(s string) (string, error) {
+	if len(s) != 42 || !strings.HasPrefix(s, "0x") {
+		return "", ErrAddress
+	}
+	digits := s[2:]
+	if _, err := hex.DecodeString(digits); err != nil {
+		return "", ErrAddress
+	}
+
+	lower := strings.ToLower(s)
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && Checksum(lower) != s {
+		return "", ErrChecksum
+	}
+	return lower, nil
+}
+
+// Checksum returns the EIP-55 mixed-case form of a valid address.
+func Checksum(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	hash := hex.EncodeToString(auth.Keccak256([]byte(lower)))
+
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(checksummed)
+}
+
+// UID checks a submission UID. UIDs appear in URLs and on-chain, so they
+// are limited to URL-safe characters.
+func UID(s string) error {
+	if !uidPattern.MatchString(s) {
+		return ErrUID
+	}
+	return nil
+}
+
+// SolutionHash checks a solution hash and returns its canonical form:
+// keccak256 hashes lowercase, IPFS CIDs unchanged.
+func SolutionHash(s string) (string, error) {
+	if cidPattern.MatchString(s) {
+		return s, nil
+	}
+	if len(s) == 66 && strings.HasPrefix(s, "0x") {
+		if _, err := hex.DecodeString(s[2:]); err == nil {
+			return strings.ToLower(s), nil
+		}
+	}
+	return "", ErrSolutionHash
+}
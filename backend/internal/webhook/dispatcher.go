@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+)
+
+const (
+	// DefaultInterval is how often due deliveries are polled for.
+	DefaultInterval = 5 * time.Second
+	// MaxAttempts is how many times a delivery is tried before it is
+	// marked failed.
+	MaxAttempts = 10
+
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+	lease       = time.Minute
+	batchSize   = 20
+	timeout     = 10 * time.Second
+)
+
+// Signature is the value of the X-Cyrup-Signature header: the hex
+// HMAC-SHA256, keyed with the webhook secret, of the X-Cyrup-Timestamp value,
+// a dot and the raw body.
+func Signature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff is the delay after the given failed attempt: 30s doubling each
+// time, capped at six hours.
+func Backoff(attempt int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// LoadInterval reads the polling interval from WEBHOOK_INTERVAL, falling
+// back to DefaultInterval.
+func LoadInterval() (time.Duration, error) {
+	raw := os.Getenv("WEBHOOK_INTERVAL")
+	if raw == "" {
+		return DefaultInterval, nil
+	}
+
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid WEBHOOK_INTERVAL %q", raw)
+	}
+	return interval, nil
+}
+
+// Dispatcher posts queued deliveries.
+type Dispatcher struct {
+	webhooks database.WebhookRepository
+	client   *http.Client
+}
+
+func NewDispatcher(webhooks database.WebhookRepository) *Dispatcher {
+	return &Dispatcher{
+		webhooks: webhooks,
+		client: &http.Client{
+			Timeout: timeout,
+			// No proxy: the dialer must see the webhook's own address.
+			Transport: &http.Transport{
+				DialContext:         publicDialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        batchSize,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// A redirect would resend the payload somewhere the owner did
+			// not register.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Run polls for due deliveries every interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil {
+			log.Printf("Warning: webhook dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue claims and attempts every delivery due now and returns how
+// many were attempted.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for {
+		deliveries, err := d.webhooks.ClaimDueDeliveries(ctx, time.Now(), lease, batchSize)
+		if err != nil {
+			return attempted, err
+		}
+
+		for _, delivery := range deliveries {
+			attempted++
+			if err := d.deliver(ctx, delivery); err != nil {
+				return attempted, err
+			}
+		}
+
+		if len(deliveries) < batchSize {
+			return attempted, nil
+		}
+	}
+}
+
+// deliver makes one attempt and records its outcome. Only a failure to
+// record the outcome is returned.
+func (d *Dispatcher) deliver(ctx context.Context, delivery database.WebhookDelivery) error {
+	webhook, err := d.webhooks.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if webhook == nil || !webhook.Active {
+		return d.webhooks.RetryDelivery(ctx, delivery.ID, nil, nil, "webhook is no longer active")
+	}
+
+	status, err := d.post(ctx, webhook, delivery)
+	if err == nil {
+		return d.webhooks.CompleteDelivery(ctx, delivery.ID, status, time.Now())
+	}
+
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+	var next *time.Time
+	if delivery.Attempts < MaxAttempts {
+		at := time.Now().Add(Backoff(delivery.Attempts))
+		next = &at
+	}
+	log.Printf("Warning: webhook delivery %d to %s failed (attempt %d): %v", delivery.ID, webhook.URL, delivery.Attempts, err)
+	return d.webhooks.RetryDelivery(ctx, delivery.ID, next, responseStatus, err.Error())
+}
+
+// post sends the delivery and returns the response status. Anything but a
+// 2xx response is an error.
+func (d *Dispatcher) post(ctx context.Context, webhook *database.Webhook, delivery database.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Cyrup-Webhooks/1")
+	req.Header.Set("X-Cyrup-Event", delivery.EventType)
+	req.Header.Set("X-Cyrup-Delivery", strconv.Itoa(delivery.ID))
+	req.Header.Set("X-Cyrup-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Cyrup-Signature", Signature(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenTarget is returned for a webhook URL that resolves to an
+// address the API must not post to.
+var ErrForbiddenTarget = errors.New("address is loopback, private or link-local")
+
+// sharedAddressSpace is the carrier-grade NAT range, where some clouds
+// serve instance metadata.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// PublicAddress reports whether a webhook may be delivered to addr. Loopback,
+// private, link-local, shared, multicast and unspecified addresses are
+// refused, so that a webhook cannot reach the API's own network.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// CheckTarget resolves host and returns ErrForbiddenTarget if any of its
+// addresses is not public. The dispatcher checks again when it connects,
+// since the name may resolve differently by then.
+func CheckTarget(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !PublicAddress(addr) {
+			return fmt.Errorf("%s resolves to %s: %w", host, addr.Unmap(), ErrForbiddenTarget)
+		}
+	}
+	return nil
+}
+
+// publicDialer connects only to public addresses. The check runs on the
+// address actually dialled, after resolution, so DNS rebinding cannot get
+// past it.
+var publicDialer = &net.Dialer{
+	Timeout:   timeout,
+	KeepAlive: 30 * time.Second,
+	Control: func(network, address string, _ syscall.RawConn) error {
+		addrPort, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return err
+		}
+		if !PublicAddress(addrPort.Addr()) {
+			return fmt.Errorf("dial %s: %w", address, ErrForbiddenTarget)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,91 @@
+// Package webhook delivers events to subscribed HTTP endpoints. Events are
+// written to an outbox when they happen; a Dispatcher posts them, signed
+// with the webhook's secret, and retries failures with exponential backoff.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cyrup/backend/internal/database"
+	"github.com/google/uuid"
+)
+
+// Event types a webhook can subscribe to.
+const (
+	EventVerificationFinished    = "verification.finished"
+	EventSubmissionStatusChanged = "submission.status_changed"
+	EventChallengeStateChanged   = "challenge.state_changed"
+	EventReputationUpdated       = "reputation.updated"
+)
+
+// EventTypes lists every event type.
+var EventTypes = []string{
+	EventVerificationFinished,
+	EventSubmissionStatusChanged,
+	EventChallengeStateChanged,
+	EventReputationUpdated,
+}
+
+// Event is the body of every delivery. ID is shared by all deliveries of
+// the same event, so receivers can deduplicate retries.
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Publisher queues events for the webhooks subscribed to them.
+type Publisher struct {
+	webhooks database.WebhookRepository
+}
+
+func NewPublisher(webhooks database.WebhookRepository) *Publisher {
+	return &Publisher{webhooks: webhooks}
+}
+
+// Publish queues an event of the given type and returns how many webhooks
+// it was queued for.
+func (p *Publisher) Publish(ctx context.Context, eventType string, data interface{}) (int, error) {
+	return p.publish(ctx, eventType, "", data)
+}
+
+// PublishTo queues an event for the owner's webhooks only. An empty owner
+// queues it for none.
+func (p *Publisher) PublishTo(ctx context.Context, owner, eventType string, data interface{}) (int, error) {
+	if owner == "" {
+		return 0, nil
+	}
+	return p.publish(ctx, eventType, owner, data)
+}
+
+// SubmissionStatusChanged is the payload of a submission.status_changed
+// event. Solution code stays out of webhook payloads.
+func SubmissionStatusChanged(submission *database.Submission) map[string]interface{} {
+	data := map[string]interface{}{
+		"uid":               submission.UID,
+		"challenge_address": submission.ChallengeAddress,
+		"wallet_address":    submission.WalletAddress,
+		"status":            submission.Status,
+	}
+	if submission.SolutionHash != nil {
+		data["solution_hash"] = *submission.SolutionHash
+	}
+	return data
+}
+
+func (p *Publisher) publish(ctx context.Context, eventType, owner string, data interface{}) (int, error) {
+	event := Event{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	return p.webhooks.EnqueueDeliveries(ctx, event.ID, eventType, owner, payload, event.CreatedAt)
+}
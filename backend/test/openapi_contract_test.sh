@@ -0,0 +1,155 @@
+#!/bin/bash
+
+# Checks the API against internal/openapi/openapi.json: the generated Go
+# client must be up to date, and every member of the live responses below
+# must be a documented property. Routes and Go types are compared with the
+# spec by go test ./api/handlers. Start the API against a scratch store with
+# an admin token, e.g.:
+#
+#   DATABASE_DRIVER=memory ADMIN_TOKEN=secret go run ./api
+
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+NC='\033[0m'
+
+cd "$(dirname "$0")/.." || exit 1
+
+API_URL="${API_URL:-http://localhost:8080}"
+ADMIN_TOKEN="${ADMIN_TOKEN:-secret}"
+WALLET="0x$(openssl rand -hex 20)"
+CHALLENGE="0x$(openssl rand -hex 20)"
+UID_="$(uuidgen 2>/dev/null || cat /proc/sys/kernel/random/uuid)"
+SPEC=$(mktemp)
+trap 'rm -f "$SPEC"' EXIT
+PASSED=0
+FAILED=0
+
+# undocumented lists the members of a value that its schema does not
+# declare, following $refs, nullable oneOfs and array items.
+UNDOCUMENTED='
+def resolve: if .["$ref"] then $spec[0].components.schemas[.["$ref"] | ltrimstr("#/components/schemas/")] else . end;
+def nonnull:
+  if .oneOf then (.oneOf | map(select(.type != "null"))) as $b | if ($b | length) == 1 then $b[0] else null end
+  else . end;
+def undocumented($schema; $at):
+  ($schema | nonnull) as $s
+  | if $s == null then empty else ($s | resolve) as $r
+    | if type == "object" and $r.properties then
+        to_entries[] | .key as $k
+        | if $r.properties[$k] then .value | undocumented($r.properties[$k]; "\($at).\($k)") else "\($at).\($k)" end
+      elif type == "array" and $r.items then
+        .[] | undocumented($r.items; "\($at)[]")
+      else empty end
+    end;
+[undocumented({"$ref": "#/components/schemas/\($name)"}; "")] | unique | join(" ")'
+
+# check takes a response from get or post, with the status on its last line.
+# Problem responses must be errors and every other response a success.
+check() {
+    local name="$1" schema="$2" body="${3%$'\n'*}" status="${3##*$'\n'}"
+    echo -n "$name: "
+    local expected="2*"
+    [ "$schema" = Problem ] && expected="[45]*"
+    if [[ "$status" != $expected ]]; then
+        echo -e "${RED}✗${NC} (status $status: $body)"
+        ((FAILED++))
+        return
+    fi
+    if ! echo "$body" | jq -e 'type == "object"' > /dev/null 2>&1; then
+        echo -e "${RED}✗${NC} (not a JSON object: $body)"
+        ((FAILED++))
+        return
+    fi
+    local extra
+    extra=$(echo "$body" | jq -r --slurpfile spec "$SPEC" --arg name "$schema" "$UNDOCUMENTED")
+    if [ -z "$extra" ]; then
+        echo -e "${GREEN}✓${NC} ($schema)"
+        ((PASSED++))
+    else
+        echo -e "${RED}✗${NC} ($schema lacks $extra)"
+        ((FAILED++))
+    fi
+}
+
+get() {
+    curl -s -w '\n%{http_code}' "$API_URL$1"
+}
+
+post() {
+    curl -s -w '\n%{http_code}' -X POST "$API_URL$1" -H "Content-Type: application/json" -d "$2"
+}
+
+admin_post() {
+    curl -s -w '\n%{http_code}' -X POST "$API_URL$1" -H "Content-Type: application/json" \
+        -H "Authorization: Bearer $ADMIN_TOKEN" -d "$2"
+}
+
+echo "🧪 OpenAPI Contract Test"
+echo "========================"
+
+echo -n "generated client is current: "
+before=$(sha256sum client/client_gen.go)
+go generate ./client
+if [ "$(sha256sum client/client_gen.go)" = "$before" ]; then
+    echo -e "${GREEN}✓${NC}"
+    ((PASSED++))
+else
+    echo -e "${RED}✗${NC} (client/client_gen.go was stale; review and commit the regenerated file)"
+    ((FAILED++))
+fi
+
+if ! curl -sf "$API_URL/api/openapi.json" -o "$SPEC"; then
+    echo -e "${RED}✗${NC} API not reachable at $API_URL"
+    exit 1
+fi
+echo -n "served spec matches the repository: "
+if [ "$(jq -S . "$SPEC")" = "$(jq -S . internal/openapi/openapi.json)" ]; then
+    echo -e "${GREEN}✓${NC}"
+    ((PASSED++))
+else
+    echo -e "${RED}✗${NC} (the server is running an older build)"
+    ((FAILED++))
+fi
+
+check "unauthenticated challenge event" Problem "$(post /api/challenges/events \
+    "{\"event\":\"ChallengeCancelled\",\"challenge_address\":\"$CHALLENGE\"}")"
+check "challenge event" ChallengeEventResult "$(admin_post /api/challenges/events \
+    "{\"event\":\"ChallengeCreated\",\"challenge_address\":\"$CHALLENGE\",\"creator\":\"$WALLET\",\"deadline\":$(( $(date +%s) + 86400 ))}")"
+check "submission" Submission "$(post /api/submissions \
+    "{\"uid\":\"$UID_\",\"wallet_address\":\"$WALLET\",\"challenge_address\":\"$CHALLENGE\",\"solution_code\":\"theorem t : True := trivial\"}")"
+check "reputation event" ReputationEventResult "$(admin_post /api/leaderboard/events \
+    "{\"wallet_address\":\"$WALLET\",\"usdc_amount\":\"12.5\",\"transaction_hash\":\"0x$(openssl rand -hex 32)\",\"log_index\":0,\"block_number\":1}")"
+
+check "project" VerifyResponse "$(post /api/verify \
+    '{"files":{"Main.lean":"import Helpers","Helpers.lean":"theorem t : True := trivial"},"entry":"Main"}')"
+BATCH=$(post /api/verify/batch '{"items":[{"code":"theorem t : True := trivial","label":"t.lean"},{"files":{"Main.lean":"import Helpers","Helpers.lean":"theorem t : True := trivial"},"entry":"Main","label":"project"}]}')
+check "batch" BatchResponse "$BATCH"
+BATCH_ID=$(echo "${BATCH%$'\n'*}" | jq -r .id)
+
+check "health" Health "$(get "/health")"
+check "batch progress" BatchResponse "$(get "/api/verify/batch/$BATCH_ID")"
+check "batch items" BatchItemList "$(get "/api/verify/batch/$BATCH_ID/items")"
+check "challenge" Challenge "$(get "/api/challenges/$CHALLENGE")"
+check "challenge schedule" ScheduledJobList "$(get "/api/challenges/$CHALLENGE/schedule")"
+check "commitments" CommitmentList "$(get "/api/challenges/$CHALLENGE/commitments")"
+check "challenges" ChallengeList "$(get "/api/challenges?status=open")"
+check "deadlines" DeadlineList "$(get "/api/deadlines")"
+check "submission view" SubmissionView "$(get "/api/submissions/$UID_")"
+check "wallet submissions" SubmissionList "$(get "/api/submissions/wallet/$WALLET?limit=1")"
+check "challenge submissions" SubmissionList "$(get "/api/submissions/challenge/$CHALLENGE")"
+check "leaderboard" Leaderboard "$(get "/api/leaderboard")"
+check "seasons" SeasonList "$(get "/api/leaderboard/seasons")"
+check "tokens" TokenList "$(get "/api/tokens")"
+check "top performers" LeaderboardEntryList "$(get "/api/leaderboard/top")"
+check "user stats" UserStats "$(get "/api/leaderboard/user/$WALLET")"
+check "qualification" Qualification "$(get "/api/leaderboard/user/$WALLET/qualification")"
+check "thresholds" ThresholdUpdateList "$(get "/api/leaderboard/thresholds")"
+check "points preview" PointsPreview "$(get "/api/leaderboard/points?amount=100")"
+check "recent events" ReputationEventList "$(get "/api/leaderboard/events/recent")"
+check "not found problem" Problem "$(get "/api/submissions/00000000-0000-4000-8000-000000000000")"
+check "validation problem" Problem "$(post /api/submissions '{}')"
+check "project problem" Problem "$(post /api/verify '{"files":{"../Main.lean":""}}')"
+
+echo ""
+echo "Passed: $PASSED  Failed: $FAILED"
+[ "$FAILED" -eq 0 ]
@@ -0,0 +1,34 @@
+#!/bin/bash
+
+# Checks the API's point calculation against the shared parity fixture.
+# The same fixture is asserted against ReputationSystem.calculatePoints in
+# contracts/test/PointsParity.t.sol.
+
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+NC='\033[0m'
+
+API_URL="${API_URL:-http://localhost:8080}"
+FIXTURE="$(dirname "$0")/points_parity.json"
+PASSED=0
+FAILED=0
+
+echo "🧪 Reputation Points Parity Test"
+echo "================================"
+
+while read -r amount is_verifier expected; do
+    echo -n "amount=$amount is_verifier=$is_verifier: "
+    actual=$(curl -s "$API_URL/api/leaderboard/points?amount=$amount&is_verifier=$is_verifier" | jq -r '.points')
+
+    if [ "$actual" = "$expected" ]; then
+        echo -e "${GREEN}✓${NC} ($actual)"
+        ((PASSED++))
+    else
+        echo -e "${RED}✗${NC} (expected $expected, got $actual)"
+        ((FAILED++))
+    fi
+done < <(jq -r '.cases[] | "\(.amount) \(.is_verifier) \(.points)"' "$FIXTURE")
+
+echo ""
+echo "Passed: $PASSED  Failed: $FAILED"
+[ "$FAILED" -eq 0 ]
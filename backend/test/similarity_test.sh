@@ -0,0 +1,76 @@
+#!/bin/bash
+
+# Checks similarity flags on /api/challenges/:address/similarity: a copy of
+# a proof with its names changed is flagged, an independent proof of the
+# same statement is not. Start the API against a scratch store with an
+# admin token, which indexes the challenge, e.g.:
+#
+#   DATABASE_DRIVER=memory ADMIN_TOKEN=secret go run ./api
+
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+NC='\033[0m'
+
+API_URL="${API_URL:-http://localhost:8080}"
+ADMIN_TOKEN="${ADMIN_TOKEN:-secret}"
+CHALLENGE="0x$(openssl rand -hex 20)"
+PASSED=0
+FAILED=0
+
+new_uid() {
+    uuidgen 2>/dev/null || cat /proc/sys/kernel/random/uuid
+}
+
+challenge_event() {
+    curl -s -X POST "$API_URL/api/challenges/events" \
+        -H "Content-Type: application/json" \
+        -H "Authorization: Bearer $ADMIN_TOKEN" \
+        -d "$1" > /dev/null
+}
+
+# submit posts code from a fresh wallet and prints the submission's uid.
+submit() {
+    local uid
+    uid=$(new_uid)
+    jq -n --arg uid "$uid" --arg wallet "0x$(openssl rand -hex 20)" --arg challenge "$CHALLENGE" --arg code "$1" \
+        '{uid: $uid, wallet_address: $wallet, challenge_address: $challenge, solution_code: $code}' |
+        curl -s -X POST "$API_URL/api/submissions" -H "Content-Type: application/json" -d @- > /dev/null
+    echo "$uid"
+}
+
+check() {
+    local name="$1" expected="$2" actual="$3"
+    echo -n "$name: "
+    if [ "$actual" = "$expected" ]; then
+        echo -e "${GREEN}✓${NC} ($actual)"
+        ((PASSED++))
+    else
+        echo -e "${RED}✗${NC} (expected $expected, got $actual)"
+        ((FAILED++))
+    fi
+}
+
+echo "🧪 Similarity Test"
+echo "=================="
+
+challenge_event "{\"event\":\"ChallengeCreated\",\"challenge_address\":\"$CHALLENGE\",\"creator\":\"0x$(openssl rand -hex 20)\",\"deadline\":$(( $(date +%s) + 86400 ))}"
+
+ORIGINAL=$(submit 'theorem add_le (a b c : Nat) (h : a ≤ b) : a + c ≤ b + c :=
+  Nat.add_le_add_right h c')
+RENAMED=$(submit '-- my own work
+theorem my_add_le (x y z : Nat) (hxy : x ≤ y) : x + z ≤ y + z :=
+  Nat.add_le_add_right hxy z')
+INDEPENDENT=$(submit 'theorem add_le (a b c : Nat) (h : a ≤ b) : a + c ≤ b + c := by
+  omega')
+
+# The report is public once the challenge closes.
+challenge_event "{\"event\":\"ChallengeCancelled\",\"challenge_address\":\"$CHALLENGE\"}"
+REPORT=$(curl -s "$API_URL/api/challenges/$CHALLENGE/similarity")
+
+check "renamed copy is flagged" "$ORIGINAL" "$(echo "$REPORT" | jq -r --arg u "$RENAMED" '.data[] | select(.submission_uid == $u) | .matched_uid')"
+check "independent proof is not flagged" "0" "$(echo "$REPORT" | jq --arg u "$INDEPENDENT" '[.data[] | select(.submission_uid == $u)] | length')"
+check "flag count" "1" "$(echo "$REPORT" | jq '.data | length')"
+
+echo ""
+echo "Passed: $PASSED  Failed: $FAILED"
+[ "$FAILED" -eq 0 ]
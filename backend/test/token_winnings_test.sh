@@ -0,0 +1,60 @@
+#!/bin/bash
+
+# Checks per-token winnings and the USD total on /api/leaderboard/user/:wallet.
+# Start the API against a scratch store with an admin token, the test price
+# list and an 18-decimal test token, e.g.:
+#
+#   DATABASE_DRIVER=memory ADMIN_TOKEN=secret PRICE_FILE=test/prices.json \
+#   TOKENS='[{"address":"0x0000000000000000000000000000000000000de0","symbol":"TEST","decimals":18}]' \
+#   go run ./api
+
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+NC='\033[0m'
+
+API_URL="${API_URL:-http://localhost:8080}"
+ADMIN_TOKEN="${ADMIN_TOKEN:-secret}"
+WALLET="0x$(openssl rand -hex 20)"
+TEST_TOKEN="0x0000000000000000000000000000000000000de0"
+PASSED=0
+FAILED=0
+
+record() {
+    curl -s -X POST "$API_URL/api/leaderboard/events" \
+        -H "Content-Type: application/json" \
+        -H "Authorization: Bearer $ADMIN_TOKEN" \
+        -d "$1" > /dev/null
+}
+
+check() {
+    local name="$1" expected="$2" actual="$3"
+    echo -n "$name: "
+    if [ "$actual" = "$expected" ]; then
+        echo -e "${GREEN}✓${NC} ($actual)"
+        ((PASSED++))
+    else
+        echo -e "${RED}✗${NC} (expected $expected, got $actual)"
+        ((FAILED++))
+    fi
+}
+
+echo "🧪 Token Winnings Test"
+echo "======================"
+
+TX="0x$(openssl rand -hex 32)"
+record "{\"wallet_address\":\"$WALLET\",\"usdc_amount\":\"150.25\",\"transaction_hash\":\"$TX\",\"log_index\":0,\"block_number\":1}"
+record "{\"wallet_address\":\"$WALLET\",\"amount_raw\":\"4000000000000000000\",\"token_address\":\"$TEST_TOKEN\",\"transaction_hash\":\"$TX\",\"log_index\":1,\"block_number\":1}"
+
+STATS=$(curl -s "$API_URL/api/leaderboard/user/$WALLET")
+
+check "token count" "2" "$(echo "$STATS" | jq -r '.winnings | length')"
+check "test token amount" "4" "$(echo "$STATS" | jq -r --arg t "$TEST_TOKEN" '.winnings[] | select(.token_address == $t) | .amount_won')"
+check "test token symbol" "TEST" "$(echo "$STATS" | jq -r --arg t "$TEST_TOKEN" '.winnings[] | select(.token_address == $t) | .symbol')"
+check "usd total" "160.25" "$(echo "$STATS" | jq -r '.total_usd')"
+check "usdc total excludes other tokens" "150.25" "$(echo "$STATS" | jq -r '.stats.total_usdc_won')"
+WINDOWED=$(curl -s "$API_URL/api/leaderboard?window=30d&limit=100")
+check "windowed usdc total" "150.25" "$(echo "$WINDOWED" | jq -r --arg w "$WALLET" '.data[] | select(.wallet_address == $w) | .total_usdc_won')"
+
+echo ""
+echo "Passed: $PASSED  Failed: $FAILED"
+[ "$FAILED" -eq 0 ]
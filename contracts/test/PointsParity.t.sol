@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.26;
+
+import "forge-std/Test.sol";
+import "../src/ReputationSystem.sol";
+
+/// @title Points Parity Test
+/// @notice Pins calculatePoints to the fixture shared with the Go backend
+/// @dev The backend runs the same cases in backend/test/points_parity_test.sh
+contract PointsParityTest is Test {
+    /// @dev Field order must be alphabetical to match vm.parseJson decoding
+    struct ParityCase {
+        uint256 amount;
+        bool is_verifier;
+        uint256 points;
+    }
+
+    ReputationSystem public reputationSystem;
+
+    function setUp() public {
+        reputationSystem = new ReputationSystem(address(this));
+    }
+
+    function testCalculatePointsMatchesBackendFixture() public view {
+        string memory json = vm.readFile("../backend/test/points_parity.json");
+        ParityCase[] memory cases = abi.decode(vm.parseJson(json, ".cases"), (ParityCase[]));
+
+        assertGt(cases.length, 0);
+        for (uint256 i; i < cases.length; ++i) {
+            assertEq(
+                reputationSystem.calculatePoints(cases[i].amount, cases[i].is_verifier),
+                cases[i].points
+            );
+        }
+    }
+}
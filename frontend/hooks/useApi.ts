@@ -42,7 +42,7 @@ export function useVerifyProof() {
 export function useSubmissions(walletAddress?: string) {
   return useQuery({
     queryKey: ['submissions', walletAddress],
-    queryFn: () => walletAddress ? apiClient.getUserSubmissions(walletAddress) : [],
+    queryFn: async () => walletAddress ? (await apiClient.getUserSubmissions(walletAddress)).data : [],
     enabled: !!walletAddress,
   });
 }
@@ -50,7 +50,7 @@ export function useSubmissions(walletAddress?: string) {
 export function useChallengeSubmissions(challengeAddress?: string) {
   return useQuery({
     queryKey: ['challengeSubmissions', challengeAddress],
-    queryFn: () => challengeAddress ? apiClient.getChallengeSubmissions(challengeAddress) : [],
+    queryFn: async () => challengeAddress ? (await apiClient.getChallengeSubmissions(challengeAddress, { limit: 100 })).data : [],
     enabled: !!challengeAddress,
   });
 }
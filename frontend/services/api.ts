@@ -2,8 +2,12 @@
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 'https://cyrup-production.up.railway.app';
 
 // Types
+// Send either code or a multi-file project as files or a base64 archive.
 export interface VerifyProofRequest {
-  code: string;
+  code?: string;
+  files?: Record<string, string>;
+  archive?: string;
+  entry?: string;
   timeout?: number;
 }
 
@@ -37,15 +41,109 @@ export interface Submission {
   verification_result?: any;
 }
 
+export interface SubmissionListParams {
+  cursor?: string;
+  limit?: number;
+  status?: string[];
+  created_after?: string;
+  created_before?: string;
+  sort?: 'newest' | 'oldest';
+  fields?: (keyof Submission)[];
+}
+
+// Every list endpoint wraps its items in a {data, page} envelope. Members of
+// page that do not apply to an endpoint are omitted; next_cursor is absent
+// on the last page of a cursor listing.
+export interface Page {
+  count: number;
+  limit?: number;
+  offset?: number;
+  next_cursor?: string;
+}
+
+export interface ListResponse<T> {
+  data: T[];
+  page: Page;
+}
+
+// Listings omit solution_code unless it is requested through `fields`.
+export type SubmissionPage = ListResponse<Partial<Submission>>;
+
+// Errors are RFC 7807 problem details. `code` is stable and safe to branch
+// on; `detail` is for people.
+export interface Problem {
+  type: string;
+  title: string;
+  status: number;
+  detail?: string;
+  instance?: string;
+  code: string;
+  request_id?: string;
+  errors?: { field: string; message: string }[];
+  [extension: string]: unknown;
+}
+
+export class ApiError extends Error {
+  constructor(public readonly problem: Problem) {
+    super(problem.detail || problem.title);
+    this.name = 'ApiError';
+  }
+
+  get status(): number {
+    return this.problem.status;
+  }
+
+  get code(): string {
+    return this.problem.code;
+  }
+}
+
+function submissionListQuery(params: SubmissionListParams = {}): string {
+  const query = new URLSearchParams();
+  if (params.cursor) query.set('cursor', params.cursor);
+  if (params.limit) query.set('limit', String(params.limit));
+  if (params.status?.length) query.set('status', params.status.join(','));
+  if (params.created_after) query.set('created_after', params.created_after);
+  if (params.created_before) query.set('created_before', params.created_before);
+  if (params.sort) query.set('sort', params.sort);
+  if (params.fields?.length) query.set('fields', params.fields.join(','));
+  const encoded = query.toString();
+  return encoded ? `?${encoded}` : '';
+}
+
 export interface LeaderboardEntry {
+  id: number;
   wallet_address: string;
-  total_points: number;
-  challenge_count: number;
-  winner_count: number;
-  verifier_count: number;
+  reputation_score: number;
+  total_usdc_won: string;
+  challenges_won: number;
+  challenges_verified: number;
+  verifier_points: number;
+  verifier_fees_earned: string;
   last_updated: string;
 }
 
+export interface TokenWinnings {
+  wallet_address: string;
+  token_address: string;
+  symbol: string;
+  decimals: number;
+  amount_won: string;
+  wins: number;
+  fees_earned: string;
+  verifications: number;
+  usd_value: string | null;
+  last_updated: string;
+}
+
+export interface UserStats {
+  stats: LeaderboardEntry;
+  position: number;
+  winnings: TokenWinnings[];
+  total_usd: string | null;
+  unpriced_tokens: string[];
+}
+
 export interface ReputationEvent {
   wallet_address: string;
   event_type: 'challenge_won' | 'verification_completed' | 'challenge_created';
@@ -77,8 +175,20 @@ class ApiClient {
     });
 
     if (!response.ok) {
-      const error = await response.text();
-      throw new Error(`API Error: ${response.status} - ${error}`);
+      const body = await response.text();
+      let problem: Problem;
+      try {
+        problem = JSON.parse(body);
+      } catch {
+        problem = {
+          type: 'about:blank',
+          title: response.statusText,
+          status: response.status,
+          detail: body,
+          code: 'unknown',
+        };
+      }
+      throw new ApiError(problem);
     }
 
     return response.json();
@@ -119,25 +229,25 @@ class ApiClient {
     });
   }
 
-  async getUserSubmissions(walletAddress: string): Promise<Submission[]> {
-    return this.request<Submission[]>(`/api/submissions/wallet/${walletAddress}`);
+  async getUserSubmissions(walletAddress: string, params?: SubmissionListParams): Promise<SubmissionPage> {
+    return this.request<SubmissionPage>(`/api/submissions/wallet/${walletAddress}${submissionListQuery(params)}`);
   }
 
-  async getChallengeSubmissions(challengeAddress: string): Promise<Submission[]> {
-    return this.request<Submission[]>(`/api/submissions/challenge/${challengeAddress}`);
+  async getChallengeSubmissions(challengeAddress: string, params?: SubmissionListParams): Promise<SubmissionPage> {
+    return this.request<SubmissionPage>(`/api/submissions/challenge/${challengeAddress}${submissionListQuery(params)}`);
   }
 
   // Leaderboard endpoints
   async getLeaderboard(limit: number = 100): Promise<LeaderboardEntry[]> {
-    return this.request<LeaderboardEntry[]>(`/api/leaderboard?limit=${limit}`);
+    return (await this.request<ListResponse<LeaderboardEntry>>(`/api/leaderboard?limit=${limit}`)).data;
   }
 
   async getTopPerformers(limit: number = 10): Promise<LeaderboardEntry[]> {
-    return this.request<LeaderboardEntry[]>(`/api/leaderboard/top?limit=${limit}`);
+    return (await this.request<ListResponse<LeaderboardEntry>>(`/api/leaderboard/top?limit=${limit}`)).data;
   }
 
-  async getUserStats(walletAddress: string): Promise<LeaderboardEntry> {
-    return this.request<LeaderboardEntry>(`/api/leaderboard/user/${walletAddress}`);
+  async getUserStats(walletAddress: string): Promise<UserStats> {
+    return this.request<UserStats>(`/api/leaderboard/user/${walletAddress}`);
   }
 
   async recordReputationEvent(event: ReputationEvent): Promise<void> {
@@ -148,7 +258,7 @@ class ApiClient {
   }
 
   async getRecentEvents(limit: number = 20): Promise<ReputationEvent[]> {
-    return this.request<ReputationEvent[]>(`/api/leaderboard/events/recent?limit=${limit}`);
+    return (await this.request<ListResponse<ReputationEvent>>(`/api/leaderboard/events/recent?limit=${limit}`)).data;
   }
 
   // Health check
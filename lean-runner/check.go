@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// checkCommand implements
+//
+//	lean-server check [-timeout seconds] <proof.lean> <result.json>
+//
+// which checks one file like /verify and writes the response to
+// result.json instead of serving it. The API's Docker execution path runs
+// it in a throwaway container and copies the result file back out, so the
+// outcome never has to be picked out of the container's logs.
+func checkCommand(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	timeout := flags.Int("timeout", 30, "seconds before lean is stopped, at most 60")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: lean-server check [-timeout seconds] <proof.lean> <result.json>")
+		return 2
+	}
+	if *timeout <= 0 || *timeout > 60 {
+		*timeout = 30
+	}
+
+	resp := checkFile(flags.Arg(0), *timeout)
+	data, err := json.Marshal(resp)
+	if err == nil {
+		err = os.WriteFile(flags.Arg(1), data, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to write result:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File statuses in a project build.
+const (
+	FileBuilt   = "built"
+	FileFailed  = "failed"
+	FileSkipped = "skipped"
+)
+
+// maxProjectFiles is a backstop; the API enforces its own, lower limits
+// before a project gets here.
+const maxProjectFiles = 256
+
+// FileResult is how one file of a project fared in the build.
+type FileResult struct {
+	Path        string       `json:"path"`
+	Module      string       `json:"module"`
+	Status      string       `json:"status"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// Diagnostic is one message from Lean, positioned in a project file. Line
+// is 1-based and Column 0-based, as Lean prints them.
+type Diagnostic struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+var moduleComponent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// modulePath checks that p is a clean, relative path to a .lean file whose
+// directories and name are Lean identifiers, and returns its module name.
+func modulePath(p string) (string, error) {
+	if p == "" || strings.Contains(p, `\`) || path.IsAbs(p) || path.Clean(p) != p {
+		return "", fmt.Errorf("%q is not a clean relative path", p)
+	}
+	if !strings.HasSuffix(p, ".lean") {
+		return "", fmt.Errorf("%q is not a .lean file", p)
+	}
+	if p == "lakefile.lean" {
+		return "", fmt.Errorf("lakefile.lean is generated by the runner")
+	}
+	components := strings.Split(strings.TrimSuffix(p, ".lean"), "/")
+	for _, component := range components {
+		if !moduleComponent.MatchString(component) {
+			return "", fmt.Errorf("%q is not a valid module path", p)
+		}
+	}
+	return strings.Join(components, "."), nil
+}
+
+// verifyProject lays the request's files out in a Lake workspace, builds
+// every module and reports the diagnostics of each file.
+func verifyProject(w http.ResponseWriter, req VerifyRequest, timeout int) {
+	if len(req.Files) > maxProjectFiles {
+		http.Error(w, fmt.Sprintf("Project has more than %d files", maxProjectFiles), http.StatusBadRequest)
+		return
+	}
+
+	modules := make(map[string]string, len(req.Files))
+	for p := range req.Files {
+		module, err := modulePath(p)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		modules[p] = module
+	}
+	entry := req.Entry
+	if entry == "" && len(modules) == 1 {
+		for _, module := range modules {
+			entry = module
+		}
+	}
+	if !containsModule(modules, entry) {
+		http.Error(w, fmt.Sprintf("Entry module %q is not in the project", entry), http.StatusBadRequest)
+		return
+	}
+
+	dir, err := os.MkdirTemp("/tmp", "project_*")
+	if err != nil {
+		respondWithError(w, "Failed to create workspace", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	if err := writeWorkspace(dir, req.Files, modules, entry); err != nil {
+		respondWithError(w, "Failed to write project", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "lake", "build")
+	cmd.Dir = dir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err = cmd.Run()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		respondWithError(w, "timeout", fmt.Sprintf("Project build timed out after %d seconds", timeout))
+		return
+	}
+	var exit *exec.ExitError
+	if err != nil && !errors.As(err, &exit) {
+		respondWithError(w, "error", fmt.Sprintf("Failed to run lake: %v", err))
+		return
+	}
+
+	diagnostics := parseLakeOutput(output.String(), dir, modules)
+	files := make([]FileResult, 0, len(modules))
+	for _, p := range sortedPaths(modules) {
+		file := FileResult{Path: p, Module: modules[p], Status: FileSkipped, Diagnostics: []Diagnostic{}}
+		for _, diagnostic := range diagnostics {
+			if diagnostic.File == p {
+				file.Diagnostics = append(file.Diagnostics, diagnostic)
+				if diagnostic.Severity == "error" {
+					file.Status = FileFailed
+				}
+			}
+		}
+		if file.Status != FileFailed && built(dir, p) {
+			file.Status = FileBuilt
+		}
+		files = append(files, file)
+	}
+
+	resp := VerifyResponse{Status: "success", Files: files}
+	rendered := renderDiagnostics(diagnostics)
+	failed := err != nil
+	for _, file := range files {
+		if file.Status != FileBuilt {
+			failed = true
+		}
+	}
+	switch {
+	case failed && rendered != "":
+		resp.Status, resp.Error = "error", rendered
+	case failed:
+		// Nothing was attributable to a file, so pass on what Lake said.
+		resp.Status, resp.Error = "error", strings.TrimSpace(output.String())
+	case rendered != "":
+		resp.Output = rendered
+	default:
+		resp.Output = "Project built successfully"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func containsModule(modules map[string]string, module string) bool {
+	for _, m := range modules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedPaths(modules map[string]string) []string {
+	paths := make([]string, 0, len(modules))
+	for p := range modules {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// writeWorkspace writes the files and a lakefile whose one library has the
+// entry module as its root and every file as a module, so that files the
+// entry does not import are still built and checked.
+func writeWorkspace(dir string, files, modules map[string]string, entry string) error {
+	globs := make([]string, 0, len(modules))
+	for _, p := range sortedPaths(modules) {
+		globs = append(globs, strconv.Quote(modules[p]))
+
+		target := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, []byte(files[p]), 0o644); err != nil {
+			return err
+		}
+	}
+
+	lakefile := fmt.Sprintf(`name = "proof"
+defaultTargets = ["ProofProject"]
+
+[[lean_lib]]
+name = "ProofProject"
+roots = [%s]
+globs = [%s]
+`, strconv.Quote(entry), strings.Join(globs, ", "))
+	return os.WriteFile(filepath.Join(dir, "lakefile.toml"), []byte(lakefile), 0o644)
+}
+
+// built reports whether Lake produced an .olean for the file. Newer Lake
+// versions put build artifacts under lib/lean.
+func built(dir, p string) bool {
+	olean := filepath.FromSlash(strings.TrimSuffix(p, ".lean") + ".olean")
+	for _, lib := range []string{"lib", filepath.Join("lib", "lean")} {
+		if _, err := os.Stat(filepath.Join(dir, ".lake", "build", lib, olean)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// Lake logs Lean's messages as "error: ./././A/B.lean:3:2: message".
+	lakeMessage = regexp.MustCompile(`^(error|warning|info): (.+?\.lean):(\d+):(\d+): ?(.*)$`)
+	// Lean itself prints "A/B.lean:3:2: error: message".
+	leanMessage = regexp.MustCompile(`^(.+?\.lean):(\d+):(\d+): (error|warning|info): ?(.*)$`)
+	// lakeLine starts any other line of Lake's own: log entries, progress
+	// and the build summary. Such lines end the message before them.
+	lakeLine = regexp.MustCompile(`^(?:(?:error|warning|info|trace): |[✔✖⚠ℹ] \[\d+/\d+\]|Some required builds logged failures:|Build completed successfully)`)
+)
+
+// parseLakeOutput picks Lean's messages out of Lake's build log, with file
+// names made relative to the workspace. Messages about files outside the
+// project are dropped.
+func parseLakeOutput(output, dir string, modules map[string]string) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	var current *Diagnostic
+
+	for _, line := range strings.Split(output, "\n") {
+		var file, severity, message string
+		var lineNumber, column int
+		if match := lakeMessage.FindStringSubmatch(line); match != nil {
+			severity, file, message = match[1], match[2], match[5]
+			// Some Lake versions repeat the severity after the position.
+			message = strings.TrimPrefix(message, severity+": ")
+			lineNumber, _ = strconv.Atoi(match[3])
+			column, _ = strconv.Atoi(match[4])
+		} else if match := leanMessage.FindStringSubmatch(line); match != nil {
+			file, severity, message = match[1], match[4], match[5]
+			lineNumber, _ = strconv.Atoi(match[2])
+			column, _ = strconv.Atoi(match[3])
+		} else {
+			if lakeLine.MatchString(line) {
+				current = nil
+			} else if current != nil {
+				current.Message += "\n" + line
+			}
+			continue
+		}
+
+		current = nil
+		file = projectPath(file, dir)
+		if _, ok := modules[file]; !ok {
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     file,
+			Line:     lineNumber,
+			Column:   column,
+			Severity: severity,
+			Message:  message,
+		})
+		current = &diagnostics[len(diagnostics)-1]
+	}
+
+	for i := range diagnostics {
+		diagnostics[i].Message = strings.TrimRight(diagnostics[i].Message, "\n ")
+	}
+	return diagnostics
+}
+
+// projectPath turns the file name in a message, which Lake prints as
+// ./././A/B.lean or as an absolute path, into the project's A/B.lean.
+func projectPath(file, dir string) string {
+	file = strings.TrimPrefix(file, dir+"/")
+	for strings.HasPrefix(file, "./") {
+		file = file[2:]
+	}
+	return file
+}
+
+// renderDiagnostics prints diagnostics the way Lean does, so clients that
+// parse plain Lean output read project results too.
+func renderDiagnostics(diagnostics []Diagnostic) string {
+	var b strings.Builder
+	for _, d := range diagnostics {
+		fmt.Fprintf(&b, "%s:%d:%d: %s: %s\n", d.File, d.Line, d.Column, d.Severity, d.Message)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -16,12 +17,18 @@ import (
 type VerifyRequest struct {
 	Code    string `json:"code"`
 	Timeout int    `json:"timeout"`
+	// Files, keyed by relative path, make a multi-file project built
+	// with Lake instead of Code; Entry names its root module.
+	Files map[string]string `json:"files,omitempty"`
+	Entry string            `json:"entry,omitempty"`
 }
 
 type VerifyResponse struct {
 	Status string `json:"status"`
 	Output string `json:"output"`
 	Error  string `json:"error"`
+	// Files is set for projects, in path order.
+	Files []FileResult `json:"files,omitempty"`
 }
 
 func verifyHandler(w http.ResponseWriter, r *http.Request) {
@@ -42,6 +49,11 @@ func verifyHandler(w http.ResponseWriter, r *http.Request) {
 		timeout = req.Timeout
 	}
 
+	if len(req.Files) > 0 {
+		verifyProject(w, req, timeout)
+		return
+	}
+
 	// Create temporary file for the proof
 	tmpFile, err := os.CreateTemp("/tmp", "proof_*.lean")
 	if err != nil {
@@ -57,46 +69,50 @@ func verifyHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	tmpFile.Close()
 
+	resp := checkFile(tmpFile.Name(), timeout)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// checkFile runs lean on one file and classifies the outcome. The HTTP
+// handler and the check command share it.
+func checkFile(path string, timeout int) VerifyResponse {
 	// Run lean with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "lean", tmpFile.Name())
+	cmd := exec.CommandContext(ctx, "lean", path)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err = cmd.Run()
-	
+	err := cmd.Run()
+
 	// Process results
 	if ctx.Err() == context.DeadlineExceeded {
-		respondWithError(w, "timeout", fmt.Sprintf("Proof verification timed out after %d seconds", timeout))
-		return
+		return VerifyResponse{Status: "timeout", Error: fmt.Sprintf("Proof verification timed out after %d seconds", timeout)}
 	}
 
 	output := stdout.String()
 	errOutput := stderr.String()
-	
-	if err != nil {
-		// Check if there are compilation/verification errors
-		if errOutput != "" {
-			respondWithError(w, "error", errOutput)
-		} else if output != "" && strings.Contains(output, "error") {
-			respondWithError(w, "error", output)
-		} else {
-			respondWithError(w, "error", fmt.Sprintf("Verification failed: %v", err))
-		}
-		return
+	combinedOutput := output
+	if errOutput != "" {
+		combinedOutput += "\n" + errOutput
 	}
 
-	// Success - check for any error messages in output
-	if strings.Contains(output, "error") || strings.Contains(errOutput, "error") {
-		combinedOutput := output
-		if errOutput != "" {
-			combinedOutput += "\n" + errOutput
+	// Lean exits non-zero when a file has errors. The output cannot be
+	// searched for the word "error": #print axioms echoes theorem names,
+	// and a theorem may well be called error_bound. Only a message Lean
+	// reports with error severity counts.
+	if err != nil {
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) || strings.TrimSpace(combinedOutput) == "" {
+			return VerifyResponse{Status: "error", Error: fmt.Sprintf("Verification failed: %v", err)}
 		}
-		respondWithError(w, "error", combinedOutput)
-		return
+		return VerifyResponse{Status: "error", Error: combinedOutput}
+	}
+	if hasErrorMessage(combinedOutput) {
+		return VerifyResponse{Status: "error", Error: combinedOutput}
 	}
 
 	// Proof verified successfully
@@ -105,13 +121,21 @@ func verifyHandler(w http.ResponseWriter, r *http.Request) {
 		Output: "Proof verified successfully",
 		Error:  "",
 	}
-	
+
 	if output != "" {
 		resp.Output = output
 	}
+	return resp
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+// hasErrorMessage reports whether Lean printed a message of error severity.
+func hasErrorMessage(output string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		if match := leanMessage.FindStringSubmatch(line); match != nil && match[4] == "error" {
+			return true
+		}
+	}
+	return false
 }
 
 func respondWithError(w http.ResponseWriter, status, errorMsg string) {
@@ -131,6 +155,10 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(checkCommand(os.Args[2:]))
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"